@@ -0,0 +1,103 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gateway_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sjy-dv/nnv/gateway"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/auth"
+	"github.com/sjy-dv/nnv/server"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestForwardedIdentity(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	listeners := make(map[string]*bufconn.Listener)
+	dialer := grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		lis, ok := listeners[strings.TrimPrefix(addr, "passthrough:///")]
+		if !ok {
+			return nil, fmt.Errorf("unknown address %s", addr)
+		}
+		return lis.DialContext(ctx)
+	})
+	// the nodes only let alice read, the gateway would let her write
+	nodeAuth := &auth.Config{
+		Authenticators: []auth.Authenticator{auth.APIKeys{"gateway-key": "gateway"}},
+		Policy: auth.Policy{
+			"gateway": {auth.Any: auth.Read},
+			"admin":   {auth.Any: auth.Admin},
+			"alice":   {"docs": auth.Read},
+		},
+		Forwarders: []string{"gateway"},
+	}
+	var addrs []string
+	for i := 0; i < 2; i++ {
+		node, err := server.New(server.Config{DataDir: t.TempDir(), Stable: true, Auth: nodeAuth})
+		require.NoError(t, err)
+		addr := fmt.Sprintf("node-%d", i)
+		listeners[addr] = bufconn.Listen(1 << 20)
+		go node.Serve(listeners[addr])
+		t.Cleanup(func() { node.Close() })
+		addrs = append(addrs, "passthrough:///"+addr)
+	}
+	gw, err := gateway.New(gateway.GateWay{ServerAddrs: addrs, Balancer: gateway.LB, Auth: &auth.Config{
+		Authenticators: []auth.Authenticator{auth.APIKeys{"admin-key": "admin", "alice-key": "alice"}},
+		Policy: auth.Policy{
+			"admin": {auth.Any: auth.Admin},
+			"alice": {"docs": auth.Write},
+		},
+	}}, dialer, grpc.WithPerRPCCredentials(auth.APIKey("gateway-key")))
+	require.NoError(t, err)
+	listeners["gateway"] = bufconn.Listen(1 << 20)
+	go gw.Serve(listeners["gateway"])
+	t.Cleanup(gw.Close)
+	conn, err := grpc.NewClient("passthrough:///gateway", dialer, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewLBCoordinatorClient(conn)
+	as := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), auth.AuthorizationKey, "Bearer "+key)
+	}
+
+	_, err = client.CreateCollection(context.Background(), &pb.Collection{CollectionName: "docs", Dimension: 2})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	// the gateway itself may only read, the backends act as admin
+	created, err := client.CreateCollection(as("admin-key"), &pb.Collection{CollectionName: "docs", Dimension: 2})
+	require.NoError(t, err)
+	require.True(t, created.GetResponse().GetResult(), created.GetResponse().GetErrorMessage())
+
+	_, err = client.Insert(as("alice-key"), &pb.ModifyDataset{Id: uuid.NewString(), CollectionName: "docs", Vector: []float32{1, 1}})
+	requireCode(t, err, pb.ErrorCode_PERMISSION_DENIED)
+	search, err := client.Search(as("alice-key"), &pb.SearchReq{CollectionName: "docs", Vector: []float32{1, 1}, TopK: 1})
+	require.NoError(t, err)
+	require.True(t, search.GetResult(), search.GetErrorMessage())
+}
@@ -0,0 +1,370 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/errcode"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type backend struct {
+	addr    string
+	conn    *grpc.ClientConn
+	client  pb.LBCoordinatorClient
+	breaker *breaker
+}
+
+func dialBackend(addr string, policy healthPolicy, opts ...grpc.DialOption) (*backend, error) {
+	brk := newBreaker(addr, policy)
+	conn, err := grpc.NewClient(addr, append(opts, brk.interceptors()...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial backend %s: %w", addr, err)
+	}
+	return &backend{
+		addr:    addr,
+		conn:    conn,
+		client:  pb.NewLBCoordinatorClient(conn),
+		breaker: brk,
+	}, nil
+}
+
+func dialBackends(addrs []string, policy healthPolicy, opts ...grpc.DialOption) ([]*backend, error) {
+	backends := make([]*backend, 0, len(addrs))
+	for _, addr := range addrs {
+		b, err := dialBackend(addr, policy, opts...)
+		if err != nil {
+			closeBackends(backends)
+			return nil, err
+		}
+		backends = append(backends, b)
+	}
+	return backends, nil
+}
+
+func closeBackends(backends []*backend) {
+	for _, b := range backends {
+		b.conn.Close()
+	}
+}
+
+type result struct {
+	backend *backend
+	resp    *pb.Response
+	err     error
+}
+
+func (r result) ok() bool {
+	return r.err == nil && r.resp.GetResult()
+}
+
+// fanOut runs call against every backend in parallel, results are in backend
+// order.
+func fanOut(ctx context.Context, backends []*backend, call func(context.Context, *backend) (*pb.Response, error)) []result {
+	results := make([]result, len(backends))
+	var wg sync.WaitGroup
+	wg.Add(len(backends))
+	for i, b := range backends {
+		go func(i int, b *backend) {
+			defer wg.Done()
+			resp, err := call(ctx, b)
+			results[i] = result{backend: b, resp: resp, err: err}
+		}(i, b)
+	}
+	wg.Wait()
+	return results
+}
+
+// gather runs call against every backend in parallel and returns the values in
+// backend order, or the first error in backend order.
+func gather[T any](ctx context.Context, backends []*backend, call func(context.Context, *backend) (T, error)) ([]T, error) {
+	values := make([]T, len(backends))
+	errs := make([]error, len(backends))
+	var wg sync.WaitGroup
+	wg.Add(len(backends))
+	for i, b := range backends {
+		go func(i int, b *backend) {
+			defer wg.Done()
+			values[i], errs[i] = call(ctx, b)
+		}(i, b)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func allOk(results []result) bool {
+	for _, r := range results {
+		if !r.ok() {
+			return false
+		}
+	}
+	return true
+}
+
+// failure reports the first failed backend, transport failures and
+// rejections by the backend are told apart by the error code.
+func failure(results []result) error {
+	for _, r := range results {
+		switch {
+		case r.err != nil:
+			return shardFailure(r.backend, r.err)
+		case !r.resp.GetResult():
+			return rejected(r.backend, r.resp.GetErrorCode(), r.resp.GetErrorMessage())
+		}
+	}
+	return nil
+}
+
+// respond answers an RPC, a failed one with its status.
+func respond(err error) (*pb.Response, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Response{Result: true}, nil
+}
+
+// errResponse is the answer to a failed item of a stream, the stream itself
+// goes on.
+func errResponse(err error) *pb.Response {
+	return &pb.Response{
+		Result:       false,
+		ErrorMessage: err.Error(),
+		ErrorCode:    rpcErrCode(err),
+	}
+}
+
+// shardCode is the code of a request a shard rejected. Shards that did not
+// tell why are reported as COMMUNICATION_SHARD_ERROR.
+func shardCode(code pb.ErrorCode) pb.ErrorCode {
+	if code == pb.ErrorCode_UNDEFINED {
+		return pb.ErrorCode_COMMUNICATION_SHARD_ERROR
+	}
+	return code
+}
+
+// rpcErrCode is the code of a failed call. A status the shard answered with
+// keeps its code, so do calls the caller gave up on, anything else means the
+// shard could not be reached.
+func rpcErrCode(err error) pb.ErrorCode {
+	if code, ok := errcode.FromError(err); ok {
+		return code
+	}
+	code := status.Code(err)
+	if code == codes.Unknown {
+		code = status.FromContextError(err).Code()
+	}
+	switch code {
+	case codes.Canceled:
+		return pb.ErrorCode_CANCELLED
+	case codes.DeadlineExceeded:
+		return pb.ErrorCode_DEADLINE_EXCEEDED
+	}
+	return pb.ErrorCode_COMMUNICATION_SHARD_RPC_ERROR
+}
+
+// shardError is a failed call to a shard, its status keeps the code of the
+// shard and names it.
+type shardError struct {
+	addr string
+	code pb.ErrorCode
+	msg  string
+}
+
+func rejected(b *backend, code pb.ErrorCode, msg string) error {
+	return &shardError{addr: b.addr, code: shardCode(code), msg: msg}
+}
+
+// shardFailure is the error of a call to a shard, a status the shard
+// answered with keeps its code.
+func shardFailure(b *backend, err error) error {
+	if code, ok := errcode.FromError(err); ok {
+		return rejected(b, code, status.Convert(err).Message())
+	}
+	return &shardError{addr: b.addr, code: rpcErrCode(err), msg: err.Error()}
+}
+
+func (e *shardError) Error() string {
+	return fmt.Sprintf("%s: %s", e.addr, e.msg)
+}
+
+func (e *shardError) GRPCStatus() *status.Status {
+	return errcode.Status(e.code, e.Error(), map[string]string{"shard": e.addr})
+}
+
+// rollback applies compensate on the replicas that accepted a write which
+// failed elsewhere and returns why it failed. It runs even if the caller has
+// gone away. Replicas that cannot be undone keep the write, which is then
+// reported as REPLICATION_FAILED.
+func rollback(ctx context.Context, results []result, compensate func(context.Context, *backend) (*pb.Response, error)) error {
+	ctx = context.WithoutCancel(ctx)
+	var diverged []string
+	for _, res := range results {
+		if !res.ok() {
+			continue
+		}
+		resp, err := compensate(ctx, res.backend)
+		if err == nil && !resp.GetResult() {
+			err = errors.New(resp.GetErrorMessage())
+		}
+		if err != nil {
+			log.Error().Err(err).Str("backend", res.backend.addr).Msg("rollback failed, replica diverged")
+			diverged = append(diverged, res.backend.addr)
+		}
+	}
+	cause := failure(results)
+	if len(diverged) == 0 {
+		return cause
+	}
+	return errcode.Error(pb.ErrorCode_REPLICATION_FAILED,
+		fmt.Sprintf("%v, not undone on %s", cause, strings.Join(diverged, ", ")),
+		map[string]string{"diverged": strings.Join(diverged, ",")})
+}
+
+// createCollection creates the collection on every backend, it is dropped
+// again from the backends that created it if any of them fails.
+func createCollection(ctx context.Context, backends []*backend, req *pb.Collection) (*pb.CollectionResponse, error) {
+	var created atomic.Pointer[pb.Collection]
+	results := fanOut(ctx, backends, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		resp, err := b.client.CreateCollection(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.GetResponse().GetResult() {
+			created.CompareAndSwap(nil, resp.GetCollection())
+		}
+		return resp.GetResponse(), nil
+	})
+	if allOk(results) {
+		return &pb.CollectionResponse{Response: &pb.Response{Result: true}, Collection: created.Load()}, nil
+	}
+	return nil, rollback(ctx, results, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.DropCollection(ctx, &pb.CollectionName{CollectionName: req.GetCollectionName()})
+	})
+}
+
+// dropCollection cannot be rolled back, a partial failure is reported so the
+// drop can be retried.
+func dropCollection(ctx context.Context, backends []*backend, req *pb.CollectionName) (*pb.Response, error) {
+	results := fanOut(ctx, backends, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.DropCollection(ctx, req)
+	})
+	return respond(failure(results))
+}
+
+// createAlias creates the alias on every backend, it is deleted again from
+// the backends that created it if any of them fails.
+func createAlias(ctx context.Context, backends []*backend, req *pb.Alias) (*pb.Response, error) {
+	results := fanOut(ctx, backends, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.CreateAlias(ctx, req)
+	})
+	if allOk(results) {
+		return respond(nil)
+	}
+	return respond(rollback(ctx, results, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.DeleteAlias(ctx, &pb.AliasName{Alias: req.GetAlias()})
+	}))
+}
+
+// pointAlias points the alias on every backend, the backends that followed
+// are pointed back to their previous collection if any of them fails. The
+// alias is deleted again from those it was not on before.
+func pointAlias(ctx context.Context, backends []*backend, req *pb.Alias) (*pb.Response, error) {
+	lists, err := gather(ctx, backends, func(ctx context.Context, b *backend) (*pb.AliasList, error) {
+		list, err := b.client.ListAliases(ctx, &emptypb.Empty{})
+		if err != nil {
+			err = shardFailure(b, err)
+		}
+		return list, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	previous := make(map[*backend]string, len(backends))
+	for i, list := range lists {
+		for _, alias := range list.GetAliases() {
+			if alias.GetAlias() == req.GetAlias() {
+				previous[backends[i]] = alias.GetCollectionName()
+			}
+		}
+	}
+	results := fanOut(ctx, backends, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.PointAlias(ctx, req)
+	})
+	if allOk(results) {
+		return respond(nil)
+	}
+	return respond(rollback(ctx, results, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		collectionName, ok := previous[b]
+		if !ok {
+			return b.client.DeleteAlias(ctx, &pb.AliasName{Alias: req.GetAlias()})
+		}
+		return b.client.PointAlias(ctx, &pb.Alias{Alias: req.GetAlias(), CollectionName: collectionName})
+	}))
+}
+
+// swapAliases swaps on every backend, a second swap undoes the backends that
+// swapped if any of them fails.
+func swapAliases(ctx context.Context, backends []*backend, req *pb.SwapAliasesReq) (*pb.Response, error) {
+	swap := func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.SwapAliases(ctx, req)
+	}
+	results := fanOut(ctx, backends, swap)
+	if allOk(results) {
+		return respond(nil)
+	}
+	return respond(rollback(ctx, results, swap))
+}
+
+// deleteAlias is not rolled back, like dropCollection.
+func deleteAlias(ctx context.Context, backends []*backend, req *pb.AliasName) (*pb.Response, error) {
+	results := fanOut(ctx, backends, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.DeleteAlias(ctx, req)
+	})
+	return respond(failure(results))
+}
+
+// pointLocks serialises writes to the same point so every backend applies
+// them, and their rollbacks, in the same order.
+type pointLocks [256]sync.Mutex
+
+func (l *pointLocks) lock(collectionName, id string) func() {
+	h := fnv.New32a()
+	h.Write([]byte(collectionName))
+	h.Write([]byte{0})
+	h.Write([]byte(id))
+	mu := &l[h.Sum32()%uint32(len(l))]
+	mu.Lock()
+	return mu.Unlock
+}
@@ -0,0 +1,98 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// aliasClient keeps the aliases of a backend in memory. Points fail while
+// failing is set, and an alias missing from the list is created by a point,
+// as if it was created between the two.
+type aliasClient struct {
+	pb.LBCoordinatorClient
+	mu      sync.Mutex
+	aliases map[string]string
+	failing bool
+}
+
+func (a *aliasClient) ListAliases(context.Context, *emptypb.Empty, ...grpc.CallOption) (*pb.AliasList, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	list := &pb.AliasList{}
+	for alias, collectionName := range a.aliases {
+		list.Aliases = append(list.Aliases, &pb.Alias{Alias: alias, CollectionName: collectionName})
+	}
+	return list, nil
+}
+
+func (a *aliasClient) PointAlias(_ context.Context, req *pb.Alias, _ ...grpc.CallOption) (*pb.Response, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.failing || req.GetCollectionName() == "" {
+		return nil, status.Error(codes.NotFound, "collection not found")
+	}
+	a.aliases[req.GetAlias()] = req.GetCollectionName()
+	return &pb.Response{Result: true}, nil
+}
+
+func (a *aliasClient) DeleteAlias(_ context.Context, req *pb.AliasName, _ ...grpc.CallOption) (*pb.Response, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.aliases[req.GetAlias()]; !ok {
+		return nil, status.Error(codes.NotFound, "alias not found")
+	}
+	delete(a.aliases, req.GetAlias())
+	return &pb.Response{Result: true}, nil
+}
+
+func TestPointAliasRollback(t *testing.T) {
+	ctx := context.Background()
+	clients := []*aliasClient{
+		{aliases: map[string]string{"live": "docs"}},
+		{aliases: map[string]string{}},
+		{aliases: map[string]string{"live": "docs"}, failing: true},
+	}
+	backends := make([]*backend, len(clients))
+	for i, client := range clients {
+		backends[i] = &backend{addr: fmt.Sprintf("node-%d", i), client: client}
+	}
+	_, err := pointAlias(ctx, backends, &pb.Alias{Alias: "live", CollectionName: "docs-v2"})
+	require.Error(t, err)
+	// the backend the alias was on points back, the other one loses it
+	require.Equal(t, map[string]string{"live": "docs"}, clients[0].aliases)
+	require.Empty(t, clients[1].aliases)
+	require.Equal(t, map[string]string{"live": "docs"}, clients[2].aliases)
+
+	// a fresh alias is deleted from the backends that got it
+	_, err = pointAlias(ctx, backends, &pb.Alias{Alias: "next", CollectionName: "docs-v2"})
+	require.Error(t, err)
+	for _, client := range clients {
+		require.NotContains(t, client.aliases, "next")
+	}
+}
@@ -17,6 +17,14 @@
 
 package gateway
 
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/sjy-dv/nnv/pkg/auth"
+	"github.com/sjy-dv/nnv/pkg/sharding"
+)
+
 type GateWay struct {
 	Host        string
 	Port        string
@@ -24,7 +32,21 @@ type GateWay struct {
 
 	Balancer BalancerType
 	Infra    Infra
+	// SLB only, how points are spread over ServerAddrs
+	Placement sharding.PlacementType
 
 	// when k8s
 	ServiceName string
+
+	// how often every backend is pinged, DefaultHealthCheckInterval if unset
+	HealthCheckInterval time.Duration
+	// consecutive failed calls that open the circuit of a backend,
+	// DefaultFailureThreshold if unset
+	FailureThreshold int
+
+	// authenticates and authorizes callers, whose principal is forwarded to
+	// the backends. nil serves everyone
+	Auth *auth.Config
+	// serves callers over TLS when set
+	TLS *tls.Config
 }
@@ -0,0 +1,602 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gateway_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sjy-dv/nnv/gateway"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/errcode"
+	"github.com/sjy-dv/nnv/pkg/tenant"
+	"github.com/sjy-dv/nnv/server"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type cluster struct {
+	gateway pb.LBCoordinatorClient
+	nodes   []pb.LBCoordinatorClient
+	servers []*server.Server
+	addrs   []string
+}
+
+// startCluster runs count nodes and a gateway in front of the first serving
+// ones, everything talks over in-memory listeners.
+func startCluster(t *testing.T, count, serving int, balancer gateway.BalancerType) *cluster {
+	t.Helper()
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	c := &cluster{}
+	listeners := make(map[string]*bufconn.Listener)
+	dialer := grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		lis, ok := listeners[strings.TrimPrefix(addr, "passthrough:///")]
+		if !ok {
+			return nil, fmt.Errorf("unknown address %s", addr)
+		}
+		return lis.DialContext(ctx)
+	})
+	dial := func(addr string) pb.LBCoordinatorClient {
+		conn, err := grpc.NewClient("passthrough:///"+addr, dialer, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return pb.NewLBCoordinatorClient(conn)
+	}
+	for i := 0; i < count; i++ {
+		node, err := server.New(server.Config{DataDir: t.TempDir(), Stable: true})
+		require.NoError(t, err)
+		addr := fmt.Sprintf("node-%d", i)
+		listeners[addr] = bufconn.Listen(1 << 20)
+		go node.Serve(listeners[addr])
+		t.Cleanup(func() { node.Close() })
+		c.addrs = append(c.addrs, "passthrough:///"+addr)
+		c.nodes = append(c.nodes, dial(addr))
+		c.servers = append(c.servers, node)
+	}
+	gw, err := gateway.New(gateway.GateWay{ServerAddrs: c.addrs[:serving], Balancer: balancer}, dialer)
+	require.NoError(t, err)
+	listeners["gateway"] = bufconn.Listen(1 << 20)
+	go gw.Serve(listeners["gateway"])
+	t.Cleanup(gw.Close)
+	c.gateway = dial("gateway")
+
+	resp, err := c.gateway.CreateCollection(context.Background(), &pb.Collection{
+		CollectionName: "docs",
+		Dimension:      2,
+		InvertedIndex:  []string{"category"},
+	})
+	require.NoError(t, err)
+	require.True(t, resp.GetResponse().GetResult(), resp.GetResponse().GetErrorMessage())
+	return c
+}
+
+func insert(client pb.LBCoordinatorClient, id string, vector []float32) error {
+	_, err := client.Insert(context.Background(), &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: vector})
+	return err
+}
+
+// requireCode checks that an RPC failed with the status of code.
+func requireCode(t *testing.T, err error, code pb.ErrorCode) {
+	t.Helper()
+	got, ok := errcode.FromError(err)
+	require.True(t, ok, err)
+	require.Equal(t, code, got, err)
+}
+
+func getPoint(t *testing.T, client pb.LBCoordinatorClient, id string) *pb.Row {
+	t.Helper()
+	resp, err := client.GetPoints(context.Background(), &pb.PointIds{CollectionName: "docs", Ids: []string{id}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	if len(resp.GetPoints()) == 0 {
+		return nil
+	}
+	return resp.GetPoints()[0]
+}
+
+func mustUnpack(t *testing.T, a *anypb.Any) proto.Message {
+	t.Helper()
+	msg, err := a.UnmarshalNew()
+	require.NoError(t, err)
+	return msg
+}
+
+func TestReplicaWrites(t *testing.T) {
+	c := startCluster(t, 3, 3, gateway.LB)
+	ctx := context.Background()
+
+	ids := make([]string, 10)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+		require.NoError(t, insert(c.gateway, ids[i], []float32{float32(i), 0}))
+	}
+	for _, node := range c.nodes {
+		col, err := node.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+		require.NoError(t, err)
+		require.EqualValues(t, len(ids), col.GetCollectionSize())
+	}
+	// every replica keeps the version the gateway stamped
+	for _, id := range ids {
+		want := getPoint(t, c.nodes[0], id)
+		require.NotZero(t, want.GetVersion())
+		for _, node := range c.nodes[1:] {
+			row := getPoint(t, node, id)
+			require.Equal(t, want.GetVersion(), row.GetVersion())
+			require.Equal(t, want.GetOrigin(), row.GetOrigin())
+		}
+	}
+
+	// A replica that already has the point rejects the insert, the others
+	// drop it again
+	conflict := uuid.NewString()
+	require.NoError(t, insert(c.nodes[2], conflict, []float32{9, 9}))
+	requireCode(t, insert(c.gateway, conflict, []float32{1, 1}), pb.ErrorCode_ALREADY_EXISTS)
+	require.Nil(t, getPoint(t, c.nodes[0], conflict))
+	require.Nil(t, getPoint(t, c.nodes[1], conflict))
+	require.Equal(t, []float32{9, 9}, getPoint(t, c.nodes[2], conflict).GetVector())
+
+	// An update that one replica cannot apply is undone on the others
+	delResp, err := c.nodes[1].Delete(ctx, &pb.DeleteDataset{Id: ids[0], CollectionName: "docs"})
+	require.NoError(t, err)
+	require.True(t, delResp.GetResult(), delResp.GetErrorMessage())
+	_, err = c.gateway.Update(ctx, &pb.ModifyDataset{Id: ids[0], CollectionName: "docs", Vector: []float32{50, 50}})
+	requireCode(t, err, pb.ErrorCode_NOT_FOUND)
+	require.Equal(t, []float32{0, 0}, getPoint(t, c.nodes[0], ids[0]).GetVector())
+	require.Equal(t, []float32{0, 0}, getPoint(t, c.nodes[2], ids[0]).GetVector())
+
+	// Every replica applies the same patch
+	views, err := anypb.New(wrapperspb.Int64(3))
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		resp, err := c.gateway.PatchMetadata(ctx, &pb.PatchMetadataReq{
+			Id:             ids[2],
+			CollectionName: "docs",
+			Increment:      map[string]*anypb.Any{"views": views},
+		})
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	}
+	want := getPoint(t, c.nodes[0], ids[2])
+	require.Equal(t, []float32{2, 0}, want.GetVector())
+	for _, node := range c.nodes {
+		row := getPoint(t, node, ids[2])
+		require.Equal(t, want.GetVersion(), row.GetVersion())
+		require.True(t, proto.Equal(wrapperspb.Int64(6), mustUnpack(t, row.GetMetadata()["views"])))
+	}
+
+	// A failed precondition keeps its code through the gateway
+	_, err = c.gateway.Update(ctx, &pb.ModifyDataset{
+		Id:             ids[2],
+		CollectionName: "docs",
+		Vector:         []float32{7, 7},
+		Precondition:   &pb.Precondition{IfVersionEquals: proto.Uint64(want.GetVersion() - 1)},
+	})
+	requireCode(t, err, pb.ErrorCode_PRECONDITION_FAILED)
+	resp, err := c.gateway.Update(ctx, &pb.ModifyDataset{
+		Id:             ids[2],
+		CollectionName: "docs",
+		Vector:         []float32{7, 7},
+		Precondition:   &pb.Precondition{IfVersionEquals: proto.Uint64(want.GetVersion())},
+	})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	for _, node := range c.nodes {
+		require.Equal(t, []float32{7, 7}, getPoint(t, node, ids[2]).GetVector())
+	}
+
+	// Deletes reach every replica
+	resp, err = c.gateway.Delete(ctx, &pb.DeleteDataset{Id: ids[1], CollectionName: "docs"})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	for _, node := range c.nodes {
+		require.Nil(t, getPoint(t, node, ids[1]))
+	}
+}
+
+func TestReplicaReadFailover(t *testing.T) {
+	c := startCluster(t, 3, 3, gateway.LB)
+	ctx := context.Background()
+	ids := make([]string, 5)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+		require.NoError(t, insert(c.gateway, ids[i], []float32{float32(i), 0}))
+	}
+
+	require.NoError(t, c.servers[0].Close())
+	for i := 0; i < 6; i++ {
+		search, err := c.gateway.Search(ctx, &pb.SearchReq{CollectionName: "docs", Vector: []float32{0, 0}, TopK: 2})
+		require.NoError(t, err)
+		require.True(t, search.GetResult(), search.GetErrorMessage())
+		require.Len(t, search.GetResponse(), 2)
+		require.Equal(t, ids[0], search.GetResponse()[0].GetId())
+	}
+	list, err := c.gateway.ListCollection(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, list.GetCount())
+
+	// Writes need every replica
+	requireCode(t, insert(c.gateway, uuid.NewString(), []float32{1, 1}), pb.ErrorCode_COMMUNICATION_SHARD_RPC_ERROR)
+}
+
+func TestShardWritesAndSearch(t *testing.T) {
+	c := startCluster(t, 3, 3, gateway.SLB)
+	ctx := context.Background()
+
+	ids := make([]string, 30)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+		require.NoError(t, insert(c.gateway, ids[i], []float32{float32(i), 0}))
+	}
+	// Every point lives on exactly one shard
+	var total uint64
+	for _, node := range c.nodes {
+		col, err := node.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+		require.NoError(t, err)
+		require.Less(t, col.GetCollectionSize(), uint64(len(ids)))
+		total += col.GetCollectionSize()
+	}
+	require.EqualValues(t, len(ids), total)
+	col, err := c.gateway.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+	require.NoError(t, err)
+	require.EqualValues(t, len(ids), col.GetCollectionSize())
+
+	search, err := c.gateway.Search(ctx, &pb.SearchReq{CollectionName: "docs", Vector: []float32{0, 0}, TopK: 5})
+	require.NoError(t, err)
+	require.True(t, search.GetResult(), search.GetErrorMessage())
+	require.Len(t, search.GetResponse(), 5)
+	for i, row := range search.GetResponse() {
+		require.Equal(t, ids[i], row.GetId())
+	}
+	batch, err := c.gateway.BatchSearch(ctx, &pb.BatchSearchReq{CollectionName: "docs", Searches: []*pb.SearchReq{
+		{Vector: []float32{0, 0}, TopK: 5},
+		{Vector: []float32{29, 0}, TopK: 2},
+		{Vector: []float32{1}},
+	}})
+	require.NoError(t, err)
+	require.True(t, batch.GetResult(), batch.GetErrorMessage())
+	require.Len(t, batch.GetResponses(), 3)
+	for i, row := range batch.GetResponses()[0].GetResponse() {
+		require.Equal(t, ids[i], row.GetId())
+	}
+	require.Len(t, batch.GetResponses()[1].GetResponse(), 2)
+	require.Equal(t, ids[29], batch.GetResponses()[1].GetResponse()[0].GetId())
+	require.False(t, batch.GetResponses()[2].GetResult())
+	require.Equal(t, pb.ErrorCode_DIMENSION_MISMATCH, batch.GetResponses()[2].GetErrorCode())
+
+	// min_score 0.1 keeps the points within squared distance 9
+	search, err = c.gateway.Search(ctx, &pb.SearchReq{CollectionName: "docs", Vector: []float32{0, 0}, TopK: 10, MinScore: 0.1})
+	require.NoError(t, err)
+	require.True(t, search.GetResult(), search.GetErrorMessage())
+	require.Len(t, search.GetResponse(), 4)
+
+	points, err := c.gateway.GetPoints(ctx, &pb.PointIds{CollectionName: "docs", Ids: []string{ids[7], uuid.NewString(), ids[3]}})
+	require.NoError(t, err)
+	require.True(t, points.GetResult(), points.GetErrorMessage())
+	require.Len(t, points.GetPoints(), 2)
+	require.Equal(t, ids[7], points.GetPoints()[0].GetId())
+	require.Equal(t, ids[3], points.GetPoints()[1].GetId())
+
+	resp, err := c.gateway.Delete(ctx, &pb.DeleteDataset{Id: ids[0], CollectionName: "docs"})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	_, err = c.gateway.Delete(ctx, &pb.DeleteDataset{Id: "not-a-uuid", CollectionName: "docs"})
+	requireCode(t, err, pb.ErrorCode_INVALID_ARGUMENT)
+
+	// The codes of the shards reach the client
+	requireCode(t, insert(c.gateway, ids[1], []float32{1, 0}), pb.ErrorCode_ALREADY_EXISTS)
+	_, err = c.gateway.Search(ctx, &pb.SearchReq{CollectionName: "missing", Vector: []float32{0, 0}, TopK: 1})
+	requireCode(t, err, pb.ErrorCode_NOT_FOUND)
+	_, err = c.gateway.GetCollection(ctx, &pb.CollectionName{CollectionName: "missing"})
+	requireCode(t, err, pb.ErrorCode_NOT_FOUND)
+	col, err = c.gateway.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+	require.NoError(t, err)
+	require.EqualValues(t, len(ids)-1, col.GetCollectionSize())
+}
+
+func TestShardScrollAndCount(t *testing.T) {
+	c := startCluster(t, 3, 3, gateway.SLB)
+	ctx := context.Background()
+	ids := make([]string, 25)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+		require.NoError(t, insert(c.gateway, ids[i], []float32{float32(i), 0}))
+	}
+	slices.Sort(ids)
+
+	// the pages of the shards merge into one id order
+	var got []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 4)
+		resp, err := c.gateway.Scroll(ctx, &pb.ScrollReq{CollectionName: "docs", Cursor: cursor, Limit: 7})
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+		require.LessOrEqual(t, len(resp.GetPoints()), 7)
+		for _, row := range resp.GetPoints() {
+			got = append(got, row.GetId())
+		}
+		if cursor = resp.GetNextCursor(); cursor == "" {
+			break
+		}
+	}
+	require.Equal(t, ids, got)
+
+	count, err := c.gateway.Count(ctx, &pb.CountReq{CollectionName: "docs"})
+	require.NoError(t, err)
+	require.True(t, count.GetResult(), count.GetErrorMessage())
+	require.EqualValues(t, len(ids), count.GetCount())
+}
+
+func TestShardBatchStream(t *testing.T) {
+	c := startCluster(t, 3, 3, gateway.SLB)
+	ctx := context.Background()
+
+	stream, err := c.gateway.BatchInsert(ctx)
+	require.NoError(t, err)
+	ids := make([]string, 100)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+		if i == 50 {
+			ids[i] = "not-a-uuid"
+		}
+		require.NoError(t, stream.Send(&pb.ModifyDataset{Id: ids[i], CollectionName: "docs", Vector: []float32{float32(i), 0}}))
+	}
+	// Inserting the first point again fails on its shard only
+	require.NoError(t, stream.Send(&pb.ModifyDataset{Id: ids[0], CollectionName: "docs", Vector: []float32{0, 0}}))
+	require.NoError(t, stream.CloseSend())
+	// Answers come back in the order the items were sent
+	for i := range ids {
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, i != 50, resp.GetResult(), fmt.Sprint(i, resp.GetErrorMessage()))
+	}
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.False(t, resp.GetResult())
+
+	col, err := c.gateway.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+	require.NoError(t, err)
+	require.EqualValues(t, len(ids)-1, col.GetCollectionSize())
+}
+
+func TestShardReshard(t *testing.T) {
+	c := startCluster(t, 4, 3, gateway.SLB)
+	ctx := context.Background()
+	ids := make([]string, 300)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+		require.NoError(t, insert(c.gateway, ids[i], []float32{float32(i), 0}))
+	}
+	sizes := func() []uint64 {
+		sizes := make([]uint64, len(c.nodes))
+		for i, node := range c.nodes {
+			col, err := node.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+			if err == nil {
+				sizes[i] = col.GetCollectionSize()
+			}
+		}
+		return sizes
+	}
+
+	// Searches see every point exactly once and updates are not lost while
+	// points move
+	stop := make(chan struct{})
+	errs := make(chan error, 2)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				errs <- nil
+				return
+			default:
+			}
+			search, err := c.gateway.Search(ctx, &pb.SearchReq{CollectionName: "docs", Vector: []float32{0, 0}, TopK: 1000})
+			if err == nil && len(search.GetResponse()) != len(ids) {
+				err = fmt.Errorf("search returned %d of %d points", len(search.GetResponse()), len(ids))
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+	go func() {
+		for i := 0; i < len(ids); i += 3 {
+			_, err := c.gateway.Update(ctx, &pb.ModifyDataset{Id: ids[i], CollectionName: "docs", Vector: []float32{float32(i), 1}})
+			if err != nil {
+				errs <- err
+				return
+			}
+		}
+		errs <- nil
+	}()
+
+	reshard, err := c.gateway.Reshard(ctx, &pb.ReshardReq{ServerAddrs: c.addrs})
+	require.NoError(t, err)
+	require.True(t, reshard.GetResult(), reshard.GetErrorMessage())
+	require.NoError(t, <-errs)
+	close(stop)
+	require.NoError(t, <-errs)
+
+	// Roughly a quarter of the points moved to the new node and only those
+	after := sizes()
+	require.EqualValues(t, reshard.GetMoved(), after[3])
+	require.InDelta(t, len(ids)/4, after[3], float64(len(ids))/8)
+	require.EqualValues(t, len(ids), after[0]+after[1]+after[2]+after[3])
+	points, err := c.gateway.GetPoints(ctx, &pb.PointIds{CollectionName: "docs", Ids: ids})
+	require.NoError(t, err)
+	require.Len(t, points.GetPoints(), len(ids))
+	for i, row := range points.GetPoints() {
+		require.Equal(t, ids[i], row.GetId())
+		y := float32(0)
+		if i%3 == 0 {
+			y = 1
+		}
+		require.Equal(t, []float32{float32(i), y}, row.GetVector())
+	}
+
+	// Removing a node empties it
+	reshard, err = c.gateway.Reshard(ctx, &pb.ReshardReq{ServerAddrs: []string{c.addrs[0], c.addrs[2], c.addrs[3]}})
+	require.NoError(t, err)
+	require.True(t, reshard.GetResult(), reshard.GetErrorMessage())
+	require.EqualValues(t, after[1], reshard.GetMoved())
+	after = sizes()
+	require.Zero(t, after[1])
+	col, err := c.gateway.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+	require.NoError(t, err)
+	require.EqualValues(t, len(ids), col.GetCollectionSize())
+
+	// Moved points keep their versions, and points moving back are not lost
+	// to tombstones of their move away
+	moved, err := c.gateway.GetPoints(ctx, &pb.PointIds{CollectionName: "docs", Ids: ids})
+	require.NoError(t, err)
+	require.Len(t, moved.GetPoints(), len(ids))
+	for i, row := range moved.GetPoints() {
+		require.Equal(t, points.GetPoints()[i].GetVersion(), row.GetVersion())
+	}
+	reshard, err = c.gateway.Reshard(ctx, &pb.ReshardReq{ServerAddrs: c.addrs})
+	require.NoError(t, err)
+	require.True(t, reshard.GetResult(), reshard.GetErrorMessage())
+	require.NotZero(t, sizes()[1])
+	moved, err = c.gateway.GetPoints(ctx, &pb.PointIds{CollectionName: "docs", Ids: ids})
+	require.NoError(t, err)
+	require.Len(t, moved.GetPoints(), len(ids))
+	resp, err := c.gateway.Delete(ctx, &pb.DeleteDataset{Id: ids[0], CollectionName: "docs"})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+}
+
+func TestShardTenants(t *testing.T) {
+	c := startCluster(t, 3, 2, gateway.SLB)
+	teamA := metadata.AppendToOutgoingContext(context.Background(), tenant.MetadataKey, "team-a")
+	created, err := c.gateway.CreateCollection(teamA, &pb.Collection{CollectionName: "docs", Dimension: 3})
+	require.NoError(t, err)
+	require.True(t, created.GetResponse().GetResult(), created.GetResponse().GetErrorMessage())
+	ids := make([]string, 40)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+		resp, err := c.gateway.Insert(teamA, &pb.ModifyDataset{Id: ids[i], CollectionName: "docs", Vector: []float32{float32(i), 0, 0}})
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	}
+	list, err := c.gateway.ListCollection(teamA, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, list.GetCollections(), 1)
+	require.EqualValues(t, 3, list.GetCollections()[0].GetDimension())
+	require.EqualValues(t, len(ids), list.GetCollections()[0].GetCollectionSize())
+	col, err := c.gateway.GetCollection(context.Background(), &pb.CollectionName{CollectionName: "docs"})
+	require.NoError(t, err)
+	require.Zero(t, col.GetCollectionSize())
+
+	// resharding moves the collections of every tenant
+	reshard, err := c.gateway.Reshard(context.Background(), &pb.ReshardReq{ServerAddrs: c.addrs})
+	require.NoError(t, err)
+	require.True(t, reshard.GetResult(), reshard.GetErrorMessage())
+	require.NotZero(t, reshard.GetMoved())
+	moved, err := c.nodes[2].GetCollection(teamA, &pb.CollectionName{CollectionName: "docs"})
+	require.NoError(t, err)
+	require.EqualValues(t, reshard.GetMoved(), moved.GetCollectionSize())
+	points, err := c.gateway.GetPoints(teamA, &pb.PointIds{CollectionName: "docs", Ids: ids})
+	require.NoError(t, err)
+	require.Len(t, points.GetPoints(), len(ids))
+}
+
+func TestShardAliases(t *testing.T) {
+	c := startCluster(t, 3, 2, gateway.SLB)
+	ctx := context.Background()
+	created, err := c.gateway.CreateCollection(ctx, &pb.Collection{CollectionName: "docs-v2", Dimension: 2})
+	require.NoError(t, err)
+	require.True(t, created.GetResponse().GetResult(), created.GetResponse().GetErrorMessage())
+	for _, alias := range []*pb.Alias{{Alias: "live", CollectionName: "docs"}, {Alias: "next", CollectionName: "docs-v2"}} {
+		resp, err := c.gateway.CreateAlias(ctx, alias)
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	}
+	for i := 0; i < 40; i++ {
+		collection := "live"
+		if i%4 == 0 {
+			collection = "next"
+		}
+		resp, err := c.gateway.Insert(ctx, &pb.ModifyDataset{Id: uuid.NewString(), CollectionName: collection, Vector: []float32{float32(i), 0}})
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	}
+	count := func(name string) uint64 {
+		t.Helper()
+		resp, err := c.gateway.Count(ctx, &pb.CountReq{CollectionName: name})
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+		return resp.GetCount()
+	}
+	require.EqualValues(t, 30, count("live"))
+	require.EqualValues(t, 10, count("next"))
+
+	// reads during swaps see every shard before or after a swap
+	done := make(chan struct{})
+	counts := make(chan uint64, 1024)
+	go func() {
+		defer close(counts)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				counts <- count("live")
+			}
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		resp, err := c.gateway.SwapAliases(ctx, &pb.SwapAliasesReq{Alias: "live", OtherAlias: "next"})
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	}
+	close(done)
+	for n := range counts {
+		require.Contains(t, []uint64{10, 30}, n)
+	}
+	require.EqualValues(t, 30, count("live"))
+
+	// resharding gives the new server the aliases
+	reshard, err := c.gateway.Reshard(ctx, &pb.ReshardReq{ServerAddrs: c.addrs})
+	require.NoError(t, err)
+	require.True(t, reshard.GetResult(), reshard.GetErrorMessage())
+	aliases, err := c.nodes[2].ListAliases(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, aliases.GetAliases(), 2)
+	resp, err := c.gateway.PointAlias(ctx, &pb.Alias{Alias: "live", CollectionName: "docs-v2"})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	require.EqualValues(t, 10, count("live"))
+	aliases, err = c.gateway.ListAliases(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, aliases.GetAliases(), 2)
+	require.Equal(t, "docs-v2", aliases.GetAliases()[0].GetCollectionName())
+}
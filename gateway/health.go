@@ -0,0 +1,241 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gateway
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/errcode"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	DefaultHealthCheckInterval = 2 * time.Second
+	DefaultFailureThreshold    = 3
+	// successful probes or calls until a recovered backend takes its full
+	// share of reads again
+	DefaultRecoverySteps = 5
+)
+
+type healthPolicy struct {
+	interval      time.Duration
+	threshold     int
+	recoverySteps int
+}
+
+func (g GateWay) healthPolicy() healthPolicy {
+	p := healthPolicy{
+		interval:      g.HealthCheckInterval,
+		threshold:     g.FailureThreshold,
+		recoverySteps: DefaultRecoverySteps,
+	}
+	if p.interval <= 0 {
+		p.interval = DefaultHealthCheckInterval
+	}
+	if p.threshold <= 0 {
+		p.threshold = DefaultFailureThreshold
+	}
+	return p
+}
+
+type breakerState int
+
+const (
+	// every call goes through
+	breakerClosed breakerState = iota
+	// the backend answered a probe again and gets a growing share of reads
+	breakerHalfOpen
+	// calls fail right away until a probe succeeds
+	breakerOpen
+)
+
+// breaker is the circuit breaker of a backend. Consecutive RPC failures open
+// it, the health probe moves it to half-open once the backend answers and
+// every success from there raises the share of reads it is given.
+type breaker struct {
+	addr     string
+	policy   healthPolicy
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	// share of reads admitted while half-open
+	weight float64
+}
+
+func newBreaker(addr string, policy healthPolicy) *breaker {
+	return &breaker{addr: addr, policy: policy}
+}
+
+func (b *breaker) current() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *breaker) allow() bool {
+	return b.current() != breakerOpen
+}
+
+// admitRead decides whether a read should go to the backend first.
+func (b *breaker) admitRead() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerClosed:
+		return true
+	case breakerHalfOpen:
+		return rand.Float64() < b.weight
+	}
+	return false
+}
+
+func (b *breaker) transition(to breakerState) {
+	if b.state == to {
+		return
+	}
+	b.state = to
+	b.failures = 0
+	switch to {
+	case breakerOpen:
+		log.Warn().Str("backend", b.addr).Msg("circuit opened")
+	case breakerHalfOpen:
+		b.weight = 1 / float64(b.policy.recoverySteps)
+		log.Info().Str("backend", b.addr).Msg("circuit half-open, re-admitting backend")
+	case breakerClosed:
+		log.Info().Str("backend", b.addr).Msg("circuit closed")
+	}
+}
+
+// record counts the outcome of a call.
+func (b *breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if isBackendFailure(err) {
+		b.failures++
+		if b.state == breakerHalfOpen || b.failures >= b.policy.threshold {
+			b.transition(breakerOpen)
+		}
+		return
+	}
+	switch b.state {
+	case breakerClosed:
+		b.failures = 0
+	case breakerHalfOpen:
+		b.weight += 1 / float64(b.policy.recoverySteps)
+		if b.weight >= 1 {
+			b.transition(breakerClosed)
+		}
+	}
+}
+
+// probe counts the outcome of a health check, the only way out of open.
+func (b *breaker) probe(err error) {
+	if err == nil {
+		b.mu.Lock()
+		if b.state == breakerOpen {
+			b.transition(breakerHalfOpen)
+			b.mu.Unlock()
+			return
+		}
+		b.mu.Unlock()
+	}
+	b.record(err)
+}
+
+// isBackendFailure tells calls the backend failed to serve, the ones reported
+// as COMMUNICATION_SHARD_RPC_ERROR, from answers it gave on purpose. A status
+// with an error code of the node is such an answer, a full quota included.
+func isBackendFailure(err error) bool {
+	if _, ok := errcode.FromError(err); ok {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.DataLoss, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+func errCircuitOpen(addr string) error {
+	return status.Errorf(codes.Unavailable, "%s: circuit open", addr)
+}
+
+// interceptors put the breaker in front of every call on the backend
+// connection. Pings are the health probe and bypass it, calls the caller gave
+// up on are not counted.
+func (b *breaker) interceptors() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			if method == pb.LBCoordinator_Ping_FullMethodName {
+				return invoker(ctx, method, req, reply, cc, opts...)
+			}
+			if !b.allow() {
+				return errCircuitOpen(b.addr)
+			}
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if ctx.Err() == nil {
+				b.record(err)
+			}
+			return err
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			if !b.allow() {
+				return nil, errCircuitOpen(b.addr)
+			}
+			stream, err := streamer(ctx, desc, cc, method, opts...)
+			if ctx.Err() == nil {
+				b.record(err)
+			}
+			return stream, err
+		}),
+	}
+}
+
+// healthCheck pings every backend of the coordinator each interval until
+// closing is closed.
+func healthCheck(c coordinator, policy healthPolicy, closing <-chan struct{}) {
+	ticker := time.NewTicker(policy.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closing:
+			return
+		case <-ticker.C:
+		}
+		var wg sync.WaitGroup
+		for _, b := range c.members() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(context.Background(), policy.interval)
+				defer cancel()
+				_, err := b.client.Ping(ctx, &emptypb.Empty{})
+				b.breaker.probe(err)
+			}()
+		}
+		wg.Wait()
+	}
+}
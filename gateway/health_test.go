@@ -0,0 +1,224 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// fakeBackend answers pings and searches, searches fail while failing is set.
+type fakeBackend struct {
+	pb.UnimplementedLBCoordinatorServer
+	searches atomic.Int64
+	failing  atomic.Bool
+}
+
+func (f *fakeBackend) Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeBackend) Search(context.Context, *pb.SearchReq) (*pb.SearchResponse, error) {
+	f.searches.Add(1)
+	if f.failing.Load() {
+		return nil, status.Error(codes.Internal, "search failed")
+	}
+	return &pb.SearchResponse{Result: true}, nil
+}
+
+// fakeCluster serves fake backends over in-memory listeners, a killed backend
+// refuses connections until it is restarted.
+type fakeCluster struct {
+	mu        sync.Mutex
+	listeners map[string]*bufconn.Listener
+	servers   map[string]*grpc.Server
+	backends  []*fakeBackend
+	addrs     []string
+}
+
+func startFakeCluster(t *testing.T, count int) *fakeCluster {
+	t.Helper()
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	c := &fakeCluster{
+		listeners: make(map[string]*bufconn.Listener),
+		servers:   make(map[string]*grpc.Server),
+	}
+	for i := 0; i < count; i++ {
+		c.backends = append(c.backends, &fakeBackend{})
+		c.addrs = append(c.addrs, fmt.Sprintf("passthrough:///fake-%d", i))
+		c.restart(i)
+	}
+	t.Cleanup(func() {
+		for i := range c.backends {
+			c.kill(i)
+		}
+	})
+	return c
+}
+
+func (c *fakeCluster) name(i int) string {
+	return strings.TrimPrefix(c.addrs[i], "passthrough:///")
+}
+
+func (c *fakeCluster) kill(i int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.servers[c.name(i)]; ok {
+		s.Stop()
+	}
+	delete(c.servers, c.name(i))
+	delete(c.listeners, c.name(i))
+}
+
+func (c *fakeCluster) restart(i int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	pb.RegisterLBCoordinatorServer(s, c.backends[i])
+	go s.Serve(lis)
+	c.listeners[c.name(i)] = lis
+	c.servers[c.name(i)] = s
+}
+
+func (c *fakeCluster) dialer() grpc.DialOption {
+	return grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		c.mu.Lock()
+		lis, ok := c.listeners[addr]
+		c.mu.Unlock()
+		if !ok {
+			return nil, errors.New("connection refused")
+		}
+		return lis.DialContext(ctx)
+	})
+}
+
+func (c *fakeCluster) gateway(t *testing.T, interval time.Duration) *Server {
+	t.Helper()
+	gw, err := New(GateWay{
+		ServerAddrs:         c.addrs,
+		Balancer:            LB,
+		HealthCheckInterval: interval,
+		FailureThreshold:    3,
+	}, c.dialer())
+	require.NoError(t, err)
+	t.Cleanup(gw.Close)
+	return gw
+}
+
+func search(t *testing.T, gw *Server, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		resp, err := gw.coordinator.Search(context.Background(), &pb.SearchReq{CollectionName: "docs"})
+		require.NoError(t, err)
+		require.True(t, resp.GetResult())
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	b := newBreaker("fake", healthPolicy{threshold: 2, recoverySteps: 2})
+	failure := status.Error(codes.Unavailable, "down")
+
+	// only consecutive failures count, a rejection by the backend is an answer
+	b.record(failure)
+	b.record(nil)
+	b.record(failure)
+	b.record(status.Error(codes.NotFound, "no such collection"))
+	b.record(failure)
+	require.Equal(t, breakerClosed, b.current())
+	b.record(failure)
+	require.Equal(t, breakerOpen, b.current())
+	require.False(t, b.allow())
+	require.False(t, b.admitRead())
+
+	// only a probe leaves open, a failure while recovering opens it again
+	b.record(nil)
+	require.Equal(t, breakerOpen, b.current())
+	b.probe(failure)
+	require.Equal(t, breakerOpen, b.current())
+	b.probe(nil)
+	require.Equal(t, breakerHalfOpen, b.current())
+	require.True(t, b.allow())
+	b.record(failure)
+	require.Equal(t, breakerOpen, b.current())
+
+	b.probe(nil)
+	require.Equal(t, breakerHalfOpen, b.current())
+	b.record(nil)
+	require.Equal(t, breakerClosed, b.current())
+	require.True(t, b.admitRead())
+}
+
+func TestHealthCheckFailover(t *testing.T) {
+	c := startFakeCluster(t, 3)
+	gw := c.gateway(t, 10*time.Millisecond)
+	brk := gw.coordinator.members()[0].breaker
+
+	c.kill(0)
+	require.Eventually(t, func() bool { return brk.current() == breakerOpen }, 5*time.Second, 10*time.Millisecond)
+	search(t, gw, 30)
+	require.Zero(t, c.backends[0].searches.Load())
+
+	// the restarted backend is probed back in and takes reads again
+	c.restart(0)
+	require.Eventually(t, func() bool { return brk.current() == breakerClosed }, 5*time.Second, 10*time.Millisecond)
+	search(t, gw, 30)
+	require.NotZero(t, c.backends[0].searches.Load())
+}
+
+func TestCircuitBreakerReadmission(t *testing.T) {
+	c := startFakeCluster(t, 2)
+	// probes are run by hand
+	gw := c.gateway(t, time.Hour)
+	b := gw.coordinator.members()[1]
+
+	// repeated errors open the circuit and reads fail over
+	c.backends[1].failing.Store(true)
+	search(t, gw, 50)
+	require.Equal(t, breakerOpen, b.breaker.current())
+	require.EqualValues(t, 3, c.backends[1].searches.Load())
+
+	// once the backend answers again it gets a growing share of reads
+	c.backends[1].failing.Store(false)
+	b.breaker.probe(nil)
+	require.Equal(t, breakerHalfOpen, b.breaker.current())
+	for b.breaker.current() == breakerHalfOpen {
+		search(t, gw, 1)
+	}
+	require.Equal(t, breakerClosed, b.breaker.current())
+	require.EqualValues(t, 3+DefaultRecoverySteps-1, c.backends[1].searches.Load())
+	search(t, gw, 20)
+	require.EqualValues(t, 3+DefaultRecoverySteps-1+10, c.backends[1].searches.Load())
+}
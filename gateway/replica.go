@@ -0,0 +1,467 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/errcode"
+	"github.com/sjy-dv/nnv/pkg/hlc"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// replicaCoordinator keeps every backend a full copy of the data. A write is
+// committed only when all replicas accept it, otherwise the replicas that did
+// apply it are rolled back with a compensating write. Writes are versioned by
+// the gateway, so every replica keeps the same version of a point and writes
+// of several gateways end up in the same order everywhere.
+type replicaCoordinator struct {
+	pb.UnimplementedLBCoordinatorServer
+	backends []*backend
+	next     atomic.Uint64
+	locks    pointLocks
+	clock    *hlc.Clock
+	origin   string
+}
+
+func newReplicaCoordinator(backends []*backend) *replicaCoordinator {
+	return &replicaCoordinator{
+		backends: backends,
+		clock:    hlc.NewClock(),
+		origin:   "gateway-" + uuid.NewString(),
+	}
+}
+
+// version returns the version of a write, the one the caller picked or a new
+// one of the gateway clock.
+func (r *replicaCoordinator) version(version uint64, origin string) (uint64, string, error) {
+	if version == 0 {
+		ts, err := r.clock.Now()
+		if err != nil {
+			return 0, "", errcode.Error(pb.ErrorCode_UNDEFINED, err.Error(), nil)
+		}
+		return uint64(ts), r.origin, nil
+	}
+	if _, err := r.clock.Update(hlc.Timestamp(version)); err != nil {
+		return 0, "", errcode.Error(pb.ErrorCode_INVALID_ARGUMENT, fmt.Sprintf("version %d: %v", version, err), nil)
+	}
+	return version, origin, nil
+}
+
+// stamp versions a write unless the caller did.
+func (r *replicaCoordinator) stamp(req *pb.ModifyDataset) (*pb.ModifyDataset, error) {
+	version, origin, err := r.version(req.GetVersion(), req.GetOrigin())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ModifyDataset{
+		Id:             req.GetId(),
+		CollectionName: req.GetCollectionName(),
+		Vector:         req.GetVector(),
+		Metadata:       req.GetMetadata(),
+		Version:        version,
+		Origin:         origin,
+		Precondition:   req.GetPrecondition(),
+		Internal:       req.GetInternal(),
+	}, nil
+}
+
+// compensate versions a rollback after the write it undoes. It restores what
+// was stored, so quotas do not apply.
+func (r *replicaCoordinator) compensate(collectionName, id string, row *pb.Row) (*pb.ModifyDataset, error) {
+	return r.stamp(&pb.ModifyDataset{
+		Id:             id,
+		CollectionName: collectionName,
+		Vector:         row.GetVector(),
+		Metadata:       row.GetMetadata(),
+		Internal:       true,
+	})
+}
+
+func (r *replicaCoordinator) close() {
+	closeBackends(r.backends)
+}
+
+func (r *replicaCoordinator) members() []*backend {
+	return r.backends
+}
+
+// readOrder starts at the next backend in round robin order. Backends whose
+// circuit is open go last, recovering ones are only tried first for their
+// share of reads.
+func (r *replicaCoordinator) readOrder() []*backend {
+	start := int(r.next.Add(1) % uint64(len(r.backends)))
+	admitted := make([]*backend, 0, len(r.backends))
+	var recovering, open []*backend
+	for i := range r.backends {
+		b := r.backends[(start+i)%len(r.backends)]
+		switch {
+		case b.breaker.admitRead():
+			admitted = append(admitted, b)
+		case b.breaker.allow():
+			recovering = append(recovering, b)
+		default:
+			open = append(open, b)
+		}
+	}
+	return append(append(admitted, recovering...), open...)
+}
+
+// read runs call on a single replica, falling over to the next one when a
+// replica fails to serve it.
+func (r *replicaCoordinator) read(ctx context.Context, call func(context.Context, *backend) error) error {
+	var lastErr error
+	for _, b := range r.readOrder() {
+		err := call(ctx, b)
+		if err == nil {
+			return nil
+		}
+		lastErr = shardFailure(b, err)
+		if !isBackendFailure(err) || ctx.Err() != nil {
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+// fetchPoint reads the current state of a point so that it can be restored if
+// a write fails. Replicas may have diverged, so the first replica that has the
+// point wins and nil means none of them has it.
+func (r *replicaCoordinator) fetchPoint(ctx context.Context, collectionName, id string) (*pb.Row, error) {
+	var lastErr error
+	for _, b := range r.readOrder() {
+		resp, err := b.client.GetPoints(ctx, &pb.PointIds{CollectionName: collectionName, Ids: []string{id}})
+		if isBackendFailure(err) {
+			lastErr = shardFailure(b, err)
+			continue
+		}
+		if err != nil {
+			return nil, shardFailure(b, err)
+		}
+		if len(resp.GetPoints()) > 0 {
+			return resp.GetPoints()[0], nil
+		}
+	}
+	return nil, lastErr
+}
+
+func (r *replicaCoordinator) Ping(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (r *replicaCoordinator) CreateCollection(ctx context.Context, req *pb.Collection) (*pb.CollectionResponse, error) {
+	return createCollection(ctx, r.backends, req)
+}
+
+func (r *replicaCoordinator) DropCollection(ctx context.Context, req *pb.CollectionName) (*pb.Response, error) {
+	return dropCollection(ctx, r.backends, req)
+}
+
+func (r *replicaCoordinator) GetCollection(ctx context.Context, req *pb.CollectionName) (*pb.Collection, error) {
+	var col *pb.Collection
+	err := r.read(ctx, func(ctx context.Context, b *backend) (err error) {
+		col, err = b.client.GetCollection(ctx, req)
+		return err
+	})
+	return col, err
+}
+
+func (r *replicaCoordinator) ListCollection(ctx context.Context, req *emptypb.Empty) (*pb.CollectionList, error) {
+	var list *pb.CollectionList
+	err := r.read(ctx, func(ctx context.Context, b *backend) (err error) {
+		list, err = b.client.ListCollection(ctx, req)
+		return err
+	})
+	return list, err
+}
+
+func (r *replicaCoordinator) CreateAlias(ctx context.Context, req *pb.Alias) (*pb.Response, error) {
+	return createAlias(ctx, r.backends, req)
+}
+
+func (r *replicaCoordinator) PointAlias(ctx context.Context, req *pb.Alias) (*pb.Response, error) {
+	return pointAlias(ctx, r.backends, req)
+}
+
+func (r *replicaCoordinator) SwapAliases(ctx context.Context, req *pb.SwapAliasesReq) (*pb.Response, error) {
+	return swapAliases(ctx, r.backends, req)
+}
+
+func (r *replicaCoordinator) DeleteAlias(ctx context.Context, req *pb.AliasName) (*pb.Response, error) {
+	return deleteAlias(ctx, r.backends, req)
+}
+
+func (r *replicaCoordinator) ListAliases(ctx context.Context, req *emptypb.Empty) (*pb.AliasList, error) {
+	var list *pb.AliasList
+	err := r.read(ctx, func(ctx context.Context, b *backend) (err error) {
+		list, err = b.client.ListAliases(ctx, req)
+		return err
+	})
+	return list, err
+}
+
+func (r *replicaCoordinator) Insert(ctx context.Context, req *pb.ModifyDataset) (*pb.Response, error) {
+	unlock := r.locks.lock(req.GetCollectionName(), req.GetId())
+	defer unlock()
+	req, err := r.stamp(req)
+	if err != nil {
+		return nil, err
+	}
+	results := fanOut(ctx, r.backends, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.Insert(ctx, req)
+	})
+	if allOk(results) {
+		return respond(nil)
+	}
+	version, origin, err := r.version(0, "")
+	return respond(rollback(ctx, results, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		if err != nil {
+			return nil, err
+		}
+		return b.client.Delete(ctx, &pb.DeleteDataset{
+			Id:             req.GetId(),
+			CollectionName: req.GetCollectionName(),
+			Version:        version,
+			Origin:         origin,
+		})
+	}))
+}
+
+func (r *replicaCoordinator) Update(ctx context.Context, req *pb.ModifyDataset) (*pb.Response, error) {
+	unlock := r.locks.lock(req.GetCollectionName(), req.GetId())
+	defer unlock()
+	prev, err := r.fetchPoint(ctx, req.GetCollectionName(), req.GetId())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read point before update: %w", err)
+	}
+	req, err = r.stamp(req)
+	if err != nil {
+		return nil, err
+	}
+	results := fanOut(ctx, r.backends, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.Update(ctx, req)
+	})
+	if allOk(results) {
+		return respond(nil)
+	}
+	if prev == nil {
+		return respond(failure(results))
+	}
+	undo, err := r.compensate(req.GetCollectionName(), req.GetId(), prev)
+	return respond(rollback(ctx, results, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		if err != nil {
+			return nil, err
+		}
+		return b.client.Update(ctx, undo)
+	}))
+}
+
+// PatchMetadata sends the same versioned patch to every replica, replicas
+// that hold the same point end up with the same fields. A failed patch is
+// undone by writing back the point as it was.
+func (r *replicaCoordinator) PatchMetadata(ctx context.Context, req *pb.PatchMetadataReq) (*pb.Response, error) {
+	unlock := r.locks.lock(req.GetCollectionName(), req.GetId())
+	defer unlock()
+	prev, err := r.fetchPoint(ctx, req.GetCollectionName(), req.GetId())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read point before patch: %w", err)
+	}
+	version, origin, err := r.version(req.GetVersion(), req.GetOrigin())
+	if err != nil {
+		return nil, err
+	}
+	req = &pb.PatchMetadataReq{
+		Id:             req.GetId(),
+		CollectionName: req.GetCollectionName(),
+		Set:            req.GetSet(),
+		Unset:          req.GetUnset(),
+		Increment:      req.GetIncrement(),
+		Version:        version,
+		Origin:         origin,
+	}
+	results := fanOut(ctx, r.backends, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.PatchMetadata(ctx, req)
+	})
+	if allOk(results) {
+		return respond(nil)
+	}
+	if prev == nil {
+		return respond(failure(results))
+	}
+	undo, err := r.compensate(req.GetCollectionName(), req.GetId(), prev)
+	return respond(rollback(ctx, results, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		if err != nil {
+			return nil, err
+		}
+		return b.client.Update(ctx, undo)
+	}))
+}
+
+func (r *replicaCoordinator) Delete(ctx context.Context, req *pb.DeleteDataset) (*pb.Response, error) {
+	unlock := r.locks.lock(req.GetCollectionName(), req.GetId())
+	defer unlock()
+	prev, err := r.fetchPoint(ctx, req.GetCollectionName(), req.GetId())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read point before delete: %w", err)
+	}
+	version, origin, err := r.version(req.GetVersion(), req.GetOrigin())
+	if err != nil {
+		return nil, err
+	}
+	req = &pb.DeleteDataset{
+		Id:             req.GetId(),
+		CollectionName: req.GetCollectionName(),
+		Version:        version,
+		Origin:         origin,
+		Precondition:   req.GetPrecondition(),
+	}
+	results := fanOut(ctx, r.backends, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.Delete(ctx, req)
+	})
+	if allOk(results) {
+		return respond(nil)
+	}
+	if prev == nil {
+		return respond(failure(results))
+	}
+	undo, err := r.compensate(req.GetCollectionName(), req.GetId(), prev)
+	return respond(rollback(ctx, results, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		if err != nil {
+			return nil, err
+		}
+		return b.client.Insert(ctx, undo)
+	}))
+}
+
+// serveStream answers every received message with its own response, each
+// item is committed or rolled back on its own and a failed item does not
+// abort the rest of the stream.
+func serveStream[T any](stream grpc.BidiStreamingServer[T, pb.Response], handle func(context.Context, *T) (*pb.Response, error)) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		resp, err := handle(stream.Context(), req)
+		if err != nil {
+			resp = errResponse(err)
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *replicaCoordinator) BatchInsert(stream grpc.BidiStreamingServer[pb.ModifyDataset, pb.Response]) error {
+	return serveStream(stream, r.Insert)
+}
+
+func (r *replicaCoordinator) BatchUpdate(stream grpc.BidiStreamingServer[pb.ModifyDataset, pb.Response]) error {
+	return serveStream(stream, r.Update)
+}
+
+func (r *replicaCoordinator) BatchDelete(stream grpc.BidiStreamingServer[pb.DeleteDataset, pb.Response]) error {
+	return serveStream(stream, r.Delete)
+}
+
+// DataLoader forwards every point to the loader stream of each replica. The
+// loader upserts, so there is nothing to roll back.
+func (r *replicaCoordinator) DataLoader(stream grpc.BidiStreamingServer[pb.ModifyDataset, pb.Response]) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	loaders := make(map[*backend]grpc.BidiStreamingClient[pb.ModifyDataset, pb.Response], len(r.backends))
+	for _, b := range r.backends {
+		loader, err := b.client.DataLoader(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to open data loader on %s: %w", b.addr, err)
+		}
+		loaders[b] = loader
+	}
+	err := serveStream(stream, func(ctx context.Context, req *pb.ModifyDataset) (*pb.Response, error) {
+		req, err := r.stamp(req)
+		if err != nil {
+			return nil, err
+		}
+		results := fanOut(ctx, r.backends, func(ctx context.Context, b *backend) (*pb.Response, error) {
+			loader := loaders[b]
+			if err := loader.Send(req); err != nil {
+				return nil, err
+			}
+			return loader.Recv()
+		})
+		return respond(failure(results))
+	})
+	for _, loader := range loaders {
+		loader.CloseSend()
+	}
+	return err
+}
+
+func (r *replicaCoordinator) Search(ctx context.Context, req *pb.SearchReq) (*pb.SearchResponse, error) {
+	var resp *pb.SearchResponse
+	err := r.read(ctx, func(ctx context.Context, b *backend) (err error) {
+		resp, err = b.client.Search(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+func (r *replicaCoordinator) BatchSearch(ctx context.Context, req *pb.BatchSearchReq) (*pb.BatchSearchResponse, error) {
+	var resp *pb.BatchSearchResponse
+	err := r.read(ctx, func(ctx context.Context, b *backend) (err error) {
+		resp, err = b.client.BatchSearch(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+func (r *replicaCoordinator) GetPoints(ctx context.Context, req *pb.PointIds) (*pb.PointsResponse, error) {
+	var resp *pb.PointsResponse
+	err := r.read(ctx, func(ctx context.Context, b *backend) (err error) {
+		resp, err = b.client.GetPoints(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+func (r *replicaCoordinator) Scroll(ctx context.Context, req *pb.ScrollReq) (*pb.ScrollResponse, error) {
+	var resp *pb.ScrollResponse
+	err := r.read(ctx, func(ctx context.Context, b *backend) (err error) {
+		resp, err = b.client.Scroll(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+func (r *replicaCoordinator) Count(ctx context.Context, req *pb.CountReq) (*pb.CountResponse, error) {
+	var resp *pb.CountResponse
+	err := r.read(ctx, func(ctx context.Context, b *backend) (err error) {
+		resp, err = b.client.Count(ctx, req)
+		return err
+	})
+	return resp, err
+}
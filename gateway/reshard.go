@@ -0,0 +1,476 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/errcode"
+	"github.com/sjy-dv/nnv/pkg/sharding"
+	"github.com/sjy-dv/nnv/pkg/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var ErrReshardPending = errors.New("an interrupted reshard has to be resumed with the same server list")
+
+// topology is a server list and the placement of points over it.
+type topology struct {
+	backends  []*backend
+	placement sharding.Placement
+}
+
+func newTopology(backends []*backend, placementType sharding.PlacementType) (*topology, error) {
+	placement, err := sharding.NewPlacement(placementType, addrsOf(backends))
+	if err != nil {
+		return nil, err
+	}
+	return &topology{backends: backends, placement: placement}, nil
+}
+
+func addrsOf(backends []*backend) []string {
+	addrs := make([]string, len(backends))
+	for i, b := range backends {
+		addrs[i] = b.addr
+	}
+	return addrs
+}
+
+// locate returns the index of the backend owning the point.
+func (t *topology) locate(id string) (int, error) {
+	pointId, err := uuid.Parse(id)
+	if err != nil {
+		return 0, fmt.Errorf("invalid point id %s: %w", id, err)
+	}
+	return t.placement.Locate(pointId), nil
+}
+
+func (t *topology) owner(id string) (*backend, error) {
+	i, err := t.locate(id)
+	if err != nil {
+		return nil, err
+	}
+	return t.backends[i], nil
+}
+
+// view is what a request works with. While a reshard moves points previous
+// holds the old topology, a point is then on its previous owner until it has
+// been moved and on its current owner afterwards.
+type view struct {
+	current  *topology
+	previous *topology
+	// requests that still use the view
+	active sync.WaitGroup
+}
+
+func (v *view) migrating() bool {
+	return v.previous != nil
+}
+
+// backends are the servers of both topologies while migrating.
+func (v *view) backends() []*backend {
+	if !v.migrating() {
+		return v.current.backends
+	}
+	backends := slices.Clone(v.current.backends)
+	for _, b := range v.previous.backends {
+		if !slices.Contains(backends, b) {
+			backends = append(backends, b)
+		}
+	}
+	return backends
+}
+
+// owners returns the current owner of a point, and its previous owner if the
+// point may still have to move.
+func (v *view) owners(id string) (current, previous *backend, err error) {
+	current, err = v.current.owner(id)
+	if err != nil || !v.migrating() {
+		return current, nil, err
+	}
+	previous, err = v.previous.owner(id)
+	if previous == current {
+		previous = nil
+	}
+	return current, previous, err
+}
+
+// holder returns the owner that stores an existing point, a point that has
+// not moved yet is changed in place and moved later.
+func (v *view) holder(ctx context.Context, collectionName, id string) (*backend, error) {
+	current, previous, err := v.owners(id)
+	if err != nil {
+		return nil, invalidId(err)
+	}
+	if previous == nil {
+		return current, nil
+	}
+	row, err := hasPoint(ctx, current, collectionName, id)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return previous, nil
+	}
+	return current, nil
+}
+
+func (s *shardCoordinator) acquire() *view {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.view.active.Add(1)
+	return s.view
+}
+
+func (v *view) release() {
+	v.active.Done()
+}
+
+// swap installs the next view and waits until no request uses the old one.
+func (s *shardCoordinator) swap(next *view) {
+	s.mu.Lock()
+	old := s.view
+	s.view = next
+	s.mu.Unlock()
+	old.active.Wait()
+}
+
+// hasPoint reports whether the backend stores the point.
+func hasPoint(ctx context.Context, b *backend, collectionName, id string) (*pb.Row, error) {
+	resp, err := b.client.GetPoints(ctx, &pb.PointIds{CollectionName: collectionName, Ids: []string{id}})
+	if err != nil {
+		return nil, shardFailure(b, err)
+	}
+	if len(resp.GetPoints()) == 0 {
+		return nil, nil
+	}
+	return resp.GetPoints()[0], nil
+}
+
+func (s *shardCoordinator) Reshard(ctx context.Context, req *pb.ReshardReq) (*pb.ReshardResponse, error) {
+	moved, err := s.reshard(ctx, req.GetServerAddrs())
+	if err != nil {
+		// the points moved so far stay moved, the next call resumes
+		return nil, errcode.Error(pb.ErrorCode_COMMUNICATION_SHARD_ERROR, err.Error(), map[string]string{"moved": strconv.FormatUint(moved, 10)})
+	}
+	return &pb.ReshardResponse{Result: true, Moved: moved}, nil
+}
+
+// reshard moves every point whose owner differs under the new server list.
+// Requests keep being served meanwhile: writes to a point wait while it is
+// moved and reads look at both topologies. Moving starts once the requests
+// that were routed with the old topology, batch streams included, are done.
+// If the job fails the gateway stays in the migrating state and the same call
+// resumes it.
+func (s *shardCoordinator) reshard(ctx context.Context, addrs []string) (uint64, error) {
+	if len(addrs) == 0 {
+		return 0, ErrNoBackends
+	}
+	s.resharding.Lock()
+	defer s.resharding.Unlock()
+
+	v := s.acquire()
+	current, previous := v.current, v.previous
+	v.release()
+	if previous != nil {
+		if !slices.Equal(addrs, addrsOf(current.backends)) {
+			return 0, ErrReshardPending
+		}
+	} else {
+		next, err := s.nextTopology(ctx, current, addrs)
+		if err != nil {
+			return 0, err
+		}
+		previous, current = current, next
+		s.swap(&view{current: current, previous: previous})
+	}
+
+	var moved uint64
+	for _, from := range previous.backends {
+		n, err := s.drain(ctx, from, current)
+		moved += n
+		if err != nil {
+			return moved, err
+		}
+	}
+	s.swap(&view{current: current})
+	for _, b := range previous.backends {
+		if !slices.Contains(current.backends, b) {
+			b.conn.Close()
+		}
+	}
+	log.Info().Strs("servers", addrs).Uint64("moved", moved).Msg("reshard finished")
+	return moved, nil
+}
+
+// nextTopology dials the servers that are new and creates every collection on
+// them before any point is routed there.
+func (s *shardCoordinator) nextTopology(ctx context.Context, current *topology, addrs []string) (*topology, error) {
+	backends := make([]*backend, len(addrs))
+	var dialed []*backend
+	for i, addr := range addrs {
+		if j := slices.Index(addrsOf(current.backends), addr); j >= 0 {
+			backends[i] = current.backends[j]
+			continue
+		}
+		b, err := s.dial(addr)
+		if err != nil {
+			closeBackends(dialed)
+			return nil, err
+		}
+		backends[i] = b
+		dialed = append(dialed, b)
+	}
+	next, err := newTopology(backends, s.placementType)
+	if err != nil {
+		closeBackends(dialed)
+		return nil, err
+	}
+	lists, err := gather(tenant.NewContext(ctx, tenant.All), current.backends, func(ctx context.Context, b *backend) (*pb.CollectionList, error) {
+		return b.client.ListCollection(ctx, &emptypb.Empty{})
+	})
+	if err == nil {
+		err = createCollections(ctx, dialed, lists[0].GetCollections())
+	}
+	if err == nil {
+		err = s.copyAliases(ctx, current.backends[0], dialed)
+	}
+	if err != nil {
+		closeBackends(dialed)
+		return nil, err
+	}
+	return next, nil
+}
+
+// copyAliases creates the aliases of from on the new servers, alias changes
+// wait meanwhile.
+func (s *shardCoordinator) copyAliases(ctx context.Context, from *backend, backends []*backend) error {
+	s.aliasing.RLock()
+	defer s.aliasing.RUnlock()
+	list, err := from.client.ListAliases(tenant.NewContext(ctx, tenant.All), &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf("%s: %w", from.addr, err)
+	}
+	for _, alias := range list.GetAliases() {
+		ctx := tenant.NewContext(ctx, alias.GetTenant())
+		req := &pb.Alias{Alias: alias.GetAlias(), CollectionName: alias.GetCollectionName()}
+		for _, b := range backends {
+			_, err := b.client.CreateAlias(ctx, req)
+			if code, _ := errcode.FromError(err); err != nil && code != pb.ErrorCode_ALREADY_EXISTS {
+				return fmt.Errorf("%s: %w", b.addr, err)
+			}
+		}
+	}
+	return nil
+}
+
+func createCollections(ctx context.Context, backends []*backend, collections []*pb.Collection) error {
+	for _, col := range collections {
+		ctx := tenant.NewContext(ctx, col.GetTenant())
+		req := &pb.Collection{
+			CollectionName: col.GetCollectionName(),
+			Dimension:      col.GetDimension(),
+			InvertedIndex:  col.GetInvertedIndex(),
+			VectorIndex:    col.GetVectorIndex(),
+		}
+		for _, b := range backends {
+			if _, err := b.client.GetCollection(ctx, &pb.CollectionName{CollectionName: col.GetCollectionName()}); status.Code(err) != codes.NotFound {
+				if err != nil {
+					return fmt.Errorf("%s: %w", b.addr, err)
+				}
+				continue
+			}
+			if _, err := b.client.CreateCollection(ctx, req); err != nil {
+				return fmt.Errorf("%s: %w", b.addr, err)
+			}
+		}
+	}
+	return nil
+}
+
+// drain moves the points of one server that are owned by another server in
+// the new topology.
+func (s *shardCoordinator) drain(ctx context.Context, from *backend, next *topology) (uint64, error) {
+	list, err := from.client.ListCollection(tenant.NewContext(ctx, tenant.All), &emptypb.Empty{})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", from.addr, err)
+	}
+	var moved uint64
+	for _, col := range list.GetCollections() {
+		n, err := s.drainCollection(tenant.NewContext(ctx, col.GetTenant()), from, next, col.GetCollectionName())
+		moved += n
+		if err != nil {
+			return moved, err
+		}
+	}
+	return moved, nil
+}
+
+func (s *shardCoordinator) drainCollection(ctx context.Context, from *backend, next *topology, collectionName string) (uint64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ids, err := from.client.ListPointIds(ctx, &pb.CollectionName{CollectionName: collectionName})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", from.addr, err)
+	}
+	loaders := make(map[*backend]grpc.BidiStreamingClient[pb.ModifyDataset, pb.Response])
+	defer func() {
+		for _, loader := range loaders {
+			loader.CloseSend()
+		}
+	}()
+	var moved uint64
+	for {
+		chunk, err := ids.Recv()
+		if err == io.EOF {
+			return moved, nil
+		}
+		if err != nil {
+			return moved, fmt.Errorf("%s: %w", from.addr, err)
+		}
+		for _, id := range chunk.GetIds() {
+			to, err := next.owner(id)
+			if err != nil {
+				return moved, err
+			}
+			if to == from {
+				continue
+			}
+			loader, ok := loaders[to]
+			if !ok {
+				if loader, err = to.client.DataLoader(ctx); err != nil {
+					return moved, fmt.Errorf("%s: %w", to.addr, err)
+				}
+				loaders[to] = loader
+			}
+			ok, err = s.move(ctx, collectionName, id, from, to, loader)
+			if err != nil {
+				return moved, err
+			}
+			if ok {
+				moved++
+			}
+		}
+	}
+}
+
+// move copies a point to its new owner and hands it off from the old one once
+// the copy is acknowledged. The point is locked so no write slips in between.
+// A read meanwhile may find both copies and keeps one, but never neither as
+// the handoff waits for reads in flight. The copy keeps the version of the
+// point, the handoff leaves no tombstone it could lose against if the point
+// ever moves back.
+func (s *shardCoordinator) move(ctx context.Context, collectionName, id string, from, to *backend, loader grpc.BidiStreamingClient[pb.ModifyDataset, pb.Response]) (bool, error) {
+	unlock := s.locks.lock(collectionName, id)
+	defer unlock()
+	row, err := hasPoint(ctx, from, collectionName, id)
+	if err != nil || row == nil {
+		return false, err
+	}
+	err = loader.Send(&pb.ModifyDataset{
+		Id:             id,
+		CollectionName: collectionName,
+		Vector:         row.GetVector(),
+		Metadata:       row.GetMetadata(),
+		Version:        row.GetVersion(),
+		Origin:         row.GetOrigin(),
+		Internal:       true,
+	})
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", to.addr, err)
+	}
+	resp, err := loader.Recv()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", to.addr, err)
+	}
+	if !resp.GetResult() {
+		return false, fmt.Errorf("%s: %s", to.addr, resp.GetErrorMessage())
+	}
+	if _, err := s.handoff(ctx, from, collectionName, id); err != nil {
+		return false, fmt.Errorf("%s: %w", from.addr, err)
+	}
+	return true, nil
+}
+
+// handoff deletes the copy of a point its previous owner still has, without
+// a tombstone. The reads in flight finish first, the reads that start later
+// find the copy of the new owner and do not wait.
+func (s *shardCoordinator) handoff(ctx context.Context, from *backend, collectionName, id string) (*pb.Response, error) {
+	s.reads.wait()
+	return from.client.Delete(ctx, &pb.DeleteDataset{Id: id, CollectionName: collectionName, Handoff: true})
+}
+
+// readers counts the reads in flight by the number of waits started before
+// them, a wait only waits for the reads that were in flight when it started.
+type readers struct {
+	mu       sync.Mutex
+	changed  *sync.Cond
+	waits    uint64
+	inFlight map[uint64]int
+}
+
+func newReaders() *readers {
+	r := &readers{inFlight: make(map[uint64]int)}
+	r.changed = sync.NewCond(&r.mu)
+	return r
+}
+
+// start registers a read, the returned func marks it done.
+func (r *readers) start() func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	waits := r.waits
+	r.inFlight[waits]++
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.inFlight[waits]--; r.inFlight[waits] == 0 {
+			delete(r.inFlight, waits)
+			r.changed.Broadcast()
+		}
+	}
+}
+
+// wait returns once the reads in flight when it was called are done.
+func (r *readers) wait() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	waits := r.waits
+	r.waits++
+	for {
+		earlier := false
+		for w := range r.inFlight {
+			earlier = earlier || w <= waits
+		}
+		if !earlier {
+			return
+		}
+		r.changed.Wait()
+	}
+}
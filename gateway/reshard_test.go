@@ -0,0 +1,60 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadersWait(t *testing.T) {
+	r := newReaders()
+	// a wait without reads in flight returns right away
+	r.wait()
+
+	before := r.start()
+	waited := make(chan struct{})
+	go func() {
+		r.wait()
+		close(waited)
+	}()
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.waits == 2
+	}, time.Second, time.Millisecond)
+	// reads that start meanwhile neither wait nor are waited for
+	after := r.start()
+	select {
+	case <-waited:
+		t.Fatal("the wait returned before the read in flight was done")
+	case <-time.After(50 * time.Millisecond):
+	}
+	before()
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatal("the wait waited for a read that started after it")
+	}
+	after()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	require.Empty(t, r.inFlight)
+}
@@ -0,0 +1,154 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/auth"
+	"github.com/sjy-dv/nnv/pkg/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var ErrNoBackends = errors.New("gateway has no backend servers")
+
+// Server accepts LBCoordinator calls and spreads them over the backend nodes
+// according to the balancer type.
+type Server struct {
+	config      GateWay
+	coordinator coordinator
+	grpcServer  *grpc.Server
+	closing     chan struct{}
+	health      sync.WaitGroup
+}
+
+// coordinator is the LBCoordinator implementation of a balancer type, it owns
+// the backend connections.
+type coordinator interface {
+	pb.LBCoordinatorServer
+	// members are the backends the health check probes
+	members() []*backend
+	close()
+}
+
+func (g GateWay) Addr() string {
+	return net.JoinHostPort(g.Host, g.Port)
+}
+
+// resolveAddrs returns the backend addresses, on k8s every address behind the
+// headless service ServiceName ("host:port") is a backend.
+func (g GateWay) resolveAddrs() ([]string, error) {
+	if g.Infra != K8S {
+		return g.ServerAddrs, nil
+	}
+	host, port, err := net.SplitHostPort(g.ServiceName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service name %s: %w", g.ServiceName, err)
+	}
+	ips, err := net.LookupHost(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve service %s: %w", host, err)
+	}
+	sort.Strings(ips)
+	addrs := make([]string, len(ips))
+	for i, ip := range ips {
+		addrs[i] = net.JoinHostPort(ip, port)
+	}
+	return addrs, nil
+}
+
+// New dials every backend, the connections are plaintext unless dialOpts say
+// otherwise. With authentication on the backends, dialOpts carry the
+// credentials of the gateway, which the backends must trust as a forwarder.
+func New(config GateWay, dialOpts ...grpc.DialOption) (*Server, error) {
+	addrs, err := config.resolveAddrs()
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, ErrNoBackends
+	}
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, auth.ForwardIdentity()...)
+	opts = append(opts, tenant.Forward()...)
+	opts = append(opts, dialOpts...)
+	policy := config.healthPolicy()
+	backends, err := dialBackends(addrs, policy, opts...)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{config: config, closing: make(chan struct{})}
+	switch config.Balancer {
+	case LB:
+		s.coordinator = newReplicaCoordinator(backends)
+	case SLB:
+		s.coordinator, err = newShardCoordinator(backends, config.Placement, func(addr string) (*backend, error) {
+			return dialBackend(addr, policy, opts...)
+		})
+	default:
+		err = fmt.Errorf("unsupported balancer type %d", config.Balancer)
+	}
+	if err != nil {
+		closeBackends(backends)
+		return nil, err
+	}
+	s.health.Add(1)
+	go func() {
+		defer s.health.Done()
+		healthCheck(s.coordinator, policy, s.closing)
+	}()
+	return s, nil
+}
+
+func (s *Server) Serve(lis net.Listener, opts ...grpc.ServerOption) error {
+	if s.config.TLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.config.TLS)))
+	}
+	if s.config.Auth != nil {
+		opts = append(opts, s.config.Auth.ServerOptions()...)
+	}
+	opts = append(opts, tenant.ServerOptions()...)
+	s.grpcServer = grpc.NewServer(opts...)
+	pb.RegisterLBCoordinatorServer(s.grpcServer, s.coordinator)
+	log.Info().Str("addr", lis.Addr().String()).Msg("nnv gateway serving")
+	return s.grpcServer.Serve(lis)
+}
+
+func (s *Server) ListenAndServe(opts ...grpc.ServerOption) error {
+	lis, err := net.Listen("tcp", s.config.Addr())
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.Addr(), err)
+	}
+	return s.Serve(lis, opts...)
+}
+
+func (s *Server) Close() {
+	if s.grpcServer != nil {
+		s.grpcServer.GracefulStop()
+	}
+	close(s.closing)
+	s.health.Wait()
+	s.coordinator.close()
+}
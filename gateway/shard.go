@@ -0,0 +1,800 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/errcode"
+	"github.com/sjy-dv/nnv/pkg/sharding"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	// defaultTopK matches the node default so a sharded search returns as
+	// many rows as a single node would.
+	defaultTopK = 10
+	// the scroll page limits match the node ones so every shard fills the
+	// page the merge cuts
+	defaultScrollLimit = 100
+	maxScrollLimit     = 1000
+)
+
+// shardCoordinator splits the points of every collection across the backends,
+// each point lives on exactly one shard chosen by the placement from its id.
+type shardCoordinator struct {
+	pb.UnimplementedLBCoordinatorServer
+	placementType sharding.PlacementType
+	dial          func(addr string) (*backend, error)
+
+	mu   sync.RWMutex
+	view *view
+
+	locks      pointLocks
+	resharding sync.Mutex
+	// reads register while they gather from the shards, so the old copy of a
+	// moving point is never deleted in the middle of a read
+	reads *readers
+	// reads hold it while they gather from the shards and alias changes
+	// while they go to every shard, no read sees an alias changed on some
+	// shards only
+	aliasing sync.RWMutex
+}
+
+func newShardCoordinator(backends []*backend, placementType sharding.PlacementType, dial func(addr string) (*backend, error)) (*shardCoordinator, error) {
+	t, err := newTopology(backends, placementType)
+	if err != nil {
+		return nil, err
+	}
+	return &shardCoordinator{
+		placementType: placementType,
+		dial:          dial,
+		view:          &view{current: t},
+		reads:         newReaders(),
+	}, nil
+}
+
+func (s *shardCoordinator) close() {
+	v := s.acquire()
+	defer v.release()
+	closeBackends(v.backends())
+}
+
+func (s *shardCoordinator) members() []*backend {
+	v := s.acquire()
+	defer v.release()
+	return v.backends()
+}
+
+func invalidId(err error) error {
+	return errcode.Error(pb.ErrorCode_INVALID_ARGUMENT, err.Error(), nil)
+}
+
+func (s *shardCoordinator) Ping(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (s *shardCoordinator) CreateCollection(ctx context.Context, req *pb.Collection) (*pb.CollectionResponse, error) {
+	v := s.acquire()
+	defer v.release()
+	return createCollection(ctx, v.backends(), req)
+}
+
+func (s *shardCoordinator) DropCollection(ctx context.Context, req *pb.CollectionName) (*pb.Response, error) {
+	v := s.acquire()
+	defer v.release()
+	return dropCollection(ctx, v.backends(), req)
+}
+
+// mergeCollection adds up the sizes the shards report for one collection.
+func mergeCollection(shards []*pb.Collection) *pb.Collection {
+	merged := &pb.Collection{
+		Tenant:          shards[0].GetTenant(),
+		CollectionName:  shards[0].GetCollectionName(),
+		Dimension:       shards[0].GetDimension(),
+		InvertedIndex:   shards[0].GetInvertedIndex(),
+		VectorIndex:     shards[0].GetVectorIndex(),
+		CreateTimestamp: shards[0].GetCreateTimestamp(),
+	}
+	for _, shard := range shards {
+		merged.CollectionSize += shard.GetCollectionSize()
+		merged.DiskSize += shard.GetDiskSize()
+	}
+	return merged
+}
+
+func (s *shardCoordinator) GetCollection(ctx context.Context, req *pb.CollectionName) (*pb.Collection, error) {
+	v := s.acquire()
+	defer v.release()
+	s.aliasing.RLock()
+	shards, err := gather(ctx, v.backends(), func(ctx context.Context, b *backend) (*pb.Collection, error) {
+		col, err := b.client.GetCollection(ctx, req)
+		if err != nil {
+			err = shardFailure(b, err)
+		}
+		return col, err
+	})
+	s.aliasing.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	return mergeCollection(shards), nil
+}
+
+func (s *shardCoordinator) ListCollection(ctx context.Context, req *emptypb.Empty) (*pb.CollectionList, error) {
+	v := s.acquire()
+	defer v.release()
+	lists, err := gather(ctx, v.backends(), func(ctx context.Context, b *backend) (*pb.CollectionList, error) {
+		list, err := b.client.ListCollection(ctx, req)
+		if err != nil {
+			err = shardFailure(b, err)
+		}
+		return list, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// listings of every tenant have the same name in several tenants
+	type key struct{ tenant, name string }
+	byName := make(map[key][]*pb.Collection)
+	var names []key
+	for _, list := range lists {
+		for _, col := range list.GetCollections() {
+			k := key{col.GetTenant(), col.GetCollectionName()}
+			if _, ok := byName[k]; !ok {
+				names = append(names, k)
+			}
+			byName[k] = append(byName[k], col)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].tenant != names[j].tenant {
+			return names[i].tenant < names[j].tenant
+		}
+		return names[i].name < names[j].name
+	})
+	merged := &pb.CollectionList{}
+	for _, name := range names {
+		col := mergeCollection(byName[name])
+		merged.Collections = append(merged.Collections, col)
+		merged.TotalSize += col.GetDiskSize()
+	}
+	merged.Count = uint64(len(merged.Collections))
+	return merged, nil
+}
+
+// CreateAlias, PointAlias, SwapAliases and DeleteAlias change the alias on
+// every shard before any read can see it.
+func (s *shardCoordinator) CreateAlias(ctx context.Context, req *pb.Alias) (*pb.Response, error) {
+	v := s.acquire()
+	defer v.release()
+	s.aliasing.Lock()
+	defer s.aliasing.Unlock()
+	return createAlias(ctx, v.backends(), req)
+}
+
+func (s *shardCoordinator) PointAlias(ctx context.Context, req *pb.Alias) (*pb.Response, error) {
+	v := s.acquire()
+	defer v.release()
+	s.aliasing.Lock()
+	defer s.aliasing.Unlock()
+	return pointAlias(ctx, v.backends(), req)
+}
+
+func (s *shardCoordinator) SwapAliases(ctx context.Context, req *pb.SwapAliasesReq) (*pb.Response, error) {
+	v := s.acquire()
+	defer v.release()
+	s.aliasing.Lock()
+	defer s.aliasing.Unlock()
+	return swapAliases(ctx, v.backends(), req)
+}
+
+func (s *shardCoordinator) DeleteAlias(ctx context.Context, req *pb.AliasName) (*pb.Response, error) {
+	v := s.acquire()
+	defer v.release()
+	s.aliasing.Lock()
+	defer s.aliasing.Unlock()
+	return deleteAlias(ctx, v.backends(), req)
+}
+
+// ListAliases keeps an alias once, a shard that missed a change shows up
+// behind the others.
+func (s *shardCoordinator) ListAliases(ctx context.Context, req *emptypb.Empty) (*pb.AliasList, error) {
+	v := s.acquire()
+	defer v.release()
+	s.aliasing.RLock()
+	lists, err := gather(ctx, v.backends(), func(ctx context.Context, b *backend) (*pb.AliasList, error) {
+		list, err := b.client.ListAliases(ctx, req)
+		if err != nil {
+			err = shardFailure(b, err)
+		}
+		return list, err
+	})
+	s.aliasing.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	type key struct{ tenant, alias string }
+	seen := make(map[key]bool)
+	merged := &pb.AliasList{}
+	for _, list := range lists {
+		for _, alias := range list.GetAliases() {
+			k := key{alias.GetTenant(), alias.GetAlias()}
+			if !seen[k] {
+				seen[k] = true
+				merged.Aliases = append(merged.Aliases, alias)
+			}
+		}
+	}
+	sort.Slice(merged.Aliases, func(i, j int) bool {
+		a, b := merged.Aliases[i], merged.Aliases[j]
+		if a.GetTenant() != b.GetTenant() {
+			return a.GetTenant() < b.GetTenant()
+		}
+		return a.GetAlias() < b.GetAlias()
+	})
+	return merged, nil
+}
+
+func call(ctx context.Context, b *backend, fn func(context.Context, *backend) (*pb.Response, error)) (*pb.Response, error) {
+	resp, err := fn(ctx, b)
+	return respond(failure([]result{{backend: b, resp: resp, err: err}}))
+}
+
+func (s *shardCoordinator) Insert(ctx context.Context, req *pb.ModifyDataset) (*pb.Response, error) {
+	v := s.acquire()
+	defer v.release()
+	unlock := s.locks.lock(req.GetCollectionName(), req.GetId())
+	defer unlock()
+	current, previous, err := v.owners(req.GetId())
+	if err != nil {
+		return nil, invalidId(err)
+	}
+	// a point that has not moved yet still counts as existing
+	if previous != nil {
+		row, err := hasPoint(ctx, previous, req.GetCollectionName(), req.GetId())
+		if err != nil {
+			return nil, err
+		}
+		if row != nil && req.GetPrecondition() != nil {
+			// the owner that has the point decides how the write fails
+			return call(ctx, previous, func(ctx context.Context, b *backend) (*pb.Response, error) {
+				return b.client.Insert(ctx, req)
+			})
+		}
+		if row != nil {
+			return nil, rejected(previous, pb.ErrorCode_ALREADY_EXISTS, fmt.Sprintf("point %s already exists", req.GetId()))
+		}
+	}
+	return call(ctx, current, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.Insert(ctx, req)
+	})
+}
+
+func (s *shardCoordinator) Update(ctx context.Context, req *pb.ModifyDataset) (*pb.Response, error) {
+	v := s.acquire()
+	defer v.release()
+	unlock := s.locks.lock(req.GetCollectionName(), req.GetId())
+	defer unlock()
+	target, err := v.holder(ctx, req.GetCollectionName(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return call(ctx, target, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.Update(ctx, req)
+	})
+}
+
+// PatchMetadata goes where an update of the point would.
+func (s *shardCoordinator) PatchMetadata(ctx context.Context, req *pb.PatchMetadataReq) (*pb.Response, error) {
+	v := s.acquire()
+	defer v.release()
+	unlock := s.locks.lock(req.GetCollectionName(), req.GetId())
+	defer unlock()
+	target, err := v.holder(ctx, req.GetCollectionName(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return call(ctx, target, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.PatchMetadata(ctx, req)
+	})
+}
+
+func (s *shardCoordinator) Delete(ctx context.Context, req *pb.DeleteDataset) (*pb.Response, error) {
+	v := s.acquire()
+	defer v.release()
+	unlock := s.locks.lock(req.GetCollectionName(), req.GetId())
+	defer unlock()
+	current, previous, err := v.owners(req.GetId())
+	if err != nil {
+		return nil, invalidId(err)
+	}
+	del := func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return b.client.Delete(ctx, req)
+	}
+	if previous == nil {
+		return call(ctx, current, del)
+	}
+	// a precondition only holds on the owner that has the point
+	if req.GetPrecondition() != nil {
+		target, err := v.holder(ctx, req.GetCollectionName(), req.GetId())
+		if err != nil {
+			return nil, err
+		}
+		return call(ctx, target, del)
+	}
+	// the point is on one of the two owners
+	results := fanOut(ctx, []*backend{current, previous}, del)
+	for _, res := range results {
+		if res.ok() {
+			return respond(nil)
+		}
+	}
+	return respond(failure(results))
+}
+
+// load upserts a point while migrating. The point is written to its current
+// owner first and only then handed off from the previous one, so a failed
+// write leaves the old copy in place and the old copy cannot move over the
+// loaded one afterwards.
+func (s *shardCoordinator) load(ctx context.Context, req *pb.ModifyDataset) (*pb.Response, error) {
+	v := s.acquire()
+	defer v.release()
+	unlock := s.locks.lock(req.GetCollectionName(), req.GetId())
+	defer unlock()
+	current, previous, err := v.owners(req.GetId())
+	if err != nil {
+		return nil, invalidId(err)
+	}
+	row, err := hasPoint(ctx, current, req.GetCollectionName(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := call(ctx, current, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		if row != nil {
+			return b.client.Update(ctx, req)
+		}
+		return b.client.Insert(ctx, req)
+	})
+	if err != nil || previous == nil {
+		return resp, err
+	}
+	return call(ctx, previous, func(ctx context.Context, b *backend) (*pb.Response, error) {
+		return s.handoff(ctx, b, req.GetCollectionName(), req.GetId())
+	})
+}
+
+// pending is a received stream item waiting for its answer, either from the
+// shard it was forwarded to or already known when it could not be routed.
+type pending struct {
+	shard int
+	resp  *pb.Response
+}
+
+// demux splits one client stream into a stream per shard. Every shard answers
+// its items in order, so the answers are put back into the order the client
+// sent the items by replaying the shard of every item.
+func demux[T any](
+	t *topology,
+	stream grpc.BidiStreamingServer[T, pb.Response],
+	open func(context.Context, *backend) (grpc.BidiStreamingClient[T, pb.Response], error),
+	pointId func(*T) string,
+) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	shardStreams := make([]grpc.BidiStreamingClient[T, pb.Response], len(t.backends))
+	answers := make([]chan *pb.Response, len(t.backends))
+	failures := make([]error, len(t.backends))
+	queue := make(chan pending, 1024)
+	sendErr := make(chan error, 1)
+
+	// the sender replays the item order and stops waiting on a shard whose
+	// stream broke, the remaining items of that shard get its error
+	go func() {
+		var err error
+		for p := range queue {
+			if p.resp == nil {
+				resp, ok := <-answers[p.shard]
+				if !ok {
+					resp = errResponse(shardFailure(t.backends[p.shard], failures[p.shard]))
+				}
+				p.resp = resp
+			}
+			if err == nil {
+				err = stream.Send(p.resp)
+			}
+		}
+		sendErr <- err
+	}()
+
+	shardStream := func(shard int) (grpc.BidiStreamingClient[T, pb.Response], error) {
+		if shardStreams[shard] != nil {
+			return shardStreams[shard], nil
+		}
+		b := t.backends[shard]
+		ss, err := open(ctx, b)
+		if err != nil {
+			return nil, err
+		}
+		shardStreams[shard] = ss
+		// at most the queued items and the one in hand of each side are
+		// unanswered, so a shard can always buffer its answers
+		answers[shard] = make(chan *pb.Response, cap(queue)+2)
+		go func() {
+			defer close(answers[shard])
+			for {
+				resp, err := ss.Recv()
+				if err != nil {
+					if err == io.EOF {
+						err = fmt.Errorf("shard stream closed early")
+					}
+					failures[shard] = err
+					return
+				}
+				answers[shard] <- resp
+			}
+		}()
+		return ss, nil
+	}
+
+	var recvErr error
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			recvErr = err
+			break
+		}
+		shard, err := t.locate(pointId(req))
+		if err != nil {
+			queue <- pending{resp: errResponse(invalidId(err))}
+			continue
+		}
+		ss, err := shardStream(shard)
+		if err != nil {
+			queue <- pending{resp: errResponse(shardFailure(t.backends[shard], err))}
+			continue
+		}
+		// a failed send shows up as a closed answer channel
+		ss.Send(req)
+		queue <- pending{shard: shard}
+	}
+	for _, ss := range shardStreams {
+		if ss != nil {
+			ss.CloseSend()
+		}
+	}
+	close(queue)
+	if err := <-sendErr; err != nil {
+		return err
+	}
+	return recvErr
+}
+
+func modifyId(req *pb.ModifyDataset) string { return req.GetId() }
+
+func deleteId(req *pb.DeleteDataset) string { return req.GetId() }
+
+// batch demultiplexes a stream over the shards, while points move every item
+// goes through the per point path instead.
+func batch[T any](
+	s *shardCoordinator,
+	stream grpc.BidiStreamingServer[T, pb.Response],
+	open func(context.Context, *backend) (grpc.BidiStreamingClient[T, pb.Response], error),
+	pointId func(*T) string,
+	handle func(context.Context, *T) (*pb.Response, error),
+) error {
+	v := s.acquire()
+	defer v.release()
+	if v.migrating() {
+		return serveStream(stream, handle)
+	}
+	return demux(v.current, stream, open, pointId)
+}
+
+func (s *shardCoordinator) BatchInsert(stream grpc.BidiStreamingServer[pb.ModifyDataset, pb.Response]) error {
+	return batch(s, stream, func(ctx context.Context, b *backend) (grpc.BidiStreamingClient[pb.ModifyDataset, pb.Response], error) {
+		return b.client.BatchInsert(ctx)
+	}, modifyId, s.Insert)
+}
+
+func (s *shardCoordinator) BatchUpdate(stream grpc.BidiStreamingServer[pb.ModifyDataset, pb.Response]) error {
+	return batch(s, stream, func(ctx context.Context, b *backend) (grpc.BidiStreamingClient[pb.ModifyDataset, pb.Response], error) {
+		return b.client.BatchUpdate(ctx)
+	}, modifyId, s.Update)
+}
+
+func (s *shardCoordinator) BatchDelete(stream grpc.BidiStreamingServer[pb.DeleteDataset, pb.Response]) error {
+	return batch(s, stream, func(ctx context.Context, b *backend) (grpc.BidiStreamingClient[pb.DeleteDataset, pb.Response], error) {
+		return b.client.BatchDelete(ctx)
+	}, deleteId, s.Delete)
+}
+
+func (s *shardCoordinator) DataLoader(stream grpc.BidiStreamingServer[pb.ModifyDataset, pb.Response]) error {
+	return batch(s, stream, func(ctx context.Context, b *backend) (grpc.BidiStreamingClient[pb.ModifyDataset, pb.Response], error) {
+		return b.client.DataLoader(ctx)
+	}, modifyId, s.load)
+}
+
+func searchFailure(b *backend, resp *pb.SearchResponse) *pb.SearchResponse {
+	return &pb.SearchResponse{
+		Result:       false,
+		ErrorMessage: fmt.Sprintf("%s: %s", b.addr, resp.GetErrorMessage()),
+		ErrorCode:    shardCode(resp.GetErrorCode()),
+	}
+}
+
+// mergeRows keeps the topK best scored rows of all shards. A point is only
+// counted once should a shard still hold a stale copy.
+func mergeRows(shards [][]*pb.Row, topK int, minScore float32) []*pb.Row {
+	best := make(map[string]*pb.Row)
+	for _, rows := range shards {
+		for _, row := range rows {
+			if row.GetScore() < minScore {
+				continue
+			}
+			if prev, ok := best[row.GetId()]; !ok || row.GetScore() > prev.GetScore() {
+				best[row.GetId()] = row
+			}
+		}
+	}
+	merged := make([]*pb.Row, 0, len(best))
+	for _, row := range best {
+		merged = append(merged, row)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].GetScore() != merged[j].GetScore() {
+			return merged[i].GetScore() > merged[j].GetScore()
+		}
+		return merged[i].GetId() < merged[j].GetId()
+	})
+	if len(merged) > topK {
+		merged = merged[:topK]
+	}
+	return merged
+}
+
+// Search asks every shard for its own top K and merges them, the global top K
+// is always among them. While resharding a moving point can be on two shards
+// at once, the merge keeps it once.
+func (s *shardCoordinator) Search(ctx context.Context, req *pb.SearchReq) (*pb.SearchResponse, error) {
+	startTime := time.Now()
+	v := s.acquire()
+	defer v.release()
+	backends := v.backends()
+	s.aliasing.RLock()
+	finish := s.reads.start()
+	resps, err := gather(ctx, backends, func(ctx context.Context, b *backend) (*pb.SearchResponse, error) {
+		resp, err := b.client.Search(ctx, req)
+		if err != nil {
+			err = shardFailure(b, err)
+		}
+		return resp, err
+	})
+	finish()
+	s.aliasing.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	resp := mergeSearch(backends, resps, req)
+	resp.Latency = time.Since(startTime).String()
+	return resp, nil
+}
+
+// mergeSearch merges the answers of the shards to one search, resps are in
+// the order of backends.
+func mergeSearch(backends []*backend, resps []*pb.SearchResponse, req *pb.SearchReq) *pb.SearchResponse {
+	shards := make([][]*pb.Row, len(resps))
+	var partial bool
+	for i, resp := range resps {
+		if !resp.GetResult() {
+			return searchFailure(backends[i], resp)
+		}
+		shards[i] = resp.GetResponse()
+		partial = partial || resp.GetPartial()
+	}
+	topK := int(req.GetTopK())
+	if topK <= 0 {
+		topK = defaultTopK
+	}
+	return &pb.SearchResponse{
+		Result:   true,
+		Response: mergeRows(shards, topK, req.GetMinScore()),
+		Partial:  partial,
+	}
+}
+
+// BatchSearch sends the whole batch to every shard and merges every search
+// on its own, as Search does.
+func (s *shardCoordinator) BatchSearch(ctx context.Context, req *pb.BatchSearchReq) (*pb.BatchSearchResponse, error) {
+	startTime := time.Now()
+	v := s.acquire()
+	defer v.release()
+	backends := v.backends()
+	s.aliasing.RLock()
+	finish := s.reads.start()
+	resps, err := gather(ctx, backends, func(ctx context.Context, b *backend) (*pb.BatchSearchResponse, error) {
+		resp, err := b.client.BatchSearch(ctx, req)
+		if err != nil {
+			err = shardFailure(b, err)
+		}
+		return resp, err
+	})
+	finish()
+	s.aliasing.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	for i, resp := range resps {
+		if len(resp.GetResponses()) != len(req.GetSearches()) {
+			return nil, rejected(backends[i], pb.ErrorCode_UNDEFINED, fmt.Sprintf("answered %d of %d searches", len(resp.GetResponses()), len(req.GetSearches())))
+		}
+	}
+	merged := &pb.BatchSearchResponse{Result: true, Responses: make([]*pb.SearchResponse, len(req.GetSearches()))}
+	shardResps := make([]*pb.SearchResponse, len(resps))
+	for j, search := range req.GetSearches() {
+		for i, resp := range resps {
+			shardResps[i] = resp.GetResponses()[j]
+		}
+		merged.Responses[j] = mergeSearch(backends, shardResps, search)
+	}
+	merged.Latency = time.Since(startTime).String()
+	return merged, nil
+}
+
+// GetPoints asks every shard for the ids it owns and answers in request order.
+func (s *shardCoordinator) GetPoints(ctx context.Context, req *pb.PointIds) (*pb.PointsResponse, error) {
+	v := s.acquire()
+	defer v.release()
+	backends := v.backends()
+	perShard := make(map[*backend][]string, len(backends))
+	for _, id := range req.GetIds() {
+		current, previous, err := v.owners(id)
+		if err != nil {
+			return nil, invalidId(err)
+		}
+		perShard[current] = append(perShard[current], id)
+		if previous != nil {
+			perShard[previous] = append(perShard[previous], id)
+		}
+	}
+	s.aliasing.RLock()
+	finish := s.reads.start()
+	resps, err := gather(ctx, backends, func(ctx context.Context, b *backend) (*pb.PointsResponse, error) {
+		ids := perShard[b]
+		if len(ids) == 0 {
+			return &pb.PointsResponse{Result: true}, nil
+		}
+		resp, err := b.client.GetPoints(ctx, &pb.PointIds{
+			CollectionName: req.GetCollectionName(),
+			Ids:            ids,
+			WithVector:     req.WithVector,
+			WithMetadata:   req.WithMetadata,
+		})
+		if err != nil {
+			err = shardFailure(b, err)
+		}
+		return resp, err
+	})
+	finish()
+	s.aliasing.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	rows := make(map[string]*pb.Row)
+	for _, resp := range resps {
+		for _, row := range resp.GetPoints() {
+			rows[row.GetId()] = row
+		}
+	}
+	points := make([]*pb.Row, 0, len(rows))
+	for _, id := range req.GetIds() {
+		if row, ok := rows[id]; ok {
+			points = append(points, row)
+			delete(rows, id)
+		}
+	}
+	return &pb.PointsResponse{Result: true, Points: points}, nil
+}
+
+// Scroll asks every shard for a page after the cursor and keeps the first
+// points of their union in id order, the page of the whole collection is
+// always among them. A point that is being moved is kept once.
+func (s *shardCoordinator) Scroll(ctx context.Context, req *pb.ScrollReq) (*pb.ScrollResponse, error) {
+	v := s.acquire()
+	defer v.release()
+	backends := v.backends()
+	s.aliasing.RLock()
+	finish := s.reads.start()
+	resps, err := gather(ctx, backends, func(ctx context.Context, b *backend) (*pb.ScrollResponse, error) {
+		resp, err := b.client.Scroll(ctx, req)
+		if err != nil {
+			err = shardFailure(b, err)
+		}
+		return resp, err
+	})
+	finish()
+	s.aliasing.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	rows := make(map[string]*pb.Row)
+	more := false
+	for _, resp := range resps {
+		for _, row := range resp.GetPoints() {
+			rows[row.GetId()] = row
+		}
+		more = more || resp.GetNextCursor() != ""
+	}
+	points := make([]*pb.Row, 0, len(rows))
+	for _, row := range rows {
+		points = append(points, row)
+	}
+	// ids share one length, so string order is id order
+	sort.Slice(points, func(i, j int) bool { return points[i].GetId() < points[j].GetId() })
+	limit := int(min(req.GetLimit(), maxScrollLimit))
+	if limit <= 0 {
+		limit = defaultScrollLimit
+	}
+	if limit < len(points) {
+		points = points[:limit]
+		more = true
+	}
+	resp := &pb.ScrollResponse{Result: true, Points: points}
+	if more && len(points) > 0 {
+		resp.NextCursor = points[len(points)-1].GetId()
+	}
+	return resp, nil
+}
+
+// Count adds up the counts of the shards. While resharding a point that is
+// being moved may be counted on both of its owners.
+func (s *shardCoordinator) Count(ctx context.Context, req *pb.CountReq) (*pb.CountResponse, error) {
+	v := s.acquire()
+	defer v.release()
+	backends := v.backends()
+	s.aliasing.RLock()
+	finish := s.reads.start()
+	resps, err := gather(ctx, backends, func(ctx context.Context, b *backend) (*pb.CountResponse, error) {
+		resp, err := b.client.Count(ctx, req)
+		if err != nil {
+			err = shardFailure(b, err)
+		}
+		return resp, err
+	})
+	finish()
+	s.aliasing.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	total := &pb.CountResponse{Result: true}
+	for _, resp := range resps {
+		total.Count += resp.GetCount()
+	}
+	return total, nil
+}
@@ -42,14 +42,28 @@ const (
 	LBCoordinator_DropCollection_FullMethodName   = "/balancerCommunicationV1.LBCoordinator/DropCollection"
 	LBCoordinator_GetCollection_FullMethodName    = "/balancerCommunicationV1.LBCoordinator/GetCollection"
 	LBCoordinator_ListCollection_FullMethodName   = "/balancerCommunicationV1.LBCoordinator/ListCollection"
+	LBCoordinator_CreateAlias_FullMethodName      = "/balancerCommunicationV1.LBCoordinator/CreateAlias"
+	LBCoordinator_PointAlias_FullMethodName       = "/balancerCommunicationV1.LBCoordinator/PointAlias"
+	LBCoordinator_SwapAliases_FullMethodName      = "/balancerCommunicationV1.LBCoordinator/SwapAliases"
+	LBCoordinator_DeleteAlias_FullMethodName      = "/balancerCommunicationV1.LBCoordinator/DeleteAlias"
+	LBCoordinator_ListAliases_FullMethodName      = "/balancerCommunicationV1.LBCoordinator/ListAliases"
 	LBCoordinator_Insert_FullMethodName           = "/balancerCommunicationV1.LBCoordinator/Insert"
 	LBCoordinator_Update_FullMethodName           = "/balancerCommunicationV1.LBCoordinator/Update"
 	LBCoordinator_Delete_FullMethodName           = "/balancerCommunicationV1.LBCoordinator/Delete"
+	LBCoordinator_PatchMetadata_FullMethodName    = "/balancerCommunicationV1.LBCoordinator/PatchMetadata"
 	LBCoordinator_BatchInsert_FullMethodName      = "/balancerCommunicationV1.LBCoordinator/BatchInsert"
 	LBCoordinator_BatchUpdate_FullMethodName      = "/balancerCommunicationV1.LBCoordinator/BatchUpdate"
 	LBCoordinator_BatchDelete_FullMethodName      = "/balancerCommunicationV1.LBCoordinator/BatchDelete"
 	LBCoordinator_Search_FullMethodName           = "/balancerCommunicationV1.LBCoordinator/Search"
+	LBCoordinator_BatchSearch_FullMethodName      = "/balancerCommunicationV1.LBCoordinator/BatchSearch"
+	LBCoordinator_GetPoints_FullMethodName        = "/balancerCommunicationV1.LBCoordinator/GetPoints"
+	LBCoordinator_ListPointIds_FullMethodName     = "/balancerCommunicationV1.LBCoordinator/ListPointIds"
+	LBCoordinator_Scroll_FullMethodName           = "/balancerCommunicationV1.LBCoordinator/Scroll"
+	LBCoordinator_Count_FullMethodName            = "/balancerCommunicationV1.LBCoordinator/Count"
 	LBCoordinator_DataLoader_FullMethodName       = "/balancerCommunicationV1.LBCoordinator/DataLoader"
+	LBCoordinator_Reshard_FullMethodName          = "/balancerCommunicationV1.LBCoordinator/Reshard"
+	LBCoordinator_GetMerkleTree_FullMethodName    = "/balancerCommunicationV1.LBCoordinator/GetMerkleTree"
+	LBCoordinator_CheckDivergence_FullMethodName  = "/balancerCommunicationV1.LBCoordinator/CheckDivergence"
 )
 
 // LBCoordinatorClient is the client API for LBCoordinator service.
@@ -63,16 +77,43 @@ type LBCoordinatorClient interface {
 	DropCollection(ctx context.Context, in *CollectionName, opts ...grpc.CallOption) (*Response, error)
 	GetCollection(ctx context.Context, in *CollectionName, opts ...grpc.CallOption) (*Collection, error)
 	ListCollection(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CollectionList, error)
+	// aliases name a collection, every request that names a collection may
+	// name an alias of it instead
+	CreateAlias(ctx context.Context, in *Alias, opts ...grpc.CallOption) (*Response, error)
+	// points an existing alias to another collection
+	PointAlias(ctx context.Context, in *Alias, opts ...grpc.CallOption) (*Response, error)
+	// exchanges the collections of two aliases at once
+	SwapAliases(ctx context.Context, in *SwapAliasesReq, opts ...grpc.CallOption) (*Response, error)
+	DeleteAlias(ctx context.Context, in *AliasName, opts ...grpc.CallOption) (*Response, error)
+	ListAliases(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*AliasList, error)
 	// vector data communication
 	Insert(ctx context.Context, in *ModifyDataset, opts ...grpc.CallOption) (*Response, error)
 	Update(ctx context.Context, in *ModifyDataset, opts ...grpc.CallOption) (*Response, error)
 	Delete(ctx context.Context, in *DeleteDataset, opts ...grpc.CallOption) (*Response, error)
+	// changes single metadata fields of a point, the vector stays as is
+	PatchMetadata(ctx context.Context, in *PatchMetadataReq, opts ...grpc.CallOption) (*Response, error)
 	BatchInsert(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ModifyDataset, Response], error)
 	BatchUpdate(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ModifyDataset, Response], error)
 	BatchDelete(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[DeleteDataset, Response], error)
 	Search(ctx context.Context, in *SearchReq, opts ...grpc.CallOption) (*SearchResponse, error)
+	// runs many searches on one collection, responses keep the request order
+	BatchSearch(ctx context.Context, in *BatchSearchReq, opts ...grpc.CallOption) (*BatchSearchResponse, error)
+	// point retrieval
+	GetPoints(ctx context.Context, in *PointIds, opts ...grpc.CallOption) (*PointsResponse, error)
+	ListPointIds(ctx context.Context, in *CollectionName, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PointIds], error)
+	// pages through the points of a collection in id order
+	Scroll(ctx context.Context, in *ScrollReq, opts ...grpc.CallOption) (*ScrollResponse, error)
+	Count(ctx context.Context, in *CountReq, opts ...grpc.CallOption) (*CountResponse, error)
 	// sync
 	DataLoader(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ModifyDataset, Response], error)
+	// gateway only, shard mode moves points to the owners under the new server list
+	Reshard(ctx context.Context, in *ReshardReq, opts ...grpc.CallOption) (*ReshardResponse, error)
+	// anti-entropy between replicas, hashes of one level of the merkle tree
+	// over the point ids and versions of a collection
+	GetMerkleTree(ctx context.Context, in *MerkleReq, opts ...grpc.CallOption) (*MerkleTree, error)
+	// admin, compares every collection with the peers of the node and
+	// optionally repairs the differing ranges
+	CheckDivergence(ctx context.Context, in *DivergenceReq, opts ...grpc.CallOption) (*DivergenceReport, error)
 }
 
 type lBCoordinatorClient struct {
@@ -133,6 +174,56 @@ func (c *lBCoordinatorClient) ListCollection(ctx context.Context, in *emptypb.Em
 	return out, nil
 }
 
+func (c *lBCoordinatorClient) CreateAlias(ctx context.Context, in *Alias, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, LBCoordinator_CreateAlias_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lBCoordinatorClient) PointAlias(ctx context.Context, in *Alias, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, LBCoordinator_PointAlias_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lBCoordinatorClient) SwapAliases(ctx context.Context, in *SwapAliasesReq, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, LBCoordinator_SwapAliases_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lBCoordinatorClient) DeleteAlias(ctx context.Context, in *AliasName, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, LBCoordinator_DeleteAlias_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lBCoordinatorClient) ListAliases(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*AliasList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AliasList)
+	err := c.cc.Invoke(ctx, LBCoordinator_ListAliases_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lBCoordinatorClient) Insert(ctx context.Context, in *ModifyDataset, opts ...grpc.CallOption) (*Response, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Response)
@@ -163,6 +254,16 @@ func (c *lBCoordinatorClient) Delete(ctx context.Context, in *DeleteDataset, opt
 	return out, nil
 }
 
+func (c *lBCoordinatorClient) PatchMetadata(ctx context.Context, in *PatchMetadataReq, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, LBCoordinator_PatchMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lBCoordinatorClient) BatchInsert(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ModifyDataset, Response], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &LBCoordinator_ServiceDesc.Streams[0], LBCoordinator_BatchInsert_FullMethodName, cOpts...)
@@ -212,9 +313,68 @@ func (c *lBCoordinatorClient) Search(ctx context.Context, in *SearchReq, opts ..
 	return out, nil
 }
 
+func (c *lBCoordinatorClient) BatchSearch(ctx context.Context, in *BatchSearchReq, opts ...grpc.CallOption) (*BatchSearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchSearchResponse)
+	err := c.cc.Invoke(ctx, LBCoordinator_BatchSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lBCoordinatorClient) GetPoints(ctx context.Context, in *PointIds, opts ...grpc.CallOption) (*PointsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PointsResponse)
+	err := c.cc.Invoke(ctx, LBCoordinator_GetPoints_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lBCoordinatorClient) ListPointIds(ctx context.Context, in *CollectionName, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PointIds], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LBCoordinator_ServiceDesc.Streams[3], LBCoordinator_ListPointIds_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CollectionName, PointIds]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LBCoordinator_ListPointIdsClient = grpc.ServerStreamingClient[PointIds]
+
+func (c *lBCoordinatorClient) Scroll(ctx context.Context, in *ScrollReq, opts ...grpc.CallOption) (*ScrollResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScrollResponse)
+	err := c.cc.Invoke(ctx, LBCoordinator_Scroll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lBCoordinatorClient) Count(ctx context.Context, in *CountReq, opts ...grpc.CallOption) (*CountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountResponse)
+	err := c.cc.Invoke(ctx, LBCoordinator_Count_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lBCoordinatorClient) DataLoader(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ModifyDataset, Response], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &LBCoordinator_ServiceDesc.Streams[3], LBCoordinator_DataLoader_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &LBCoordinator_ServiceDesc.Streams[4], LBCoordinator_DataLoader_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -225,6 +385,36 @@ func (c *lBCoordinatorClient) DataLoader(ctx context.Context, opts ...grpc.CallO
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type LBCoordinator_DataLoaderClient = grpc.BidiStreamingClient[ModifyDataset, Response]
 
+func (c *lBCoordinatorClient) Reshard(ctx context.Context, in *ReshardReq, opts ...grpc.CallOption) (*ReshardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReshardResponse)
+	err := c.cc.Invoke(ctx, LBCoordinator_Reshard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lBCoordinatorClient) GetMerkleTree(ctx context.Context, in *MerkleReq, opts ...grpc.CallOption) (*MerkleTree, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MerkleTree)
+	err := c.cc.Invoke(ctx, LBCoordinator_GetMerkleTree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lBCoordinatorClient) CheckDivergence(ctx context.Context, in *DivergenceReq, opts ...grpc.CallOption) (*DivergenceReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DivergenceReport)
+	err := c.cc.Invoke(ctx, LBCoordinator_CheckDivergence_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LBCoordinatorServer is the server API for LBCoordinator service.
 // All implementations should embed UnimplementedLBCoordinatorServer
 // for forward compatibility.
@@ -236,16 +426,43 @@ type LBCoordinatorServer interface {
 	DropCollection(context.Context, *CollectionName) (*Response, error)
 	GetCollection(context.Context, *CollectionName) (*Collection, error)
 	ListCollection(context.Context, *emptypb.Empty) (*CollectionList, error)
+	// aliases name a collection, every request that names a collection may
+	// name an alias of it instead
+	CreateAlias(context.Context, *Alias) (*Response, error)
+	// points an existing alias to another collection
+	PointAlias(context.Context, *Alias) (*Response, error)
+	// exchanges the collections of two aliases at once
+	SwapAliases(context.Context, *SwapAliasesReq) (*Response, error)
+	DeleteAlias(context.Context, *AliasName) (*Response, error)
+	ListAliases(context.Context, *emptypb.Empty) (*AliasList, error)
 	// vector data communication
 	Insert(context.Context, *ModifyDataset) (*Response, error)
 	Update(context.Context, *ModifyDataset) (*Response, error)
 	Delete(context.Context, *DeleteDataset) (*Response, error)
+	// changes single metadata fields of a point, the vector stays as is
+	PatchMetadata(context.Context, *PatchMetadataReq) (*Response, error)
 	BatchInsert(grpc.BidiStreamingServer[ModifyDataset, Response]) error
 	BatchUpdate(grpc.BidiStreamingServer[ModifyDataset, Response]) error
 	BatchDelete(grpc.BidiStreamingServer[DeleteDataset, Response]) error
 	Search(context.Context, *SearchReq) (*SearchResponse, error)
+	// runs many searches on one collection, responses keep the request order
+	BatchSearch(context.Context, *BatchSearchReq) (*BatchSearchResponse, error)
+	// point retrieval
+	GetPoints(context.Context, *PointIds) (*PointsResponse, error)
+	ListPointIds(*CollectionName, grpc.ServerStreamingServer[PointIds]) error
+	// pages through the points of a collection in id order
+	Scroll(context.Context, *ScrollReq) (*ScrollResponse, error)
+	Count(context.Context, *CountReq) (*CountResponse, error)
 	// sync
 	DataLoader(grpc.BidiStreamingServer[ModifyDataset, Response]) error
+	// gateway only, shard mode moves points to the owners under the new server list
+	Reshard(context.Context, *ReshardReq) (*ReshardResponse, error)
+	// anti-entropy between replicas, hashes of one level of the merkle tree
+	// over the point ids and versions of a collection
+	GetMerkleTree(context.Context, *MerkleReq) (*MerkleTree, error)
+	// admin, compares every collection with the peers of the node and
+	// optionally repairs the differing ranges
+	CheckDivergence(context.Context, *DivergenceReq) (*DivergenceReport, error)
 }
 
 // UnimplementedLBCoordinatorServer should be embedded to have
@@ -270,6 +487,21 @@ func (UnimplementedLBCoordinatorServer) GetCollection(context.Context, *Collecti
 func (UnimplementedLBCoordinatorServer) ListCollection(context.Context, *emptypb.Empty) (*CollectionList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListCollection not implemented")
 }
+func (UnimplementedLBCoordinatorServer) CreateAlias(context.Context, *Alias) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAlias not implemented")
+}
+func (UnimplementedLBCoordinatorServer) PointAlias(context.Context, *Alias) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PointAlias not implemented")
+}
+func (UnimplementedLBCoordinatorServer) SwapAliases(context.Context, *SwapAliasesReq) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SwapAliases not implemented")
+}
+func (UnimplementedLBCoordinatorServer) DeleteAlias(context.Context, *AliasName) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAlias not implemented")
+}
+func (UnimplementedLBCoordinatorServer) ListAliases(context.Context, *emptypb.Empty) (*AliasList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAliases not implemented")
+}
 func (UnimplementedLBCoordinatorServer) Insert(context.Context, *ModifyDataset) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Insert not implemented")
 }
@@ -279,6 +511,9 @@ func (UnimplementedLBCoordinatorServer) Update(context.Context, *ModifyDataset)
 func (UnimplementedLBCoordinatorServer) Delete(context.Context, *DeleteDataset) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedLBCoordinatorServer) PatchMetadata(context.Context, *PatchMetadataReq) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PatchMetadata not implemented")
+}
 func (UnimplementedLBCoordinatorServer) BatchInsert(grpc.BidiStreamingServer[ModifyDataset, Response]) error {
 	return status.Errorf(codes.Unimplemented, "method BatchInsert not implemented")
 }
@@ -291,9 +526,33 @@ func (UnimplementedLBCoordinatorServer) BatchDelete(grpc.BidiStreamingServer[Del
 func (UnimplementedLBCoordinatorServer) Search(context.Context, *SearchReq) (*SearchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
 }
+func (UnimplementedLBCoordinatorServer) BatchSearch(context.Context, *BatchSearchReq) (*BatchSearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchSearch not implemented")
+}
+func (UnimplementedLBCoordinatorServer) GetPoints(context.Context, *PointIds) (*PointsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPoints not implemented")
+}
+func (UnimplementedLBCoordinatorServer) ListPointIds(*CollectionName, grpc.ServerStreamingServer[PointIds]) error {
+	return status.Errorf(codes.Unimplemented, "method ListPointIds not implemented")
+}
+func (UnimplementedLBCoordinatorServer) Scroll(context.Context, *ScrollReq) (*ScrollResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scroll not implemented")
+}
+func (UnimplementedLBCoordinatorServer) Count(context.Context, *CountReq) (*CountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Count not implemented")
+}
 func (UnimplementedLBCoordinatorServer) DataLoader(grpc.BidiStreamingServer[ModifyDataset, Response]) error {
 	return status.Errorf(codes.Unimplemented, "method DataLoader not implemented")
 }
+func (UnimplementedLBCoordinatorServer) Reshard(context.Context, *ReshardReq) (*ReshardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reshard not implemented")
+}
+func (UnimplementedLBCoordinatorServer) GetMerkleTree(context.Context, *MerkleReq) (*MerkleTree, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMerkleTree not implemented")
+}
+func (UnimplementedLBCoordinatorServer) CheckDivergence(context.Context, *DivergenceReq) (*DivergenceReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckDivergence not implemented")
+}
 func (UnimplementedLBCoordinatorServer) testEmbeddedByValue() {}
 
 // UnsafeLBCoordinatorServer may be embedded to opt out of forward compatibility for this service.
@@ -404,6 +663,96 @@ func _LBCoordinator_ListCollection_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LBCoordinator_CreateAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Alias)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LBCoordinatorServer).CreateAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LBCoordinator_CreateAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LBCoordinatorServer).CreateAlias(ctx, req.(*Alias))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LBCoordinator_PointAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Alias)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LBCoordinatorServer).PointAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LBCoordinator_PointAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LBCoordinatorServer).PointAlias(ctx, req.(*Alias))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LBCoordinator_SwapAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SwapAliasesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LBCoordinatorServer).SwapAliases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LBCoordinator_SwapAliases_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LBCoordinatorServer).SwapAliases(ctx, req.(*SwapAliasesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LBCoordinator_DeleteAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AliasName)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LBCoordinatorServer).DeleteAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LBCoordinator_DeleteAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LBCoordinatorServer).DeleteAlias(ctx, req.(*AliasName))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LBCoordinator_ListAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LBCoordinatorServer).ListAliases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LBCoordinator_ListAliases_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LBCoordinatorServer).ListAliases(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LBCoordinator_Insert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ModifyDataset)
 	if err := dec(in); err != nil {
@@ -458,6 +807,24 @@ func _LBCoordinator_Delete_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LBCoordinator_PatchMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatchMetadataReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LBCoordinatorServer).PatchMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LBCoordinator_PatchMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LBCoordinatorServer).PatchMetadata(ctx, req.(*PatchMetadataReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LBCoordinator_BatchInsert_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(LBCoordinatorServer).BatchInsert(&grpc.GenericServerStream[ModifyDataset, Response]{ServerStream: stream})
 }
@@ -497,6 +864,89 @@ func _LBCoordinator_Search_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LBCoordinator_BatchSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchSearchReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LBCoordinatorServer).BatchSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LBCoordinator_BatchSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LBCoordinatorServer).BatchSearch(ctx, req.(*BatchSearchReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LBCoordinator_GetPoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PointIds)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LBCoordinatorServer).GetPoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LBCoordinator_GetPoints_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LBCoordinatorServer).GetPoints(ctx, req.(*PointIds))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LBCoordinator_ListPointIds_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CollectionName)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LBCoordinatorServer).ListPointIds(m, &grpc.GenericServerStream[CollectionName, PointIds]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LBCoordinator_ListPointIdsServer = grpc.ServerStreamingServer[PointIds]
+
+func _LBCoordinator_Scroll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScrollReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LBCoordinatorServer).Scroll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LBCoordinator_Scroll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LBCoordinatorServer).Scroll(ctx, req.(*ScrollReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LBCoordinator_Count_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LBCoordinatorServer).Count(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LBCoordinator_Count_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LBCoordinatorServer).Count(ctx, req.(*CountReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LBCoordinator_DataLoader_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(LBCoordinatorServer).DataLoader(&grpc.GenericServerStream[ModifyDataset, Response]{ServerStream: stream})
 }
@@ -504,6 +954,60 @@ func _LBCoordinator_DataLoader_Handler(srv interface{}, stream grpc.ServerStream
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type LBCoordinator_DataLoaderServer = grpc.BidiStreamingServer[ModifyDataset, Response]
 
+func _LBCoordinator_Reshard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReshardReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LBCoordinatorServer).Reshard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LBCoordinator_Reshard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LBCoordinatorServer).Reshard(ctx, req.(*ReshardReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LBCoordinator_GetMerkleTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MerkleReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LBCoordinatorServer).GetMerkleTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LBCoordinator_GetMerkleTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LBCoordinatorServer).GetMerkleTree(ctx, req.(*MerkleReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LBCoordinator_CheckDivergence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DivergenceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LBCoordinatorServer).CheckDivergence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LBCoordinator_CheckDivergence_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LBCoordinatorServer).CheckDivergence(ctx, req.(*DivergenceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LBCoordinator_ServiceDesc is the grpc.ServiceDesc for LBCoordinator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -531,6 +1035,26 @@ var LBCoordinator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListCollection",
 			Handler:    _LBCoordinator_ListCollection_Handler,
 		},
+		{
+			MethodName: "CreateAlias",
+			Handler:    _LBCoordinator_CreateAlias_Handler,
+		},
+		{
+			MethodName: "PointAlias",
+			Handler:    _LBCoordinator_PointAlias_Handler,
+		},
+		{
+			MethodName: "SwapAliases",
+			Handler:    _LBCoordinator_SwapAliases_Handler,
+		},
+		{
+			MethodName: "DeleteAlias",
+			Handler:    _LBCoordinator_DeleteAlias_Handler,
+		},
+		{
+			MethodName: "ListAliases",
+			Handler:    _LBCoordinator_ListAliases_Handler,
+		},
 		{
 			MethodName: "Insert",
 			Handler:    _LBCoordinator_Insert_Handler,
@@ -543,10 +1067,42 @@ var LBCoordinator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _LBCoordinator_Delete_Handler,
 		},
+		{
+			MethodName: "PatchMetadata",
+			Handler:    _LBCoordinator_PatchMetadata_Handler,
+		},
 		{
 			MethodName: "Search",
 			Handler:    _LBCoordinator_Search_Handler,
 		},
+		{
+			MethodName: "BatchSearch",
+			Handler:    _LBCoordinator_BatchSearch_Handler,
+		},
+		{
+			MethodName: "GetPoints",
+			Handler:    _LBCoordinator_GetPoints_Handler,
+		},
+		{
+			MethodName: "Scroll",
+			Handler:    _LBCoordinator_Scroll_Handler,
+		},
+		{
+			MethodName: "Count",
+			Handler:    _LBCoordinator_Count_Handler,
+		},
+		{
+			MethodName: "Reshard",
+			Handler:    _LBCoordinator_Reshard_Handler,
+		},
+		{
+			MethodName: "GetMerkleTree",
+			Handler:    _LBCoordinator_GetMerkleTree_Handler,
+		},
+		{
+			MethodName: "CheckDivergence",
+			Handler:    _LBCoordinator_CheckDivergence_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -567,6 +1123,11 @@ var LBCoordinator_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ListPointIds",
+			Handler:       _LBCoordinator_ListPointIds_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "DataLoader",
 			Handler:       _LBCoordinator_DataLoader_Handler,
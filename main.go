@@ -18,19 +18,190 @@
 package main
 
 import (
-	"encoding/binary"
-	"fmt"
+	"flag"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
-	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/sjy-dv/nnv/gateway"
+	"github.com/sjy-dv/nnv/pkg/auth"
+	"github.com/sjy-dv/nnv/pkg/sharding"
+	"github.com/sjy-dv/nnv/pkg/tenant"
+	"github.com/sjy-dv/nnv/replication"
+	"github.com/sjy-dv/nnv/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
-	id := uuid.New()
-	fmt.Println(id)
-	fmt.Println(UuidMod(id, 3))
-}
+	config := server.Config{}
+	var mode, servers, balancer, service, placement string
+	var natsURL, natsStream string
+	var natsEmbed int
+	var peers string
+	var healthInterval time.Duration
+	var failureThreshold int
+	var authFile, tlsCert, tlsKey, tlsClientCA, peerCA, apiKey string
+	var quota server.Quota
+	flag.StringVar(&mode, "mode", "node", "node or gateway")
+	flag.StringVar(&config.Host, "host", "0.0.0.0", "listen host")
+	flag.StringVar(&config.Port, "port", "50051", "listen port")
+	flag.StringVar(&config.HTTPPort, "http-port", "", "node: port of the HTTP/JSON API, off when empty")
+	flag.StringVar(&config.DataDir, "data", "./data", "directory holding the collection files")
+	flag.BoolVar(&config.Stable, "stable", true, "bbolt backed storage, compressed in-memory storage when false")
+	flag.StringVar(&servers, "servers", "", "gateway: comma separated node addresses")
+	flag.StringVar(&balancer, "balancer", "lb", "gateway: lb replicates to every node, slb shards across nodes")
+	flag.StringVar(&placement, "placement", "rendezvous", "gateway: slb point placement, rendezvous, ring or modulo")
+	flag.StringVar(&service, "service", "", "gateway: k8s headless service host:port, replaces -servers")
+	flag.DurationVar(&healthInterval, "health-interval", gateway.DefaultHealthCheckInterval, "gateway: pause between backend health checks")
+	flag.IntVar(&failureThreshold, "failure-threshold", gateway.DefaultFailureThreshold, "gateway: consecutive backend failures that open its circuit")
+	flag.StringVar(&config.NodeId, "node-id", "", "node: replication identity, generated once when empty")
+	flag.StringVar(&natsURL, "nats", "", "node: NATS url of the replication stream, replication is off when empty")
+	flag.StringVar(&natsStream, "nats-stream", replication.DefaultStream, "node: JetStream stream name")
+	flag.IntVar(&natsEmbed, "nats-embed", 0, "node: run a NATS server on this port inside the node and replicate through it")
+	flag.StringVar(&peers, "peers", "", "node: comma separated addresses of the replicas repaired by anti-entropy")
+	flag.DurationVar(&config.AntiEntropyInterval, "anti-entropy", time.Minute, "node: pause between anti-entropy rounds, 0 disables them")
+	flag.DurationVar(&config.TombstoneHorizon, "tombstone-horizon", 7*24*time.Hour, "node: age after which tombstones are purged, 0 keeps them")
+	flag.StringVar(&authFile, "auth", "", "JSON file of api keys, grants and forwarders, authentication is off when empty")
+	flag.StringVar(&tlsCert, "tls-cert", "", "certificate served to callers and presented to peers and backends")
+	flag.StringVar(&tlsKey, "tls-key", "", "key of -tls-cert")
+	flag.StringVar(&tlsClientCA, "tls-client-ca", "", "ca verifying client certificates for mutual tls")
+	flag.StringVar(&peerCA, "peer-ca", "", "ca verifying peers and backends, their connections are plaintext when empty")
+	flag.StringVar(&apiKey, "api-key", "", "api key presented to peers and backends")
+	flag.IntVar(&quota.Collections, "quota-collections", 0, "node: collections per tenant, 0 is unlimited")
+	flag.Uint64Var(&quota.Points, "quota-points", 0, "node: points per tenant, 0 is unlimited")
+	flag.Uint64Var(&quota.PointBytes, "quota-point-bytes", 0, "node: point bytes per tenant, 4 per vector dimension plus the metadata of every point, not the disk size, 0 is unlimited")
+	flag.Parse()
+
+	if authFile != "" {
+		authConfig, err := auth.Load(authFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load auth config")
+		}
+		config.Auth = authConfig
+	}
+	if tlsCert != "" {
+		tlsConfig, err := auth.ServerTLS(tlsCert, tlsKey, tlsClientCA)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load tls config")
+		}
+		config.TLS = tlsConfig
+	}
+	var dialOpts []grpc.DialOption
+	if peerCA != "" {
+		tlsConfig, err := auth.ClientTLS(peerCA, tlsCert, tlsKey)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load peer tls config")
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
+	if apiKey != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(auth.APIKey(apiKey)))
+	}
+
+	var closeFn func()
+	switch mode {
+	case "node":
+		var stopNats func()
+		if natsEmbed != 0 {
+			ns, err := replication.StartEmbedded(config.Host, natsEmbed, filepath.Join(config.DataDir, "nats"))
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to start embedded nats")
+			}
+			stopNats = ns.Shutdown
+			if natsURL == "" {
+				natsURL = ns.ClientURL()
+			}
+		}
+		if peers != "" {
+			config.Peers = strings.Split(peers, ",")
+		}
+		if natsURL != "" {
+			config.Replication = &replication.Config{URL: natsURL, Stream: natsStream}
+		}
+		if quota != (server.Quota{}) {
+			config.Quotas = map[string]server.Quota{tenant.All: quota}
+		}
+		node, err := server.New(config, dialOpts...)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to start nnv node")
+		}
+		go func() {
+			if err := node.ListenAndServe(); err != nil {
+				log.Fatal().Err(err).Msg("nnv node stopped")
+			}
+		}()
+		if config.HTTPPort != "" {
+			go func() {
+				if err := node.ListenAndServeJSON(); err != nil {
+					log.Fatal().Err(err).Msg("nnv node http api stopped")
+				}
+			}()
+		}
+		closeFn = func() {
+			if err := node.Close(); err != nil {
+				log.Error().Err(err).Msg("failed to close nnv node")
+			}
+			if stopNats != nil {
+				stopNats()
+			}
+		}
+	case "gateway":
+		gwConfig := gateway.GateWay{
+			Host:        config.Host,
+			Port:        config.Port,
+			ServiceName: service,
+
+			HealthCheckInterval: healthInterval,
+			FailureThreshold:    failureThreshold,
+
+			Auth: config.Auth,
+			TLS:  config.TLS,
+		}
+		if servers != "" {
+			gwConfig.ServerAddrs = strings.Split(servers, ",")
+		}
+		if service != "" {
+			gwConfig.Infra = gateway.K8S
+		}
+		switch balancer {
+		case "lb":
+			gwConfig.Balancer = gateway.LB
+		case "slb":
+			gwConfig.Balancer = gateway.SLB
+		default:
+			log.Fatal().Str("balancer", balancer).Msg("unknown balancer")
+		}
+		switch placement {
+		case "rendezvous":
+			gwConfig.Placement = sharding.Rendezvous
+		case "ring":
+			gwConfig.Placement = sharding.Ring
+		case "modulo":
+			gwConfig.Placement = sharding.Modulo
+		default:
+			log.Fatal().Str("placement", placement).Msg("unknown placement")
+		}
+		gw, err := gateway.New(gwConfig, dialOpts...)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to start nnv gateway")
+		}
+		go func() {
+			if err := gw.ListenAndServe(); err != nil {
+				log.Fatal().Err(err).Msg("nnv gateway stopped")
+			}
+		}()
+		closeFn = gw.Close
+	default:
+		log.Fatal().Str("mode", mode).Msg("unknown mode")
+	}
 
-func UuidMod(x uuid.UUID, mod uint64) uint64 {
-	res := ((binary.LittleEndian.Uint64(x[:8]) % mod) + (binary.LittleEndian.Uint64(x[8:]) % mod))
-	return res % mod
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	<-sig
+	closeFn()
 }
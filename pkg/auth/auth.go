@@ -0,0 +1,332 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package auth authenticates the callers of the nodes and the gateway and
+// authorizes them per collection. Callers are principals, named by the
+// authenticator that recognized their credentials, and a policy grants every
+// principal read, write or admin rights on collection names.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrUnauthenticated  = errors.New("unauthenticated")
+	ErrPermissionDenied = errors.New("permission denied")
+	// credentials hold nothing an authenticator checks
+	ErrNoCredentials = fmt.Errorf("%w: no credentials", ErrUnauthenticated)
+)
+
+// Any grants on every collection, or to every principal.
+const Any = "*"
+
+type Permission int
+
+const (
+	// authenticated callers only, no right on a collection
+	None Permission = iota
+	Read
+	Write
+	// creates and drops collections, every lower permission included
+	Admin
+)
+
+var permissionNames = []string{"none", "read", "write", "admin"}
+
+func (p Permission) String() string {
+	if p < None || p > Admin {
+		return fmt.Sprintf("permission(%d)", int(p))
+	}
+	return permissionNames[p]
+}
+
+func ParsePermission(s string) (Permission, error) {
+	i := slices.Index(permissionNames, strings.ToLower(s))
+	if i < 0 {
+		return None, fmt.Errorf("unknown permission %q, expected read, write or admin", s)
+	}
+	return Permission(i), nil
+}
+
+// Credentials are what a caller presented.
+type Credentials struct {
+	APIKey string
+	// verified client certificate chain, leaf first
+	Certificates []*x509.Certificate
+	// principal a forwarder calls on behalf of
+	Forwarded string
+}
+
+// Authenticator recognizes one kind of credentials.
+type Authenticator interface {
+	// Authenticate returns the principal of the credentials, ErrNoCredentials
+	// when they hold nothing the authenticator checks
+	Authenticate(c Credentials) (string, error)
+}
+
+// APIKeys maps static API keys to their principal.
+type APIKeys map[string]string
+
+func (k APIKeys) Authenticate(c Credentials) (string, error) {
+	if c.APIKey == "" {
+		return "", ErrNoCredentials
+	}
+	// every key is compared so the time taken tells nothing about them
+	var principal string
+	for key, p := range k {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(c.APIKey)) == 1 {
+			principal = p
+		}
+	}
+	if principal == "" {
+		return "", fmt.Errorf("%w: unknown api key", ErrUnauthenticated)
+	}
+	return principal, nil
+}
+
+// MutualTLS accepts client certificates the TLS handshake verified, the common
+// name of the leaf is the principal.
+type MutualTLS struct{}
+
+func (MutualTLS) Authenticate(c Credentials) (string, error) {
+	if len(c.Certificates) == 0 || c.Certificates[0].Subject.CommonName == "" {
+		return "", ErrNoCredentials
+	}
+	return c.Certificates[0].Subject.CommonName, nil
+}
+
+// Policy grants the permissions of every principal per collection. A grant
+// names a collection of the home tenant of the principal, the one it is bound
+// to or the default tenant, or tenant/collection for a collection of another
+// tenant. Any as the collection grants on every collection of the tenant, Any
+// as the tenant on the collections of every tenant, and Any as the principal
+// grants to every authenticated caller.
+type Policy map[string]map[string]Permission
+
+// Allows tells whether principal, at home in the tenant home, holds need on
+// collection of tenant. Requests that do not name a collection need the
+// permission on Any.
+func (p Policy) Allows(principal, home, tenant, collection string, need Permission) bool {
+	if need == None {
+		return true
+	}
+	names := []string{tenant + "/" + Any, Any + "/" + Any}
+	if tenant == home {
+		names = append(names, Any)
+	}
+	if collection != "" {
+		names = append(names, tenant+"/"+collection, Any+"/"+collection)
+		if tenant == home {
+			names = append(names, collection)
+		}
+	}
+	for _, grants := range []map[string]Permission{p[principal], p[Any]} {
+		for _, name := range names {
+			if grants[name] >= need {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type Config struct {
+	// tried in order, the first one that recognizes the credentials names
+	// the principal
+	Authenticators []Authenticator
+	Policy         Policy
+	// principals trusted to call on behalf of the principal they forward and
+	// to pick the versions of the writes they pass on, the gateways and the
+	// nodes that repair their peers
+	Forwarders []string
+	// tenant every principal is bound to, principals missing here act in
+	// the default tenant
+	Tenants map[string]string
+	// principals that act in any tenant and in all of them at once, the
+	// operators, the gateways resharding and the nodes repairing their peers
+	Operators []string
+}
+
+// Authenticate returns the identity of the caller.
+func (c *Config) Authenticate(creds Credentials) (*Identity, error) {
+	var principal string
+	for _, a := range c.Authenticators {
+		p, err := a.Authenticate(creds)
+		if err == nil {
+			principal = p
+			break
+		}
+		if !errors.Is(err, ErrNoCredentials) {
+			return nil, err
+		}
+	}
+	if principal == "" {
+		return nil, ErrNoCredentials
+	}
+	if creds.Forwarded == "" {
+		return c.identity(principal, "", slices.Contains(c.Forwarders, principal)), nil
+	}
+	if !slices.Contains(c.Forwarders, principal) {
+		return nil, fmt.Errorf("%w: %s may not call on behalf of others", ErrPermissionDenied, principal)
+	}
+	return c.identity(creds.Forwarded, principal, true), nil
+}
+
+func (c *Config) identity(principal, via string, forwarder bool) *Identity {
+	return &Identity{
+		Principal: principal,
+		Via:       via,
+		Tenant:    c.Tenants[principal],
+		operator:  slices.Contains(c.Operators, principal),
+		forwarder: forwarder,
+		policy:    c.Policy,
+	}
+}
+
+// Identity is an authenticated caller.
+type Identity struct {
+	Principal string
+	// forwarder that called on behalf of the principal, empty for direct
+	// calls
+	Via string
+	// the tenant the principal is bound to, empty for the default tenant
+	Tenant string
+	// the principal acts in any tenant
+	operator bool
+	// the call came from a forwarder, on its own behalf or on the behalf of
+	// Principal
+	forwarder bool
+	policy    Policy
+}
+
+// ResolveTenant returns the tenant a call that requested one acts in, an
+// empty request is for the home tenant. Operators act in any tenant, every
+// other principal only in its home tenant.
+func (id *Identity) ResolveTenant(requested string) (string, error) {
+	switch {
+	case requested == "" || requested == id.Tenant:
+		return id.Tenant, nil
+	case id.operator:
+		return requested, nil
+	case id.Tenant == "":
+		return "", fmt.Errorf("%w: %s may only use the default tenant", ErrPermissionDenied, id.Principal)
+	}
+	return "", fmt.Errorf("%w: %s is bound to tenant %s", ErrPermissionDenied, id.Principal, id.Tenant)
+}
+
+func (id *Identity) Can(tenant, collection string, need Permission) bool {
+	return id.policy.Allows(id.Principal, id.Tenant, tenant, collection, need)
+}
+
+// Authorize is Can as an error.
+func (id *Identity) Authorize(tenant, collection string, need Permission) error {
+	if id.Can(tenant, collection, need) {
+		return nil
+	}
+	where := "every collection"
+	if collection != "" {
+		where = "collection " + collection
+	}
+	if tenant != id.Tenant {
+		where += " of tenant " + tenant
+	}
+	return fmt.Errorf("%w: %s needs %s on %s", ErrPermissionDenied, id.Principal, need, where)
+}
+
+// Forwarder tells whether the call came from a forwarder.
+func (id *Identity) Forwarder() bool {
+	return id.forwarder
+}
+
+type identityKey struct{}
+
+func NewContext(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+func FromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(*Identity)
+	return id, ok
+}
+
+// Allowed tells whether the caller of ctx holds need on collection of tenant,
+// calls without an identity are allowed as authentication is off for them.
+func Allowed(ctx context.Context, tenant, collection string, need Permission) bool {
+	id, ok := FromContext(ctx)
+	return !ok || id.Can(tenant, collection, need)
+}
+
+// fileConfig is the JSON form of a Config:
+//
+//	{
+//	  "apiKeys":    {"<key>": "alice", "<key>": "gateway"},
+//	  "mutualTLS":  true,
+//	  "grants":     {"alice": {"docs": "write"}, "gateway": {"*/*": "admin"}},
+//	  "forwarders": ["gateway"],
+//	  "tenants":    {"alice": "search-team"},
+//	  "operators":  ["gateway"]
+//	}
+type fileConfig struct {
+	APIKeys    map[string]string            `json:"apiKeys"`
+	MutualTLS  bool                         `json:"mutualTLS"`
+	Grants     map[string]map[string]string `json:"grants"`
+	Forwarders []string                     `json:"forwarders"`
+	Tenants    map[string]string            `json:"tenants"`
+	Operators  []string                     `json:"operators"`
+}
+
+// Load reads a Config from a JSON file.
+func Load(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth config: %w", err)
+	}
+	var f fileConfig
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("invalid auth config %s: %w", path, err)
+	}
+	c := &Config{Policy: make(Policy, len(f.Grants)), Forwarders: f.Forwarders, Tenants: f.Tenants, Operators: f.Operators}
+	if len(f.APIKeys) > 0 {
+		c.Authenticators = append(c.Authenticators, APIKeys(f.APIKeys))
+	}
+	if f.MutualTLS {
+		c.Authenticators = append(c.Authenticators, MutualTLS{})
+	}
+	if len(c.Authenticators) == 0 {
+		return nil, fmt.Errorf("invalid auth config %s: neither api keys nor mutual tls", path)
+	}
+	for principal, grants := range f.Grants {
+		c.Policy[principal] = make(map[string]Permission, len(grants))
+		for collection, name := range grants {
+			perm, err := ParsePermission(name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth config %s: %s on %s: %w", path, principal, collection, err)
+			}
+			c.Policy[principal][collection] = perm
+		}
+	}
+	return c, nil
+}
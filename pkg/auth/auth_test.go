@@ -0,0 +1,208 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy(t *testing.T) {
+	p := Policy{
+		"alice":  {"docs": Write, "team-b/shared": Read},
+		"ops":    {"*/*": Admin},
+		"team":   {"*": Write},
+		Any:      {"public": Read},
+		"nobody": {},
+	}
+	require.True(t, p.Allows("alice", "", "", "docs", Read))
+	require.True(t, p.Allows("alice", "", "", "docs", Write))
+	require.False(t, p.Allows("alice", "", "", "docs", Admin))
+	require.False(t, p.Allows("alice", "", "", "other", Read))
+	require.True(t, p.Allows("alice", "", "", "public", Read))
+	require.False(t, p.Allows("alice", "", "", "public", Write))
+	require.True(t, p.Allows("ops", "", "", "anything", Admin))
+	// requests without a collection need the grant on every collection
+	require.False(t, p.Allows("alice", "", "", "", Read))
+	require.True(t, p.Allows("ops", "", "", "", Admin))
+	require.True(t, p.Allows("nobody", "", "", "", None))
+	require.False(t, p.Allows("nobody", "", "", "docs", Read))
+
+	// plain names are in the home tenant, others are named with theirs
+	require.False(t, p.Allows("alice", "", "team-a", "docs", Read))
+	require.True(t, p.Allows("alice", "team-a", "team-a", "docs", Write))
+	require.True(t, p.Allows("alice", "", "team-b", "shared", Read))
+	require.False(t, p.Allows("alice", "", "team-c", "shared", Read))
+	require.True(t, p.Allows("team", "team-a", "team-a", "", Write))
+	require.False(t, p.Allows("team", "team-a", "team-b", "docs", Read))
+	require.True(t, p.Allows("ops", "", "team-a", "docs", Admin))
+	require.True(t, p.Allows("ops", "", Any, "", Admin))
+	require.False(t, p.Allows("team", "", Any, "", Read))
+}
+
+func TestAuthenticate(t *testing.T) {
+	c := &Config{
+		Authenticators: []Authenticator{APIKeys{"k1": "alice", "k2": "gateway"}, MutualTLS{}},
+		Policy:         Policy{"alice": {"docs": Read}},
+		Forwarders:     []string{"gateway"},
+	}
+	id, err := c.Authenticate(Credentials{APIKey: "k1"})
+	require.NoError(t, err)
+	require.Equal(t, "alice", id.Principal)
+	require.False(t, id.Forwarder())
+	require.True(t, id.Can("", "docs", Read))
+	require.ErrorIs(t, id.Authorize("", "docs", Write), ErrPermissionDenied)
+
+	_, err = c.Authenticate(Credentials{APIKey: "wrong"})
+	require.ErrorIs(t, err, ErrUnauthenticated)
+	_, err = c.Authenticate(Credentials{})
+	require.ErrorIs(t, err, ErrNoCredentials)
+
+	cert := selfSigned(t, "bob")
+	id, err = c.Authenticate(Credentials{Certificates: []*x509.Certificate{cert}})
+	require.NoError(t, err)
+	require.Equal(t, "bob", id.Principal)
+
+	// only forwarders act on behalf of others
+	id, err = c.Authenticate(Credentials{APIKey: "k2", Forwarded: "alice"})
+	require.NoError(t, err)
+	require.Equal(t, "alice", id.Principal)
+	require.Equal(t, "gateway", id.Via)
+	require.True(t, id.Forwarder())
+	id, err = c.Authenticate(Credentials{APIKey: "k2"})
+	require.NoError(t, err)
+	require.True(t, id.Forwarder())
+	_, err = c.Authenticate(Credentials{APIKey: "k1", Forwarded: "gateway"})
+	require.ErrorIs(t, err, ErrPermissionDenied)
+}
+
+func TestResolveTenant(t *testing.T) {
+	c := &Config{
+		Authenticators: []Authenticator{APIKeys{"k1": "alice", "k2": "bob", "k3": "ops"}},
+		Tenants:        map[string]string{"alice": "team-a"},
+		Operators:      []string{"ops"},
+	}
+	resolve := func(key, requested string) (string, error) {
+		id, err := c.Authenticate(Credentials{APIKey: key})
+		require.NoError(t, err)
+		return id.ResolveTenant(requested)
+	}
+
+	// bound principals act in their tenant
+	for _, requested := range []string{"", "team-a"} {
+		tenant, err := resolve("k1", requested)
+		require.NoError(t, err)
+		require.Equal(t, "team-a", tenant)
+	}
+	_, err := resolve("k1", "team-b")
+	require.ErrorIs(t, err, ErrPermissionDenied)
+
+	// unbound ones in the default tenant
+	tenant, err := resolve("k2", "")
+	require.NoError(t, err)
+	require.Empty(t, tenant)
+	for _, requested := range []string{"team-a", Any} {
+		_, err := resolve("k2", requested)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+	}
+
+	// operators in any and all of them
+	for _, requested := range []string{"", "team-a", Any} {
+		tenant, err := resolve("k3", requested)
+		require.NoError(t, err)
+		require.Equal(t, requested, tenant)
+	}
+}
+
+func TestAuthorizeAliases(t *testing.T) {
+	id := &Identity{Principal: "alice", policy: Policy{"alice": {"docs": Admin, "live": Admin, "staging": Admin, "secret": Read}}}
+	require.NoError(t, authorize(id, "", Admin, &pb.Alias{Alias: "live", CollectionName: "docs"}))
+	require.Error(t, authorize(id, "", Admin, &pb.Alias{Alias: "live", CollectionName: "secret"}))
+	require.Error(t, authorize(id, "", Admin, &pb.Alias{Alias: "secret", CollectionName: "docs"}))
+	require.NoError(t, authorize(id, "", Admin, &pb.SwapAliasesReq{Alias: "live", OtherAlias: "staging"}))
+	require.Error(t, authorize(id, "", Admin, &pb.SwapAliasesReq{Alias: "live", OtherAlias: "secret"}))
+	require.NoError(t, authorize(id, "", Read, &pb.CollectionName{CollectionName: "secret"}))
+	require.Error(t, authorize(id, "", Read, &pb.ReshardReq{}))
+	// versions, handoffs and internal writes are sent by forwarders only
+	require.Error(t, authorize(id, "", Admin, &pb.DeleteDataset{CollectionName: "docs", Version: 1}))
+	require.Error(t, authorize(id, "", Admin, &pb.DeleteDataset{CollectionName: "docs", Handoff: true}))
+	require.Error(t, authorize(id, "", Admin, &pb.ModifyDataset{CollectionName: "docs", Internal: true}))
+	require.Error(t, authorize(id, "", Admin, &pb.DeleteDataset{CollectionName: "docs", Internal: true}))
+	id.forwarder = true
+	require.NoError(t, authorize(id, "", Admin, &pb.DeleteDataset{CollectionName: "docs", Version: 1}))
+	require.NoError(t, authorize(id, "", Admin, &pb.DeleteDataset{CollectionName: "docs", Handoff: true}))
+	require.NoError(t, authorize(id, "", Admin, &pb.ModifyDataset{CollectionName: "docs", Internal: true}))
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"apiKeys": {"k1": "alice"},
+		"grants": {"alice": {"docs": "write", "*": "read"}},
+		"forwarders": ["gateway"],
+		"operators": ["gateway"]
+	}`), 0600))
+	c, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, Policy{"alice": {"docs": Write, Any: Read}}, c.Policy)
+	require.Equal(t, []string{"gateway"}, c.Forwarders)
+	require.Equal(t, []string{"gateway"}, c.Operators)
+	require.Len(t, c.Authenticators, 1)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"apiKeys": {"k1": "alice"}, "grants": {"alice": {"docs": "owner"}}}`), 0600))
+	_, err = Load(path)
+	require.Error(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(`{"grants": {}}`), 0600))
+	_, err = Load(path)
+	require.Error(t, err)
+}
+
+func TestBearerToken(t *testing.T) {
+	require.Equal(t, "abc", BearerToken("Bearer abc"))
+	require.Equal(t, "abc", BearerToken("bearer abc"))
+	require.Empty(t, BearerToken("Basic abc"))
+	require.Empty(t, BearerToken("abc"))
+}
+
+func selfSigned(t *testing.T, commonName string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
@@ -0,0 +1,279 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/errcode"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	// metadata carrying the API key as "Bearer <key>", the HTTP API reads
+	// the header of the same name
+	AuthorizationKey = "authorization"
+	// metadata carrying the principal a forwarder calls on behalf of
+	ForwardedKey = "x-nnv-principal"
+	// metadata carrying the tenant a call requests, see ResolveTenant
+	TenantKey = "x-nnv-tenant"
+
+	bearerPrefix = "Bearer "
+)
+
+// publicMethods are served without credentials, the gateway probes backends
+// with Ping.
+var publicMethods = map[string]bool{
+	pb.LBCoordinator_Ping_FullMethodName: true,
+}
+
+// methodPermissions is what every method needs on the collection its request
+// names, methods missing here are denied.
+var methodPermissions = map[string]Permission{
+	pb.LBCoordinator_CreateCollection_FullMethodName: Admin,
+	pb.LBCoordinator_DropCollection_FullMethodName:   Admin,
+	pb.LBCoordinator_GetCollection_FullMethodName:    Read,
+	// answers with the collections the caller may read
+	pb.LBCoordinator_ListCollection_FullMethodName: None,
+	pb.LBCoordinator_Insert_FullMethodName:         Write,
+	pb.LBCoordinator_Update_FullMethodName:         Write,
+	pb.LBCoordinator_Delete_FullMethodName:         Write,
+	pb.LBCoordinator_PatchMetadata_FullMethodName:  Write,
+	pb.LBCoordinator_BatchInsert_FullMethodName:    Write,
+	pb.LBCoordinator_BatchUpdate_FullMethodName:    Write,
+	pb.LBCoordinator_BatchDelete_FullMethodName:    Write,
+	pb.LBCoordinator_Search_FullMethodName:         Read,
+	pb.LBCoordinator_BatchSearch_FullMethodName:    Read,
+	pb.LBCoordinator_GetPoints_FullMethodName:      Read,
+	pb.LBCoordinator_ListPointIds_FullMethodName:   Read,
+	pb.LBCoordinator_Scroll_FullMethodName:         Read,
+	pb.LBCoordinator_Count_FullMethodName:          Read,
+	pb.LBCoordinator_DataLoader_FullMethodName:     Write,
+	pb.LBCoordinator_Reshard_FullMethodName:        Admin,
+	pb.LBCoordinator_GetMerkleTree_FullMethodName:  Read,
+	// without a collection name it checks every collection
+	pb.LBCoordinator_CheckDivergence_FullMethodName: Admin,
+
+	// needed on the aliases as well as the collection, whoever points an
+	// alias decides what its users reach
+	pb.LBCoordinator_CreateAlias_FullMethodName: Admin,
+	pb.LBCoordinator_PointAlias_FullMethodName:  Admin,
+	pb.LBCoordinator_SwapAliases_FullMethodName: Admin,
+	pb.LBCoordinator_DeleteAlias_FullMethodName: Admin,
+	// answers with the aliases the caller may read
+	pb.LBCoordinator_ListAliases_FullMethodName: None,
+}
+
+// ServerOptions authenticate every call and authorize every request message
+// against the policy.
+func (c *Config) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(c.unaryInterceptor),
+		grpc.ChainStreamInterceptor(c.streamInterceptor),
+	}
+}
+
+func (c *Config) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if publicMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+	need, id, tenant, err := c.identify(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(id, tenant, need, req); err != nil {
+		return nil, err
+	}
+	return handler(NewContext(ctx, id), req)
+}
+
+func (c *Config) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if publicMethods[info.FullMethod] {
+		return handler(srv, ss)
+	}
+	need, id, tenant, err := c.identify(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizedStream{ServerStream: ss, ctx: NewContext(ss.Context(), id), id: id, tenant: tenant, need: need})
+}
+
+// authorizedStream authorizes every message the handler receives, the
+// messages of a stream may name different collections.
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	id     *Identity
+	tenant string
+	need   Permission
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authorizedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return authorize(s.id, s.tenant, s.need, m)
+}
+
+// identify authenticates the caller and resolves the tenant it acts in.
+func (c *Config) identify(ctx context.Context, method string) (Permission, *Identity, string, error) {
+	need, ok := methodPermissions[method]
+	if !ok {
+		return None, nil, "", statusError(fmt.Errorf("%w: unknown method %s", ErrPermissionDenied, method))
+	}
+	id, err := c.Authenticate(credentialsFromContext(ctx))
+	if err != nil {
+		return None, nil, "", statusError(err)
+	}
+	var requested string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(TenantKey); len(v) > 0 {
+		requested = v[0]
+	}
+	tenant, err := id.ResolveTenant(requested)
+	if err != nil {
+		return None, nil, "", statusError(err)
+	}
+	return need, id, tenant, nil
+}
+
+// authorize checks need on the collection and the aliases a request names in
+// tenant, a request naming neither needs it on every collection.
+func authorize(id *Identity, tenant string, need Permission, req any) error {
+	if what := forwarderOnly(req); what != "" && !id.Forwarder() {
+		return statusError(fmt.Errorf("%w: %s may not %s", ErrPermissionDenied, id.Principal, what))
+	}
+	var names []string
+	if named, ok := req.(interface{ GetCollectionName() string }); ok {
+		names = append(names, named.GetCollectionName())
+	}
+	if aliased, ok := req.(interface{ GetAlias() string }); ok {
+		names = append(names, aliased.GetAlias())
+	}
+	if swapped, ok := req.(interface{ GetOtherAlias() string }); ok {
+		names = append(names, swapped.GetOtherAlias())
+	}
+	if len(names) == 0 {
+		names = append(names, "")
+	}
+	for _, name := range names {
+		if err := id.Authorize(tenant, name, need); err != nil {
+			return statusError(err)
+		}
+	}
+	return nil
+}
+
+// forwarderOnly tells what a request does that only forwarders may do, empty
+// when it does nothing of the kind. The writes of everyone else are stamped
+// by whoever receives them.
+func forwarderOnly(req any) string {
+	if r, ok := req.(interface{ GetVersion() uint64 }); ok && r.GetVersion() != 0 {
+		return "pick the version of a write"
+	}
+	if r, ok := req.(interface{ GetHandoff() bool }); ok && r.GetHandoff() {
+		return "hand points off"
+	}
+	if r, ok := req.(interface{ GetInternal() bool }); ok && r.GetInternal() {
+		return "send internal writes"
+	}
+	return ""
+}
+
+func statusError(err error) error {
+	code := pb.ErrorCode_UNAUTHENTICATED
+	if errors.Is(err, ErrPermissionDenied) {
+		code = pb.ErrorCode_PERMISSION_DENIED
+	}
+	return errcode.Error(code, err.Error(), nil)
+}
+
+func credentialsFromContext(ctx context.Context) Credentials {
+	var creds Credentials
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(AuthorizationKey); len(v) > 0 {
+		creds.APIKey = BearerToken(v[0])
+	}
+	if v := md.Get(ForwardedKey); len(v) > 0 {
+		creds.Forwarded = v[0]
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 {
+			creds.Certificates = info.State.VerifiedChains[0]
+		}
+	}
+	return creds
+}
+
+// BearerToken returns the token of an authorization value, empty when it is
+// not a bearer token.
+func BearerToken(authorization string) string {
+	if len(authorization) < len(bearerPrefix) || !strings.EqualFold(authorization[:len(bearerPrefix)], bearerPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(authorization[len(bearerPrefix):])
+}
+
+// ForwardIdentity passes the principal of the incoming call on to the calls
+// made with its context, the backends authorize the original caller instead of
+// the gateway.
+func ForwardIdentity() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(forward(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(forward(ctx), desc, cc, method, opts...)
+		}),
+	}
+}
+
+func forward(ctx context.Context) context.Context {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, ForwardedKey, id.Principal)
+}
+
+// APIKey sends key with every call. Without transport security the key
+// travels in plain text.
+func APIKey(key string) credentials.PerRPCCredentials {
+	return apiKey(key)
+}
+
+type apiKey string
+
+func (k apiKey) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{AuthorizationKey: bearerPrefix + string(k)}, nil
+}
+
+func (apiKey) RequireTransportSecurity() bool {
+	return false
+}
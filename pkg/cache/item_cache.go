@@ -44,7 +44,7 @@ type itemCacheElem[K comparable, V Storable[K, V]] struct {
 
 type ItemCache[K comparable, V Storable[K, V]] struct {
 	items        map[K]*itemCacheElem[K, V]
-	itemsMu      sync.Mutex
+	itemsMu      sync.RWMutex
 	isAllInCache bool
 	storage      storage.Storage
 }
@@ -83,18 +83,24 @@ func (self *ItemCache[K, T]) read(id K) (T, error) {
 	return item.value, nil
 }
 
+// Get returns an item, concurrent Gets of cached items do not wait on each
+// other.
 func (self *ItemCache[K, V]) Get(id K) (value V, err error) {
-	self.itemsMu.Lock()
-	defer self.itemsMu.Unlock()
-	if item, ok := self.items[id]; ok {
-		if item.IsDeleted {
-			err = ErrNotFound
-			return
+	self.itemsMu.RLock()
+	item, ok := self.items[id]
+	self.itemsMu.RUnlock()
+	if !ok {
+		self.itemsMu.Lock()
+		defer self.itemsMu.Unlock()
+		if item, ok = self.items[id]; !ok {
+			return self.read(id)
 		}
-		return item.value, nil
 	}
-	value, err = self.read(id)
-	return
+	if item.IsDeleted {
+		err = ErrNotFound
+		return
+	}
+	return item.value, nil
 }
 
 func (self *ItemCache[K, V]) GetMany(ids ...K) ([]V, error) {
@@ -172,7 +178,32 @@ func (self *ItemCache[K, T]) Delete(ids ...K) error {
 	return nil
 }
 
+// ForEach calls fn for every item. Once every item is cached, concurrent
+// calls share the cache, fn must not add or remove items.
 func (self *ItemCache[K, T]) ForEach(fn func(id K, item T) error) error {
+	self.itemsMu.RLock()
+	if !self.isAllInCache {
+		self.itemsMu.RUnlock()
+		if err := self.readAll(); err != nil {
+			return err
+		}
+		self.itemsMu.RLock()
+	}
+	defer self.itemsMu.RUnlock()
+	for id, item := range self.items {
+		if item.IsDeleted {
+			continue
+		}
+		if err := fn(id, item.value); err != nil {
+			return err
+		}
+	}
+	// ---------------------------
+	return nil
+}
+
+// readAll caches the items of the storage that are not cached yet.
+func (self *ItemCache[K, T]) readAll() error {
 	self.itemsMu.Lock()
 	defer self.itemsMu.Unlock()
 	if !self.isAllInCache {
@@ -195,15 +226,6 @@ func (self *ItemCache[K, T]) ForEach(fn func(id K, item T) error) error {
 		}
 		self.isAllInCache = true
 	}
-	for id, item := range self.items {
-		if item.IsDeleted {
-			continue
-		}
-		if err := fn(id, item.value); err != nil {
-			return err
-		}
-	}
-	// ---------------------------
 	return nil
 }
 
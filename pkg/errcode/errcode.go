@@ -0,0 +1,102 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package errcode maps the error codes of the API onto gRPC status codes. A
+// status built here carries its error code as the reason of an ErrorInfo
+// detail, so nodes, the gateway and clients tell errors apart without parsing
+// messages and the code survives every hop.
+package errcode
+
+import (
+	"errors"
+
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain of the ErrorInfo details.
+const Domain = "nnv"
+
+var grpcCodes = map[pb.ErrorCode]codes.Code{
+	pb.ErrorCode_UNDEFINED:                     codes.Internal,
+	pb.ErrorCode_RPC_ERROR:                     codes.Unavailable,
+	pb.ErrorCode_COMMUNICATION_SHARD_RPC_ERROR: codes.Unavailable,
+	pb.ErrorCode_COMMUNICATION_SHARD_ERROR:     codes.Unknown,
+	pb.ErrorCode_MARSHAL_ERROR:                 codes.InvalidArgument,
+	pb.ErrorCode_PRECONDITION_FAILED:           codes.FailedPrecondition,
+	pb.ErrorCode_NOT_FOUND:                     codes.NotFound,
+	pb.ErrorCode_ALREADY_EXISTS:                codes.AlreadyExists,
+	pb.ErrorCode_DIMENSION_MISMATCH:            codes.InvalidArgument,
+	pb.ErrorCode_INVALID_FILTER:                codes.InvalidArgument,
+	pb.ErrorCode_INVALID_ARGUMENT:              codes.InvalidArgument,
+	pb.ErrorCode_READ_ONLY:                     codes.FailedPrecondition,
+	pb.ErrorCode_QUOTA_EXCEEDED:                codes.ResourceExhausted,
+	pb.ErrorCode_UNAUTHENTICATED:               codes.Unauthenticated,
+	pb.ErrorCode_PERMISSION_DENIED:             codes.PermissionDenied,
+	pb.ErrorCode_CANCELLED:                     codes.Canceled,
+	pb.ErrorCode_DEADLINE_EXCEEDED:             codes.DeadlineExceeded,
+	pb.ErrorCode_REPLICATION_FAILED:            codes.Aborted,
+}
+
+// GRPCCode returns the gRPC status code of an error code.
+func GRPCCode(code pb.ErrorCode) codes.Code {
+	if c, ok := grpcCodes[code]; ok {
+		return c
+	}
+	return codes.Unknown
+}
+
+// Status builds the status of an error, metadata ends up in the ErrorInfo
+// detail next to the code.
+func Status(code pb.ErrorCode, msg string, metadata map[string]string) *status.Status {
+	st := status.New(GRPCCode(code), msg)
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   code.String(),
+		Domain:   Domain,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+// Error is Status as an error.
+func Error(code pb.ErrorCode, msg string, metadata map[string]string) error {
+	return Status(code, msg, metadata).Err()
+}
+
+// FromError returns the error code a status error carries. Errors that did not
+// come from Status, such as transport failures, report false.
+func FromError(err error) (pb.ErrorCode, bool) {
+	var withStatus interface{ GRPCStatus() *status.Status }
+	if err == nil || !errors.As(err, &withStatus) {
+		return pb.ErrorCode_UNDEFINED, false
+	}
+	for _, detail := range withStatus.GRPCStatus().Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != Domain {
+			continue
+		}
+		if code, ok := pb.ErrorCode_value[info.GetReason()]; ok {
+			return pb.ErrorCode(code), true
+		}
+	}
+	return pb.ErrorCode_UNDEFINED, false
+}
@@ -0,0 +1,54 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEveryCodeIsMapped(t *testing.T) {
+	for value, name := range pb.ErrorCode_name {
+		_, ok := grpcCodes[pb.ErrorCode(value)]
+		require.True(t, ok, name)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	err := Error(pb.ErrorCode_QUOTA_EXCEEDED, "too many points", map[string]string{"collection": "docs"})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	code, ok := FromError(err)
+	require.True(t, ok)
+	require.Equal(t, pb.ErrorCode_QUOTA_EXCEEDED, code)
+
+	// the code survives wrapping
+	code, ok = FromError(fmt.Errorf("node-1: %w", err))
+	require.True(t, ok)
+	require.Equal(t, pb.ErrorCode_QUOTA_EXCEEDED, code)
+
+	_, ok = FromError(status.Error(codes.Unavailable, "connection refused"))
+	require.False(t, ok)
+	_, ok = FromError(errors.New("plain"))
+	require.False(t, ok)
+}
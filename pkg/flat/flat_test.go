@@ -8,6 +8,7 @@ import (
 	"slices"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/RoaringBitmap/roaring/roaring64"
 	"github.com/rs/zerolog"
@@ -130,6 +131,33 @@ func Test_Search(t *testing.T) {
 	require.Equal(t, float32(0), *results[0].Distance)
 }
 
+func Test_SearchInterrupted(t *testing.T) {
+	bucket := storage.NewMemStorage(false)
+	inv, err := flat.NewIndexFlat(flatParams, bucket)
+	require.NoError(t, err)
+	ctx := context.Background()
+	rps := randPoints(2000, 0)
+	require.NoError(t, <-inv.InsertUpdateDelete(ctx, withcontext.ProduceWithContext(ctx, rps)))
+	// ---------------------------
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	options := models.SearchVectorFlatOptions{Vector: rps[0].Vector, Limit: 10}
+	_, _, err = inv.Search(cancelled, options, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	// A passed deadline answers with the points scanned until then
+	options.Deadline = time.Now()
+	rSet, results, err := inv.Search(ctx, options, nil)
+	require.ErrorIs(t, err, models.ErrPartialResults)
+	require.EqualValues(t, 10, rSet.GetCardinality())
+	require.Len(t, results, 10)
+	// ---------------------------
+	// Writes stop on cancellation
+	in := make(chan models.IndexVectorChange, 1)
+	in <- randPoints(1, 5000)[0]
+	require.ErrorIs(t, <-inv.InsertUpdateDelete(cancelled, in), context.Canceled)
+	checkVectorCount(t, bucket, 2000)
+}
+
 func Test_Recall(t *testing.T) {
 	zerolog.SetGlobalLevel(zerolog.Disabled)
 	distFnNames := []string{models.DistanceCosine, models.DistanceEuclidean, models.DistanceDot, models.DistanceHaversine}
@@ -2,6 +2,7 @@ package flat
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,6 +14,10 @@ import (
 	"github.com/sjy-dv/nnv/storage"
 )
 
+// checkInterval is how many points a search scans between looking at its
+// context.
+const checkInterval = 256
+
 type IndexFlat struct {
 	vecStore vectorspace.VectorStore
 }
@@ -86,8 +91,16 @@ func (inf IndexFlat) Search(ctx context.Context, options models.SearchVectorFlat
 	 * we keep it single-threaded. Also no reasonably sized collection should
 	 * use flat index as the main one. */
 	startTime := time.Now()
+	budget, cancel := options.Budget(ctx)
+	defer cancel()
 	res := make([]models.SearchResult, 0, options.Limit)
+	var scanned int
 	err := inf.vecStore.ForEach(func(point vectorspace.VectorStorePoint) error {
+		if scanned++; scanned%checkInterval == 0 {
+			if err := budget.Err(); err != nil {
+				return err
+			}
+		}
 		if filter != nil && !filter.Contains(point.Id()) {
 			return nil
 		}
@@ -118,14 +131,21 @@ func (inf IndexFlat) Search(ctx context.Context, options models.SearchVectorFlat
 		}
 		return nil
 	})
-	if err != nil {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	partial := err != nil && errors.Is(err, budget.Err())
+	if err != nil && !partial {
 		return nil, nil, fmt.Errorf("failed to iterate over points: %w", err)
 	}
-	log.Debug().Dur("elapsed", time.Since(startTime)).Msg("search flat")
+	log.Debug().Dur("elapsed", time.Since(startTime)).Bool("partial", partial).Msg("search flat")
 	// ---------------------------
 	rSet := roaring64.New()
 	for _, r := range res {
 		rSet.Add(r.NodeId)
 	}
+	if partial {
+		return rSet, res, models.ErrPartialResults
+	}
 	return rSet, res, nil
 }
@@ -0,0 +1,114 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package hlc implements hybrid logical clocks. A timestamp stays close to the
+// wall clock but never goes backwards and always moves past every timestamp
+// the clock has seen, so causally related writes are ordered even when the
+// wall clocks of the nodes disagree.
+package hlc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const logicalBits = 16
+
+// MaxSkew bounds how far a remote timestamp may run ahead of the wall clock.
+// A clock that followed a timestamp far in the future would stamp every later
+// write with it and could not be caught up with.
+const MaxSkew = time.Minute
+
+var (
+	ErrClockSkew = errors.New("hlc: remote timestamp too far ahead of the wall clock")
+	// the clock reached the largest timestamp and cannot move past it
+	ErrClockExhausted = errors.New("hlc: clock exhausted")
+)
+
+// Timestamp packs the wall clock in milliseconds into the high 48 bits and a
+// logical counter into the low 16. The zero timestamp is older than anything
+// a clock returns.
+type Timestamp uint64
+
+func New(physical time.Time, logical uint16) Timestamp {
+	return Timestamp(uint64(physical.UnixMilli())<<logicalBits | uint64(logical))
+}
+
+func (t Timestamp) Physical() time.Time {
+	return time.UnixMilli(int64(t >> logicalBits))
+}
+
+func (t Timestamp) Logical() uint16 {
+	return uint16(t)
+}
+
+func (t Timestamp) physical() uint64 {
+	return uint64(t) >> logicalBits
+}
+
+type Clock struct {
+	mu   sync.Mutex
+	last Timestamp
+	wall func() time.Time
+}
+
+func NewClock() *Clock {
+	return &Clock{wall: time.Now}
+}
+
+// NewClockWithWall is for tests that need to move the wall clock.
+func NewClockWithWall(wall func() time.Time) *Clock {
+	return &Clock{wall: wall}
+}
+
+// tick moves the clock past both the wall clock and t.
+func (c *Clock) tick(t Timestamp) (Timestamp, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	wall := c.wall()
+	if t.physical() > New(wall.Add(MaxSkew), 0).physical() {
+		return 0, fmt.Errorf("%w: %s is ahead of %s", ErrClockSkew, t.Physical().UTC(), wall.UTC())
+	}
+	last := max(c.last, t)
+	now := New(wall, 0)
+	switch {
+	case now.physical() > last.physical():
+		c.last = now
+	case last == math.MaxUint64:
+		return 0, ErrClockExhausted
+	default:
+		// a full logical counter carries into the physical part, which
+		// still keeps the order
+		c.last = last + 1
+	}
+	return c.last, nil
+}
+
+// Now returns a timestamp newer than any returned or observed before.
+func (c *Clock) Now() (Timestamp, error) {
+	return c.tick(0)
+}
+
+// Update observes a timestamp of another node and returns a timestamp newer
+// than it. Timestamps more than MaxSkew ahead of the wall clock are refused
+// and leave the clock as it was.
+func (c *Clock) Update(remote Timestamp) (Timestamp, error) {
+	return c.tick(remote)
+}
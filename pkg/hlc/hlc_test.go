@@ -0,0 +1,96 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package hlc
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClock(t *testing.T) {
+	wall := time.UnixMilli(1_700_000_000_000)
+	c := NewClockWithWall(func() time.Time { return wall })
+	now := func() Timestamp {
+		t.Helper()
+		ts, err := c.Now()
+		require.NoError(t, err)
+		return ts
+	}
+	update := func(remote Timestamp) Timestamp {
+		t.Helper()
+		ts, err := c.Update(remote)
+		require.NoError(t, err)
+		return ts
+	}
+
+	t1 := now()
+	require.Equal(t, wall, t1.Physical())
+	require.Zero(t, t1.Logical())
+
+	// a stalled or skewed wall clock only advances the logical part
+	t2 := now()
+	require.Greater(t, t2, t1)
+	require.Equal(t, wall, t2.Physical())
+	require.EqualValues(t, 1, t2.Logical())
+	wall = wall.Add(-time.Second)
+	t3 := now()
+	require.Greater(t, t3, t2)
+
+	// a remote timestamp ahead of the wall clock pulls the clock forward
+	remote := New(wall.Add(MaxSkew), 5)
+	t4 := update(remote)
+	require.Greater(t, t4, remote)
+	require.Equal(t, remote.Physical(), t4.Physical())
+	require.EqualValues(t, 6, t4.Logical())
+	require.Greater(t, now(), t4)
+
+	// once the wall clock catches up the logical part resets
+	wall = wall.Add(2 * time.Minute)
+	t5 := now()
+	require.Equal(t, wall, t5.Physical())
+	require.Zero(t, t5.Logical())
+
+	// the logical counter carries into the physical part
+	full := New(wall, 1<<16-1)
+	require.Greater(t, update(full), full)
+}
+
+func TestClockBounds(t *testing.T) {
+	wall := time.UnixMilli(1_700_000_000_000)
+	c := NewClockWithWall(func() time.Time { return wall })
+	before, err := c.Now()
+	require.NoError(t, err)
+
+	// timestamps too far ahead are refused and do not move the clock
+	for _, remote := range []Timestamp{New(wall.Add(MaxSkew+time.Millisecond), 0), math.MaxUint64} {
+		_, err := c.Update(remote)
+		require.ErrorIs(t, err, ErrClockSkew)
+	}
+	after, err := c.Now()
+	require.NoError(t, err)
+	require.Equal(t, before+1, after)
+
+	// a clock at the largest timestamp fails instead of wrapping around
+	c.last = math.MaxUint64
+	wall = time.UnixMilli(int64(c.last.physical()))
+	_, err = c.Now()
+	require.ErrorIs(t, err, ErrClockExhausted)
+}
@@ -0,0 +1,213 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package hnsw
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sjy-dv/nnv/pkg/conversion"
+	"github.com/sjy-dv/nnv/storage"
+)
+
+// ------------------------------
+// Graph Storage
+// ------------------------------
+
+// The graph is stored next to the vectors. Every node keeps its neighbors
+// under its node key with the 'e' suffix as one edge list: the number of
+// neighbors on level 0 followed by them, then the same for level 1 and so on.
+// The vector of a node stays with the vector store under the 'v' suffix, the
+// store drops it on delete so tombstones keep theirs under the 't' suffix
+// until a compaction removes them. The entry point is stored followed by the
+// dimension of the vectors, graphs written before hold the entry point only.
+var entryPointKey = []byte("hnswEntryPoint")
+
+// touch marks a node whose links changed, Flush writes it.
+func (h *HNSW) touch(id uint64) {
+	h.dirty[id] = struct{}{}
+}
+
+// writable checks that the graph may change. Nodes are only marked while the
+// graph is bound to the storage of a write transaction, so that Flush writes
+// them along with that transaction and no other. A graph without storage only
+// lives in memory.
+func (h *HNSW) writable() error {
+	if h.storage != nil && h.storage.IsReadOnly() {
+		return errors.New("HNSW graph is bound to a read only storage")
+	}
+	return nil
+}
+
+func encodeEntryPoint(id uint64, d int) []byte {
+	return append(conversion.Uint64ToBytes(id), conversion.Uint64ToBytes(uint64(d))...)
+}
+
+func encodeNeighbors(node *Node) []byte {
+	size := len(node.Neighbors)
+	for _, neighbors := range node.Neighbors {
+		size += len(neighbors)
+	}
+	edges := make([]uint64, 0, size)
+	for _, neighbors := range node.Neighbors {
+		edges = append(edges, uint64(len(neighbors)))
+		edges = append(edges, neighbors...)
+	}
+	return conversion.EdgeListToBytes(edges)
+}
+
+func decodeNode(id uint64, vector []float32, b []byte) (*Node, error) {
+	edges := conversion.BytesToEdgeList(b)
+	node := &Node{ID: id, Vector: vector}
+	for len(edges) > 0 {
+		count := edges[0]
+		if count > uint64(len(edges)-1) {
+			return nil, fmt.Errorf("neighbors of node %d are truncated", id)
+		}
+		// the capacity ends with the level, appends must not spill into the
+		// next one
+		node.Neighbors = append(node.Neighbors, edges[1:count+1:count+1])
+		edges = edges[count+1:]
+	}
+	if len(node.Neighbors) == 0 {
+		return nil, fmt.Errorf("node %d has no levels", id)
+	}
+	node.Level = len(node.Neighbors) - 1
+	return node, nil
+}
+
+// Load reads a graph written by Flush from the storage, it reports false when
+// the storage holds none.
+func (h *HNSW) Load(storage storage.Storage) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entry := storage.Get(entryPointKey)
+	if entry == nil {
+		return false, nil
+	}
+	err := storage.ForEach(func(k, v []byte) error {
+		id, ok := conversion.NodeIdFromKey(k, 'e')
+		if !ok {
+			return nil
+		}
+		vector := storage.Get(conversion.NodeKey(id, 'v'))
+		if tombstone := storage.Get(conversion.NodeKey(id, 't')); tombstone != nil {
+			vector = tombstone
+			h.tombstones[id] = struct{}{}
+		}
+		if vector == nil {
+			return fmt.Errorf("vector of node %d is missing", id)
+		}
+		node, err := decodeNode(id, conversion.BytesToFloat32(vector), v)
+		if err != nil {
+			return err
+		}
+		h.addNode(node)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(entry) < 8 {
+		return false, errors.New("entry point is truncated")
+	}
+	d := h.D
+	if len(entry) >= 16 {
+		d = int(conversion.BytesToUint64(entry[8:16]))
+	}
+	for _, node := range h.Nodes {
+		if d == 0 {
+			d = len(node.Vector)
+		}
+		if len(node.Vector) != d {
+			return false, fmt.Errorf("vector of node %d has dimension %d, the graph %d", node.ID, len(node.Vector), d)
+		}
+		for _, neighbors := range node.Neighbors {
+			for _, id := range neighbors {
+				h.addEdge(node.ID, id)
+			}
+		}
+	}
+	if h.D != 0 && d != 0 && d != h.D {
+		return false, fmt.Errorf("graph has dimension %d, the index %d", d, h.D)
+	}
+	h.D = d
+	h.EntryPointID = conversion.BytesToUint64(entry)
+	if _, exists := h.Nodes[h.EntryPointID]; h.EntryPointID != 0 && !exists {
+		return false, fmt.Errorf("entry point %d is missing", h.EntryPointID)
+	}
+	h.storage = storage
+	clear(h.dirty)
+	return true, nil
+}
+
+// Flush writes the nodes whose links changed since the last flush, the entry
+// point and the dimension to the storage, removed nodes are deleted from it. It
+// belongs to the write transaction the nodes changed in. A graph without
+// storage only lives in memory.
+func (h *HNSW) Flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.storage == nil || len(h.dirty) == 0 {
+		return nil
+	}
+	if h.storage.IsReadOnly() {
+		return errors.New("cannot flush HNSW graph to read only storage")
+	}
+	for id := range h.dirty {
+		key, tombstoneKey := conversion.NodeKey(id, 'e'), conversion.NodeKey(id, 't')
+		node, exists := h.Nodes[id]
+		if !exists {
+			if err := h.storage.Delete(key); err != nil {
+				return fmt.Errorf("failed to delete node %d: %w", id, err)
+			}
+			if err := h.storage.Delete(tombstoneKey); err != nil {
+				return fmt.Errorf("failed to delete tombstone %d: %w", id, err)
+			}
+			continue
+		}
+		if err := h.storage.Put(key, encodeNeighbors(node)); err != nil {
+			return fmt.Errorf("failed to write node %d: %w", id, err)
+		}
+		var err error
+		if h.isTombstone(id) {
+			err = h.storage.Put(tombstoneKey, conversion.Float32ToBytes(node.Vector))
+		} else {
+			err = h.storage.Delete(tombstoneKey)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write tombstone %d: %w", id, err)
+		}
+	}
+	if err := h.storage.Put(entryPointKey, encodeEntryPoint(h.EntryPointID, h.D)); err != nil {
+		return fmt.Errorf("failed to write entry point: %w", err)
+	}
+	clear(h.dirty)
+	return nil
+}
+
+// UpdateStorage binds the graph to the storage of the current transaction. The
+// graph only changes while bound to a write, see writable, and the owner
+// flushes it before that transaction ends.
+func (h *HNSW) UpdateStorage(storage storage.Storage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.storage = storage
+}
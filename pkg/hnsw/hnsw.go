@@ -4,14 +4,17 @@ package hnsw
 
 import (
 	"container/heap"
+	"context"
 	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"slices"
 	"sort"
 	"sync"
 
 	"github.com/RoaringBitmap/roaring/roaring64"
+	"github.com/sjy-dv/nnv/storage"
 )
 
 // ------------------------------
@@ -44,6 +47,22 @@ func NewNode(id uint64, vector []float32, level, M int) *Node {
 // HNSW Struct
 // ------------------------------
 
+// DefaultEFSearch is the number of candidates a search keeps on layer 0 when
+// the request does not pick one.
+const DefaultEFSearch = 64
+
+// DefaultBruteForceLimit is the size up to which a search filter is scanned
+// instead of the graph.
+const DefaultBruteForceLimit = 1000
+
+// checkInterval is how many nodes a scan compares between checks of its
+// context.
+const checkInterval = 256
+
+// DefaultCompactionRatio is the share of deleted nodes a graph keeps before
+// it removes them.
+const DefaultCompactionRatio = 0.1
+
 // MetricType defines the type of metric used.
 type MetricType string
 
@@ -63,14 +82,28 @@ type HNSW struct {
 	Metric         MetricType
 	SimilarityFunc func(a, b []float32) float32
 	D              int
-	M              int
+	M              int // Maximum number of neighbors on the upper layers
+	Mmax0          int // Maximum number of neighbors on layer 0
 	EFConstruction int
-	LevelMax       int
-	EntryPointID   uint64           // 0 indicates no entry point
-	Nodes          map[uint64]*Node // Map from node ID to Node
-	Probs          []float32        // Probability distribution for level selection
-	mu             sync.RWMutex     // To handle concurrent access
-	nextID         uint64           // Next node ID to assign (starts from 1)
+	LevelMax       int                 // Highest level SelectLevel picks
+	EntryPointID   uint64              // 0 indicates no entry point
+	Nodes          map[uint64]*Node    // Map from node ID to Node
+	Probs          []float32           // Probability distribution for level selection
+	mu             sync.RWMutex        // To handle concurrent access
+	nextID         uint64              // Next node ID to assign (starts from 1)
+	storage        storage.Storage     // Where Flush writes the graph
+	dirty          map[uint64]struct{} // Nodes changed since the last Flush
+	// CompactionRatio is the share of tombstones that starts a compaction
+	CompactionRatio float64
+	// BruteForceLimit is the size up to which a search filter is scanned
+	BruteForceLimit int
+	tombstones      map[uint64]struct{} // Deleted nodes still in the graph
+	// nodes that link to a node on any level, a removed node is dropped
+	// from all of them
+	incoming map[uint64][]uint64
+	// number of nodes whose top layer is each level, see nextEntryPoint
+	levels    []int
+	compactor *Compactor
 }
 
 // NewHNSW creates a new HNSW instance.
@@ -86,12 +119,19 @@ func NewHNSW(M, efConstruction int, d int, metric MetricType) (*HNSW, error) {
 		SimilarityFunc: simFunc,
 		D:              d,
 		M:              M,
+		Mmax0:          2 * M,
 		EFConstruction: efConstruction,
 		LevelMax:       levelMax,
 		EntryPointID:   0, // 0 indicates no entry point
 		Nodes:          make(map[uint64]*Node),
 		Probs:          probs,
 		nextID:         1, // Start IDs from 1
+		dirty:          make(map[uint64]struct{}),
+
+		CompactionRatio: DefaultCompactionRatio,
+		BruteForceLimit: DefaultBruteForceLimit,
+		tombstones:      make(map[uint64]struct{}),
+		incoming:        make(map[uint64][]uint64),
 	}, nil
 }
 
@@ -134,111 +174,255 @@ func (h *HNSW) SelectLevel() int {
 	return len(h.Probs) - 1
 }
 
-// AddNodeToGraph adds a node to the HNSW graph.
+// AddNodeToGraph adds a node to the HNSW graph. It walks greedily down to the
+// top layer of the node, then on every layer below it runs a beam search of
+// EFConstruction candidates, links the node to the M neighbors picked by the
+// heuristic and prunes the neighbors that went over their maximum.
 // Assumes that the caller has already acquired the necessary lock.
 func (h *HNSW) AddNodeToGraph(node *Node) {
+	h.touch(node.ID)
 	if h.EntryPointID == 0 { // 0 indicates no entry point
 		h.EntryPointID = node.ID
-		h.Nodes[node.ID] = node
+		h.addNode(node)
 		return
 	}
 
-	currentNode := h.Nodes[h.EntryPointID]
-	closestNode := currentNode
-
-	for level := h.LevelMax; level >= 0; level-- {
-		for {
-			nextNode, maxSim := h.findMostSimilar(node, currentNode, level)
-			if nextNode != nil && maxSim > h.SimilarityFunc(node.Vector, closestNode.Vector) {
-				currentNode = nextNode
-				closestNode = currentNode
-			} else {
-				break
-			}
+	entry := h.Nodes[h.EntryPointID]
+	entryPoints := []*Item{h.descend(node.Vector, node.Level)}
+	// The node is only reached through the links made below, a layer is
+	// searched before the node is linked on it.
+	h.addNode(node)
+	for level := min(node.Level, entry.Level); level >= 0; level-- {
+		candidates, _ := h.searchLayer(context.Background(), node.Vector, entryPoints, max(h.EFConstruction, h.M), level, nil)
+		for _, neighbor := range h.selectNeighbors(candidates, h.M) {
+			node.Neighbors[level] = append(node.Neighbors[level], neighbor.id)
+			h.addEdge(node.ID, neighbor.id)
+			h.link(h.Nodes[neighbor.id], node, level)
 		}
+		entryPoints = candidates
 	}
 
-	closestLevel := min(node.Level, closestNode.Level)
+	if node.Level > entry.Level {
+		h.EntryPointID = node.ID
+	}
+}
 
-	for level := 0; level <= closestLevel; level++ {
-		// Add node to closestNode's neighbors
-		closestNode.Neighbors[level] = append(closestNode.Neighbors[level], node.ID)
-		if len(closestNode.Neighbors[level]) > h.M {
-			// Remove the farthest neighbor
-			h.removeFarthest(closestNode, level, node.Vector)
-		}
+// addNode puts a node in the graph and counts it on its top level.
+func (h *HNSW) addNode(node *Node) {
+	h.Nodes[node.ID] = node
+	for len(h.levels) <= node.Level {
+		h.levels = append(h.levels, 0)
+	}
+	h.levels[node.Level]++
+}
 
-		// Add closestNode to node's neighbors
-		node.Neighbors[level] = append(node.Neighbors[level], closestNode.ID)
-		if len(node.Neighbors[level]) > h.M {
-			h.removeFarthest(node, level, closestNode.Vector)
-		}
+// maxConnections returns how many neighbors a node keeps on a level, layer 0
+// holds every node and gets more links.
+func (h *HNSW) maxConnections(level int) int {
+	if level == 0 {
+		return h.Mmax0
 	}
+	return h.M
+}
 
-	h.Nodes[node.ID] = node
+// neighbors returns the neighbors of a node on a level, none for nodes that
+// are gone or do not reach the level.
+func (h *HNSW) neighbors(id uint64, level int) []uint64 {
+	node, exists := h.Nodes[id]
+	if !exists || level >= len(node.Neighbors) {
+		return nil
+	}
+	return node.Neighbors[level]
+}
 
-	if node.Level > h.LevelMax {
-		h.LevelMax = node.Level
+// descend walks greedily from the entry point down to the layer above level
+// and returns the node most similar to the query it found.
+func (h *HNSW) descend(query []float32, level int) *Item {
+	entry := h.Nodes[h.EntryPointID]
+	best := &Item{id: entry.ID, score: h.SimilarityFunc(query, entry.Vector)}
+	for lvl := entry.Level; lvl > level; lvl-- {
+		for changed := true; changed; {
+			changed = false
+			for _, neighborID := range h.neighbors(best.id, lvl) {
+				neighbor, exists := h.Nodes[neighborID]
+				if !exists {
+					continue
+				}
+				if sim := h.SimilarityFunc(query, neighbor.Vector); sim > best.score {
+					best = &Item{id: neighborID, score: sim}
+					changed = true
+				}
+			}
+		}
+	}
+	return best
+}
+
+// searchLayer runs a beam search on one level starting from the entry points.
+// It returns up to ef nodes, the most similar to the query first. Nodes that
+// accept rejects are traversed but not returned, a nil accept takes all.
+// Once ctx is done it returns the nodes found so far with ctx.Err().
+func (h *HNSW) searchLayer(ctx context.Context, query []float32, entryPoints []*Item, ef, level int, accept func(uint64) bool) ([]*Item, error) {
+	visited := make(map[uint64]bool, ef*4)
+	candidates := &PriorityQueue{}
+	results := &MinPriorityQueue{}
+	add := func(id uint64, sim float32) {
+		heap.Push(candidates, &Item{id: id, score: sim})
+		if accept == nil || accept(id) {
+			heap.Push(results, &Item{id: id, score: sim})
+			if results.Len() > ef {
+				heap.Pop(results)
+			}
+		}
+	}
+	// visit reports whether id is a node seen for the first time
+	visit := func(id uint64) bool {
+		if visited[id] {
+			return false
+		}
+		visited[id] = true
+		node, exists := h.Nodes[id]
+		if !exists {
+			return false
+		}
+		if sim := h.SimilarityFunc(query, node.Vector); results.Len() < ef || sim > results.Worst() {
+			add(id, sim)
+		}
+		return true
+	}
+	for _, ep := range entryPoints {
+		visited[ep.id] = true
+		add(ep.id, ep.score)
+	}
 
-		// Extend all existing nodes' Neighbors slices to accommodate the new LevelMax
-		for _, existingNode := range h.Nodes {
-			for len(existingNode.Neighbors) <= h.LevelMax {
-				existingNode.Neighbors = append(existingNode.Neighbors, []uint64{})
+	var err error
+	for candidates.Len() > 0 {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		current := heap.Pop(candidates).(*Item)
+		// every node left is further than the worst result
+		if results.Len() >= ef && current.score < results.Worst() {
+			break
+		}
+		for _, neighborID := range h.neighbors(current.id, level) {
+			if !visit(neighborID) || accept == nil || accept(neighborID) {
+				continue
+			}
+			// The neighbors of a rejected neighbor are looked at right away,
+			// with a selective filter the matching nodes are rarely linked
+			// to each other directly.
+			for _, id := range h.neighbors(neighborID, level) {
+				visit(id)
 			}
 		}
+	}
 
-		// Update EntryPointID to the new node
-		h.EntryPointID = node.ID
+	found := make([]*Item, results.Len())
+	for i := len(found) - 1; i >= 0; i-- {
+		found[i] = heap.Pop(results).(*Item)
 	}
+	return found, err
 }
 
-// findMostSimilar finds the most similar neighbor at a given level.
-func (h *HNSW) findMostSimilar(target *Node, current *Node, level int) (*Node, float32) {
-	var bestNode *Node
-	maxSim := float32(-math.MaxFloat32)
-	if level >= len(current.Neighbors) {
-		return nil, maxSim
+// selectNeighbors picks up to m of the candidates, sorted by similarity to the
+// base node, with the heuristic of the HNSW paper. A candidate is kept only if
+// it is more similar to the base than to every neighbor kept before it, so the
+// links spread out instead of piling into the closest cluster.
+func (h *HNSW) selectNeighbors(candidates []*Item, m int) []*Item {
+	if len(candidates) <= m {
+		return candidates
 	}
-	neighbors := current.Neighbors[level]
-	for _, neighborID := range neighbors {
-		if neighborID == 0 {
-			continue
+	selected := make([]*Item, 0, m)
+	for _, candidate := range candidates {
+		if len(selected) == m {
+			break
 		}
-		neighbor, exists := h.Nodes[neighborID]
-		if !exists {
-			continue
+		vector := h.Nodes[candidate.id].Vector
+		keep := true
+		for _, s := range selected {
+			if h.SimilarityFunc(vector, h.Nodes[s.id].Vector) > candidate.score {
+				keep = false
+				break
+			}
 		}
-		sim := h.SimilarityFunc(target.Vector, neighbor.Vector)
-		if sim > maxSim {
-			maxSim = sim
-			bestNode = neighbor
+		if keep {
+			selected = append(selected, candidate)
 		}
 	}
-	return bestNode, maxSim
+	return selected
 }
 
-// removeFarthest removes the farthest neighbor based on similarity.
-func (h *HNSW) removeFarthest(node *Node, level int, referenceVector []float32) {
-	if len(node.Neighbors[level]) <= h.M {
+// link adds the new node to the neighbors of node on a level. Once node has
+// more neighbors than the level allows they are selected again.
+func (h *HNSW) link(node, newNode *Node, level int) {
+	h.touch(node.ID)
+	node.Neighbors[level] = append(node.Neighbors[level], newNode.ID)
+	h.addEdge(node.ID, newNode.ID)
+	if len(node.Neighbors[level]) <= h.maxConnections(level) {
 		return
 	}
-	// Find the neighbor with the lowest similarity and remove it
-	minSim := float32(math.MaxFloat32)
-	minIdx := -1
-	for i, neighborID := range node.Neighbors[level] {
+	candidates := make([]*Item, 0, len(node.Neighbors[level]))
+	for _, neighborID := range node.Neighbors[level] {
 		neighbor, exists := h.Nodes[neighborID]
 		if !exists {
 			continue
 		}
-		sim := h.SimilarityFunc(referenceVector, neighbor.Vector)
-		if sim < minSim {
-			minSim = sim
-			minIdx = i
+		candidates = append(candidates, &Item{id: neighborID, score: h.SimilarityFunc(node.Vector, neighbor.Vector)})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	h.setNeighbors(node, level, h.selectNeighbors(candidates, h.maxConnections(level)))
+}
+
+// setNeighbors replaces the neighbors of a node on a level with the selected
+// ones and keeps the incoming edges in step.
+func (h *HNSW) setNeighbors(node *Node, level int, selected []*Item) {
+	prev := slices.Clone(node.Neighbors[level])
+	node.Neighbors[level] = node.Neighbors[level][:0]
+	for _, s := range selected {
+		node.Neighbors[level] = append(node.Neighbors[level], s.id)
+		h.addEdge(node.ID, s.id)
+	}
+	for _, id := range prev {
+		if !slices.Contains(node.Neighbors[level], id) {
+			h.dropEdge(node, id)
+		}
+	}
+	h.touch(node.ID)
+}
+
+// addEdge records that from links to to.
+func (h *HNSW) addEdge(from, to uint64) {
+	if !slices.Contains(h.incoming[to], from) {
+		h.incoming[to] = append(h.incoming[to], from)
+	}
+}
+
+// dropEdge forgets that from links to to, unless it still does on another
+// level.
+func (h *HNSW) dropEdge(from *Node, to uint64) {
+	for _, neighbors := range from.Neighbors {
+		if slices.Contains(neighbors, to) {
+			return
 		}
 	}
-	if minIdx != -1 {
-		node.Neighbors[level] = append(node.Neighbors[level][:minIdx], node.Neighbors[level][minIdx+1:]...)
+	h.dropSource(to, from.ID)
+}
+
+// dropSource removes from from the nodes that link to to.
+func (h *HNSW) dropSource(to, from uint64) {
+	sources := h.incoming[to]
+	if i := slices.Index(sources, from); i >= 0 {
+		sources[i] = sources[len(sources)-1]
+		sources = sources[:len(sources)-1]
+	}
+	if len(sources) == 0 {
+		delete(h.incoming, to)
+		return
 	}
+	h.incoming[to] = sources
 }
 
 // AddPoint adds a new point to the HNSW graph with an automatically assigned ID.
@@ -249,158 +433,118 @@ func (h *HNSW) AddPoint(vector []float32, id uint64) (uint64, error) {
 	// id := h.nextID
 	// h.nextID++
 
+	if err := h.writable(); err != nil {
+		return 0, err
+	}
 	if h.D != 0 && len(vector) != h.D {
 		return 0, fmt.Errorf("all vectors must be of the same dimension")
 	}
 	h.D = len(vector)
 
+	// an update inserts the new vector in place of the old one
+	if node, exists := h.Nodes[id]; exists {
+		h.unlink(node)
+	}
 	level := h.SelectLevel()
 	node := NewNode(id, vector, level, h.M)
 	h.AddNodeToGraph(node)
 	return id, nil
 }
 
-// DeletePoint removes a point from the HNSW index.
+// DeletePoint marks a point as deleted. The node stays in the graph as a
+// tombstone, searches pass through it but never return it, until a
+// compaction removes it. Once more than CompactionRatio of the nodes are
+// tombstones the compactor of the graph is asked for one, see SetCompactor.
 func (h *HNSW) DeletePoint(id uint64) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	node, exists := h.Nodes[id]
-	if !exists {
-		return fmt.Errorf("node with ID %d does not exist", id)
+	if err := h.writable(); err != nil {
+		return err
 	}
-
-	// Remove this node from its neighbors
-	for lvl, neighbors := range node.Neighbors {
-		for _, neighborID := range neighbors {
-			if neighborID == 0 {
-				continue
-			}
-			neighbor, exists := h.Nodes[neighborID]
-			if !exists {
-				continue
-			}
-			// Remove node.id from neighbor's neighbors at this level
-			for j, nid := range neighbor.Neighbors[lvl] {
-				if nid == id {
-					neighbor.Neighbors[lvl] = append(neighbor.Neighbors[lvl][:j], neighbor.Neighbors[lvl][j+1:]...)
-					break
-				}
-			}
-		}
+	if _, exists := h.Nodes[id]; !exists || h.isTombstone(id) {
+		return fmt.Errorf("node with ID %d does not exist", id)
 	}
+	h.tombstones[id] = struct{}{}
+	h.touch(id)
 
-	// Finally, remove the node
-	delete(h.Nodes, id)
-
-	// If the deleted node was the entry point, choose a new entry point
-	if h.EntryPointID == id {
-		if len(h.Nodes) > 0 {
-			for newEntryID := range h.Nodes {
-				h.EntryPointID = newEntryID
-				break
-			}
-		} else {
-			h.EntryPointID = 0 // 0 indicates no entry point
-		}
+	if float64(len(h.tombstones)) > h.CompactionRatio*float64(len(h.Nodes)) {
+		h.compactor.notify()
 	}
-
 	return nil
 }
 
-// SearchKNN searches for the k nearest neighbors to the query vector, excluding nodes in the filter.
-func (h *HNSW) Search(query []float32, k int, filter *roaring64.Bitmap) ([]SearchResult, error) {
+// Search finds the k nodes most similar to the query among the nodes in the
+// filter, all nodes for a nil filter, tombstones are never returned. It walks
+// greedily down the upper layers and keeps the ef best candidates on layer 0,
+// a larger ef finds closer nodes but takes longer. Filters of up to
+// BruteForceLimit nodes are compared with the query one by one instead.
+// Once ctx is done it returns the neighbors found so far with ctx.Err().
+func (h *HNSW) Search(ctx context.Context, query []float32, k, ef int, filter *roaring64.Bitmap) ([]SearchResult, error) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
 	if h.EntryPointID == 0 {
 		return nil, errors.New("the index is empty")
 	}
-
-	// Initialize the priority queue
-	candidates := &PriorityQueue{}
-	heap.Init(candidates)
-	initialScore := h.SimilarityFunc(query, h.Nodes[h.EntryPointID].Vector)
-	heap.Push(candidates, &Item{id: h.EntryPointID, score: initialScore})
-
-	result := make([]SearchResult, 0, k)
-	visited := make(map[uint64]bool)
-
-	for candidates.Len() > 0 && len(result) < k {
-		currentItem := heap.Pop(candidates).(*Item)
-		currentID := currentItem.id
-
-		if visited[currentID] {
-			continue
+	var found []*Item
+	var err error
+	if filter != nil && filter.GetCardinality() <= uint64(h.BruteForceLimit) {
+		found, err = h.bruteForce(ctx, query, k, filter)
+	} else {
+		if ef <= 0 {
+			ef = DefaultEFSearch
 		}
-		visited[currentID] = true
-
-		if filter != nil && filter.Contains(currentID) {
-			continue // Skip nodes in the filter
+		accept := func(id uint64) bool {
+			return !h.isTombstone(id) && (filter == nil || filter.Contains(id))
 		}
+		found, err = h.searchLayer(ctx, query, []*Item{h.descend(query, 0)}, max(ef, k), 0, accept)
+	}
 
-		currentNode := h.Nodes[currentID]
-		similarity := h.SimilarityFunc(currentNode.Vector, query)
+	result := make([]SearchResult, 0, k)
+	for _, item := range found[:min(k, len(found))] {
+		result = append(result, SearchResult{
+			ID:    item.id,
+			Score: item.score,
+		})
+	}
+	return result, err
+}
 
-		if similarity > 0 {
-			result = append(result, SearchResult{
-				ID:    currentID,
-				Score: similarity,
-			})
+// bruteForce compares the query with every node of the filter, the k most
+// similar first.
+func (h *HNSW) bruteForce(ctx context.Context, query []float32, k int, filter *roaring64.Bitmap) ([]*Item, error) {
+	results := &MinPriorityQueue{}
+	var err error
+	for i, it := 0, filter.Iterator(); it.HasNext(); i++ {
+		if i%checkInterval == 0 {
+			if err = ctx.Err(); err != nil {
+				break
+			}
 		}
-
-		if currentNode.Level == 0 {
+		id := it.Next()
+		node, exists := h.Nodes[id]
+		if !exists || h.isTombstone(id) {
 			continue
 		}
-
-		for level := currentNode.Level; level >= 0; level-- {
-			for _, neighborID := range currentNode.Neighbors[level] {
-				if !visited[neighborID] && (filter == nil || !filter.Contains(neighborID)) {
-					neighbor := h.Nodes[neighborID]
-					heap.Push(candidates, &Item{
-						id:    neighbor.ID,
-						score: h.SimilarityFunc(query, neighbor.Vector),
-					})
-				}
+		sim := h.SimilarityFunc(query, node.Vector)
+		if results.Len() < k || sim > results.Worst() {
+			heap.Push(results, &Item{id: id, score: sim})
+			if results.Len() > k {
+				heap.Pop(results)
 			}
 		}
 	}
-
-	// Sort the result by score in descending order
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Score > result[j].Score
-	})
-
-	if len(result) > k {
-		result = result[:k]
+	found := make([]*Item, results.Len())
+	for i := len(found) - 1; i >= 0; i-- {
+		found[i] = heap.Pop(results).(*Item)
 	}
-
-	return result, nil
+	return found, err
 }
 
-// Fit optimizes the HNSW index.
+// Fit optimizes the HNSW index. Inserts and deletes keep the graph in shape,
+// there is nothing left to do.
 func (h *HNSW) Fit() error {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	for _, node := range h.Nodes {
-		for lvl := 0; lvl <= node.Level; lvl++ {
-			sort.Slice(node.Neighbors[lvl], func(i, j int) bool {
-				return node.Neighbors[lvl][i] < node.Neighbors[lvl][j]
-			})
-		}
-	}
-
-	return nil
-}
-
-// Flush saves the HNSW index to storage.
-func (h *HNSW) Flush() error {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-
-	// TODO
-	fmt.Println("TODO FLUSH")
 	return nil
 }
 
@@ -417,13 +561,12 @@ func (h *HNSW) SizeInMemory() int64 {
 			size += 8 * int64(len(neighbors)) // Each neighbor is uint64
 		}
 	}
+	for _, sources := range h.incoming {
+		size += 8 * int64(len(sources)) // Incoming edges (uint64)
+	}
 	return size
 }
 
-func (h *HNSW) UpdateStorage(storage interface{}) {
-	fmt.Println("TODO")
-}
-
 // min returns the minimum of two integers.
 func min(a, b int) int {
 	if a < b {
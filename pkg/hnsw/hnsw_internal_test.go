@@ -0,0 +1,379 @@
+package hnsw
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/RoaringBitmap/roaring/roaring64"
+	"github.com/rs/zerolog"
+	"github.com/sjy-dv/nnv/pkg/conversion"
+	"github.com/sjy-dv/nnv/pkg/flat"
+	"github.com/sjy-dv/nnv/pkg/models"
+	"github.com/sjy-dv/nnv/pkg/withcontext"
+	"github.com/sjy-dv/nnv/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConstructionRecall(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	const (
+		size    = 3000
+		dim     = 16
+		k       = 10
+		queries = 100
+	)
+	r := rand.New(rand.NewSource(1))
+	randVector := func() []float32 {
+		vector := make([]float32, dim)
+		for j := range vector {
+			vector[j] = r.Float32()
+		}
+		return vector
+	}
+	points := make([]models.IndexVectorChange, size)
+	for i := range points {
+		points[i] = models.IndexVectorChange{Id: uint64(i + 2), Vector: randVector()}
+	}
+	h, err := NewHNSW(16, 100, dim, Euclidean)
+	require.NoError(t, err)
+	for _, p := range points {
+		_, err := h.AddPoint(p.Vector, p.Id)
+		require.NoError(t, err)
+	}
+	for _, node := range h.Nodes {
+		require.NotEmpty(t, node.Neighbors[0])
+		for level, neighbors := range node.Neighbors {
+			require.LessOrEqual(t, len(neighbors), h.maxConnections(level))
+		}
+	}
+	// the flat index scans every point and gives the ground truth
+	ctx := context.Background()
+	inf, err := flat.NewIndexFlat(models.IndexVectorFlatParameters{VectorSize: dim, DistanceMetric: models.DistanceEuclidean}, storage.NewMemStorage(false))
+	require.NoError(t, err)
+	require.NoError(t, <-inf.InsertUpdateDelete(ctx, withcontext.ProduceWithContext(ctx, points)))
+
+	// The queries are not in the index. The greedy construction linked each
+	// node to a single neighbor and found about 0.3 of them, even searched
+	// with a beam of DefaultEFSearch.
+	var found int
+	for q := 0; q < queries; q++ {
+		query := randVector()
+		_, truth, err := inf.Search(ctx, models.SearchVectorFlatOptions{Vector: query, Limit: k}, nil)
+		require.NoError(t, err)
+		results, err := h.Search(ctx, query, k, 0, nil)
+		require.NoError(t, err)
+		require.Len(t, results, k)
+		ids := make(map[uint64]bool)
+		for _, r := range results {
+			ids[r.ID] = true
+		}
+		for _, r := range truth {
+			if ids[r.NodeId] {
+				found++
+			}
+		}
+	}
+	recall := float64(found) / float64(queries*k)
+	require.GreaterOrEqual(t, recall, 0.9, "recall %.3f", recall)
+}
+
+func Test_FlushLoad(t *testing.T) {
+	bucket := storage.NewMemStorage(false)
+	h, err := NewHNSW(8, 50, 4, Euclidean)
+	require.NoError(t, err)
+	h.UpdateStorage(bucket)
+	add := func(id uint64) {
+		vector := []float32{rand.Float32(), rand.Float32(), rand.Float32(), rand.Float32()}
+		// the vector store keeps the vectors the graph is loaded with
+		require.NoError(t, bucket.Put(conversion.NodeKey(id, 'v'), conversion.Float32ToBytes(vector)))
+		_, err := h.AddPoint(vector, id)
+		require.NoError(t, err)
+	}
+	for id := uint64(2); id < 502; id++ {
+		add(id)
+	}
+	require.NoError(t, h.Flush())
+	require.Empty(t, h.dirty)
+
+	// only the nodes whose links changed are written again
+	add(502)
+	require.NoError(t, h.DeletePoint(2))
+	require.NoError(t, bucket.Delete(conversion.NodeKey(2, 'v')))
+	require.Less(t, len(h.dirty), 100)
+	require.NoError(t, h.Flush())
+	// the tombstone keeps the vector the vector store dropped
+	require.NotNil(t, bucket.Get(conversion.NodeKey(2, 't')))
+	require.NoError(t, h.DeletePoint(3))
+	require.NoError(t, h.Flush())
+
+	// the dimension is restored with the graph
+	loaded, err := NewHNSW(8, 50, 0, Euclidean)
+	require.NoError(t, err)
+	ok, err := loaded.Load(bucket)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 4, loaded.D)
+	require.Equal(t, h.EntryPointID, loaded.EntryPointID)
+	require.Equal(t, h.tombstones, loaded.tombstones)
+	require.Equal(t, edgeSets(h.incoming), edgeSets(loaded.incoming))
+	require.Equal(t, h.levels, loaded.levels)
+	require.Len(t, loaded.Nodes, len(h.Nodes))
+	for id, node := range h.Nodes {
+		require.Equal(t, node.Level, loaded.Nodes[id].Level)
+		require.Equal(t, node.Vector, loaded.Nodes[id].Vector)
+		for level, neighbors := range node.Neighbors {
+			require.ElementsMatch(t, neighbors, loaded.Nodes[id].Neighbors[level])
+		}
+	}
+
+	require.NoError(t, h.Compact(context.Background()))
+	require.NoError(t, h.Flush())
+	require.Nil(t, bucket.Get(conversion.NodeKey(2, 'e')))
+	require.Nil(t, bucket.Get(conversion.NodeKey(2, 't')))
+
+	// the graph only changes along with a write
+	loaded.UpdateStorage(storage.NewMemStorage(true))
+	_, err = loaded.AddPoint([]float32{1, 2, 3}, 600)
+	require.Error(t, err)
+	require.Error(t, loaded.DeletePoint(4))
+	require.NotContains(t, loaded.tombstones, uint64(4))
+	require.Empty(t, loaded.dirty)
+	loaded.UpdateStorage(bucket)
+	_, err = loaded.AddPoint([]float32{1, 2, 3}, 600)
+	require.Error(t, err)
+
+	empty, err := NewHNSW(8, 50, 4, Euclidean)
+	require.NoError(t, err)
+	ok, err = empty.Load(storage.NewMemStorage(false))
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func Test_DeleteCompact(t *testing.T) {
+	const dim = 8
+	h, err := NewHNSW(16, 100, dim, Euclidean)
+	require.NoError(t, err)
+	// compactions are started by hand until the end
+	h.CompactionRatio = 1
+	for id := uint64(2); id < 2002; id++ {
+		vector := make([]float32, dim)
+		for j := range vector {
+			vector[j] = rand.Float32()
+		}
+		_, err := h.AddPoint(vector, id)
+		require.NoError(t, err)
+	}
+	deleted := make(map[uint64]bool)
+	for id := range h.Nodes {
+		if rand.Intn(10) < 3 {
+			require.NoError(t, h.DeletePoint(id))
+			deleted[id] = true
+		}
+	}
+	require.Error(t, h.DeletePoint(uint64(len(h.Nodes)+10)))
+	// the entry point is replaced by a node on the highest level left
+	if !deleted[h.EntryPointID] {
+		require.NoError(t, h.DeletePoint(h.EntryPointID))
+		deleted[h.EntryPointID] = true
+	}
+	recall := func() float64 {
+		var found int
+		for q := 0; q < 30; q++ {
+			query := make([]float32, dim)
+			for j := range query {
+				query[j] = rand.Float32()
+			}
+			var live []SearchResult
+			for id, node := range h.Nodes {
+				if !deleted[id] {
+					live = append(live, SearchResult{ID: id, Score: h.SimilarityFunc(query, node.Vector)})
+				}
+			}
+			sort.Slice(live, func(i, j int) bool { return live[i].Score > live[j].Score })
+			truth := make(map[uint64]bool)
+			for _, r := range live[:10] {
+				truth[r.ID] = true
+			}
+			results, err := h.Search(context.Background(), query, 10, 0, nil)
+			require.NoError(t, err)
+			require.Len(t, results, 10)
+			for _, r := range results {
+				require.False(t, deleted[r.ID], "deleted node %d returned", r.ID)
+				if truth[r.ID] {
+					found++
+				}
+			}
+		}
+		return float64(found) / 300
+	}
+	require.GreaterOrEqual(t, recall(), 0.9)
+
+	require.NoError(t, h.Compact(context.Background()))
+	require.Empty(t, h.tombstones)
+	requireEdges(t, h)
+	entry := h.Nodes[h.EntryPointID]
+	for id, node := range h.Nodes {
+		require.False(t, deleted[id])
+		require.LessOrEqual(t, node.Level, entry.Level)
+		for _, neighbors := range node.Neighbors {
+			for _, neighborID := range neighbors {
+				require.False(t, deleted[neighborID], "node %d links to removed %d", id, neighborID)
+			}
+		}
+	}
+	require.GreaterOrEqual(t, recall(), 0.9)
+
+	// past the ratio deletes ask the compactor for a compaction
+	compactor := NewCompactor(context.Background(), h.Compact)
+	defer compactor.Stop()
+	h.SetCompactor(compactor)
+	h.CompactionRatio = 0.05
+	var ids []uint64
+	for id := range h.Nodes {
+		if rand.Intn(10) == 0 {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range ids {
+		require.NoError(t, h.DeletePoint(id))
+		deleted[id] = true
+	}
+	require.Eventually(t, func() bool {
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+		return len(h.tombstones) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.GreaterOrEqual(t, recall(), 0.9)
+}
+
+// requireEdges checks that the incoming edges mirror the neighbors of every
+// node, that no node links to one that is gone and that the nodes are
+// counted on their levels.
+func requireEdges(t *testing.T, h *HNSW) {
+	t.Helper()
+	incoming := make(map[uint64]map[uint64]struct{})
+	levels := make([]int, len(h.levels))
+	for id, node := range h.Nodes {
+		levels[node.Level]++
+		for _, neighbors := range node.Neighbors {
+			for _, neighborID := range neighbors {
+				_, exists := h.Nodes[neighborID]
+				require.True(t, exists, "node %d links to removed %d", id, neighborID)
+				if incoming[neighborID] == nil {
+					incoming[neighborID] = make(map[uint64]struct{})
+				}
+				incoming[neighborID][id] = struct{}{}
+			}
+		}
+	}
+	require.Equal(t, incoming, edgeSets(h.incoming))
+	require.Equal(t, levels, h.levels)
+}
+
+// edgeSets turns the incoming edges into sets, their order does not matter.
+func edgeSets(incoming map[uint64][]uint64) map[uint64]map[uint64]struct{} {
+	sets := make(map[uint64]map[uint64]struct{}, len(incoming))
+	for id, sources := range incoming {
+		sets[id] = make(map[uint64]struct{}, len(sources))
+		for _, source := range sources {
+			sets[id][source] = struct{}{}
+		}
+		if len(sets[id]) != len(sources) {
+			sets[id][0] = struct{}{} // a source listed twice
+		}
+	}
+	return sets
+}
+
+func Test_Unlink(t *testing.T) {
+	const dim = 8
+	h, err := NewHNSW(16, 100, dim, Euclidean)
+	require.NoError(t, err)
+	vector := func() []float32 {
+		v := make([]float32, dim)
+		for j := range v {
+			v[j] = rand.Float32()
+		}
+		return v
+	}
+	for id := uint64(2); id < 1002; id++ {
+		_, err := h.AddPoint(vector(), id)
+		require.NoError(t, err)
+	}
+	requireEdges(t, h)
+	// links are not symmetric, nodes the removed one does not link back to
+	// lose their edges as well
+	for id := uint64(2); id < 1002; id += 7 {
+		h.unlink(h.Nodes[id])
+		requireEdges(t, h)
+	}
+	// the entry point goes to a node on the highest level left
+	for range 3 {
+		h.unlink(h.Nodes[h.EntryPointID])
+		requireEdges(t, h)
+		entry := h.Nodes[h.EntryPointID]
+		for _, node := range h.Nodes {
+			require.LessOrEqual(t, node.Level, entry.Level)
+		}
+	}
+	// updates insert the node again in place of the old one
+	for id := uint64(3); id < 1002; id += 7 {
+		_, err := h.AddPoint(vector(), id)
+		require.NoError(t, err)
+	}
+	requireEdges(t, h)
+}
+
+func Test_FilteredSearch(t *testing.T) {
+	const dim = 8
+	h, err := NewHNSW(16, 100, dim, Euclidean)
+	require.NoError(t, err)
+	filter := roaring64.New()
+	for id := uint64(2); id < 3002; id++ {
+		vector := make([]float32, dim)
+		for j := range vector {
+			vector[j] = rand.Float32()
+		}
+		_, err := h.AddPoint(vector, id)
+		require.NoError(t, err)
+		if rand.Intn(50) == 0 {
+			filter.Add(id)
+		}
+	}
+	recall := func() float64 {
+		var found int
+		for q := 0; q < 30; q++ {
+			query := make([]float32, dim)
+			for j := range query {
+				query[j] = rand.Float32()
+			}
+			var matching []SearchResult
+			for _, id := range filter.ToArray() {
+				matching = append(matching, SearchResult{ID: id, Score: h.SimilarityFunc(query, h.Nodes[id].Vector)})
+			}
+			sort.Slice(matching, func(i, j int) bool { return matching[i].Score > matching[j].Score })
+			truth := make(map[uint64]bool)
+			for _, r := range matching[:10] {
+				truth[r.ID] = true
+			}
+			results, err := h.Search(context.Background(), query, 10, 0, filter)
+			require.NoError(t, err)
+			require.Len(t, results, 10)
+			for _, r := range results {
+				require.True(t, filter.Contains(r.ID), "node %d is not in the filter", r.ID)
+				if truth[r.ID] {
+					found++
+				}
+			}
+		}
+		return float64(found) / 300
+	}
+	// a small filter is scanned and exact
+	require.Equal(t, 1.0, recall())
+	// the graph is traversed through the nodes the filter rejects
+	h.BruteForceLimit = 0
+	require.GreaterOrEqual(t, recall(), 0.9)
+}
@@ -1,12 +1,16 @@
 package hnsw
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/RoaringBitmap/roaring/roaring64"
 	"github.com/rs/zerolog/log"
+	"github.com/sjy-dv/nnv/pkg/conversion"
 	"github.com/sjy-dv/nnv/pkg/models"
 	"github.com/sjy-dv/nnv/pkg/vectorspace"
 	"github.com/sjy-dv/nnv/pkg/withcontext"
@@ -19,7 +23,7 @@ type IndexHNSW struct {
 }
 
 func NewIndexHNSW(params models.IndexVectorHnswParameters, storage storage.Storage) (inh IndexHNSW, err error) {
-	hnswIndex, err := NewHNSW(int(params.M), int(params.EfConstruction), int(params.VectorSize), Euclidean)
+	hnswIndex, err := NewHNSW(int(params.M), int(params.EfConstruction), int(params.VectorSize), MetricType(params.DistanceMetric))
 	if err != nil {
 		return IndexHNSW{}, fmt.Errorf("failed to create HNSW index: %w", err)
 	}
@@ -28,10 +32,40 @@ func NewIndexHNSW(params models.IndexVectorHnswParameters, storage storage.Stora
 		err = fmt.Errorf("failed to create vector store: %w", err)
 		return
 	}
-	return IndexHNSW{
+	inh = IndexHNSW{
 		hnswIndex: hnswIndex,
 		vecStore:  vstore,
-	}, nil
+	}
+	loaded, err := hnswIndex.Load(storage)
+	if err != nil {
+		err = fmt.Errorf("failed to load HNSW graph: %w", err)
+		return
+	}
+	if loaded {
+		return
+	}
+	if err = inh.rebuildGraph(storage); err != nil {
+		err = fmt.Errorf("failed to rebuild HNSW graph: %w", err)
+		return
+	}
+	hnswIndex.UpdateStorage(storage)
+	return
+}
+
+// rebuildGraph re-inserts the plain vectors found in storage into the graph.
+// Storages written before the graph was persisted only hold the vectors, the
+// graph is reconstructed from them in memory, even within a read, and stored
+// with the next write. Every
+// vector store keeps the full precision vector under the 'v' node key.
+func (inf IndexHNSW) rebuildGraph(storage storage.Storage) error {
+	return storage.ForEach(func(k, v []byte) error {
+		id, ok := conversion.NodeIdFromKey(k, 'v')
+		if !ok {
+			return nil
+		}
+		_, err := inf.hnswIndex.AddPoint(conversion.BytesToFloat32(v), id)
+		return err
+	})
 }
 
 func (inf IndexHNSW) SizeInMemory() int64 {
@@ -43,6 +77,20 @@ func (inf IndexHNSW) UpdateStorage(storage storage.Storage) {
 	inf.vecStore.UpdateStorage(storage)
 }
 
+// SetCompactor hands the compactions of the graph to c.
+func (inf IndexHNSW) SetCompactor(c *Compactor) {
+	inf.hnswIndex.SetCompactor(c)
+}
+
+// Compact removes the tombstones of the graph and flushes it to the storage
+// of the current transaction, which must be a write of its own.
+func (inf IndexHNSW) Compact(ctx context.Context) error {
+	if err := inf.hnswIndex.Compact(ctx); err != nil {
+		return fmt.Errorf("failed to compact HNSW index: %w", err)
+	}
+	return inf.hnswIndex.Flush()
+}
+
 func (inf IndexHNSW) InsertUpdateDelete(ctx context.Context, points <-chan models.IndexVectorChange) <-chan error {
 	sinkErrC := withcontext.SinkWithContext(ctx, points, func(point models.IndexVectorChange) error {
 
@@ -78,7 +126,11 @@ func (inf IndexHNSW) InsertUpdateDelete(ctx context.Context, points <-chan model
 			errC <- fmt.Errorf("failed to fit HNSW index: %w", err)
 			return
 		}
-		errC <- inf.hnswIndex.Flush()
+		if err := inf.hnswIndex.Flush(); err != nil {
+			errC <- fmt.Errorf("failed to flush HNSW index: %w", err)
+			return
+		}
+		errC <- inf.vecStore.Flush()
 	}()
 	return errC
 }
@@ -93,21 +145,41 @@ func (inf IndexHNSW) Search(ctx context.Context, options models.SearchVectorFlat
 	}
 
 	startTime := time.Now()
-	results, err := inf.hnswIndex.Search(query, k, filter)
-	if err != nil {
+	budget, cancel := options.Budget(ctx)
+	defer cancel()
+	results, err := inf.hnswIndex.Search(budget, query, k, options.EfSearch, filter)
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	partial := err != nil && errors.Is(err, budget.Err())
+	if err != nil && !partial {
 		return nil, nil, fmt.Errorf("search failed: %w", err)
 	}
-	log.Debug().Dur("elapsed", time.Since(startTime)).Msg("search HNSW")
+	log.Debug().Dur("elapsed", time.Since(startTime)).Bool("partial", partial).Msg("search HNSW")
 
+	// the graph ranks by similarity, results report the distance of the
+	// vector store like the flat index does
+	distFn := inf.vecStore.DistanceFromFloat(query)
 	rSet := roaring64.New()
 	searchResults := make([]models.SearchResult, 0, len(results))
 	for _, res := range results {
+		point, err := inf.vecStore.Get(res.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get vector %d: %w", res.ID, err)
+		}
+		dist := distFn(point)
 		rSet.Add(res.ID)
 		searchResults = append(searchResults, models.SearchResult{
 			NodeId:      res.ID,
-			Distance:    &res.Score,
-			HybridScore: (-1 * weight * res.Score),
+			Distance:    &dist,
+			HybridScore: (-1 * weight * dist),
 		})
 	}
+	slices.SortStableFunc(searchResults, func(a, b models.SearchResult) int {
+		return cmp.Compare(*a.Distance, *b.Distance)
+	})
+	if partial {
+		return rSet, searchResults, models.ErrPartialResults
+	}
 	return rSet, searchResults, nil
 }
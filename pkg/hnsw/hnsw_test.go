@@ -8,6 +8,7 @@ import (
 	"slices"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/RoaringBitmap/roaring/roaring64"
 	"github.com/rs/zerolog"
@@ -141,6 +142,43 @@ func Test_Search(t *testing.T) {
 	require.Equal(t, float32(0), *results[0].Distance)
 }
 
+func Test_SearchInterrupted(t *testing.T) {
+	bucket := storage.NewMemStorage(false)
+	inv, err := hnsw.NewIndexHNSW(hnswParams, bucket)
+	require.NoError(t, err)
+	ctx := context.Background()
+	rps := randPoints(2000, 0)
+	require.NoError(t, <-inv.InsertUpdateDelete(ctx, withcontext.ProduceWithContext(ctx, rps)))
+	// ---------------------------
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	options := models.SearchVectorFlatOptions{Vector: rps[0].Vector, Limit: 10}
+	_, _, err = inv.Search(cancelled, options, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	// A passed deadline answers with the nodes reached until then
+	options.Deadline = time.Now()
+	rSet, results, err := inv.Search(ctx, options, nil)
+	require.ErrorIs(t, err, models.ErrPartialResults)
+	require.Less(t, len(results), 10)
+	require.EqualValues(t, len(results), rSet.GetCardinality())
+	// so does a filter searched one by one
+	_, _, err = inv.Search(ctx, options, roaring64.BitmapOf(rps[0].Id, rps[1].Id))
+	require.ErrorIs(t, err, models.ErrPartialResults)
+	options.Deadline = time.Time{}
+	_, results, err = inv.Search(ctx, options, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 10)
+	// ---------------------------
+	// Writes stop on cancellation
+	in := make(chan models.IndexVectorChange, 1)
+	in <- randPoints(1, 5000)[0]
+	require.ErrorIs(t, <-inv.InsertUpdateDelete(cancelled, in), context.Canceled)
+	checkVectorCount(t, bucket, 2000)
+	_, results, err = inv.Search(ctx, models.SearchVectorFlatOptions{Vector: rps[0].Vector, Limit: 2001}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 2000)
+}
+
 func Test_Recall(t *testing.T) {
 	zerolog.SetGlobalLevel(zerolog.Disabled)
 	distFnNames := []string{models.DistanceEuclidean}
@@ -202,3 +240,35 @@ func Test_Recall(t *testing.T) {
 		})
 	}
 }
+
+func Test_SearchEf(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	bucket := storage.NewMemStorage(false)
+	inv, err := hnsw.NewIndexHNSW(hnswParams, bucket)
+	require.NoError(t, err)
+	ctx := context.Background()
+	rps := randPoints(1000, 0)
+	require.NoError(t, <-inv.InsertUpdateDelete(ctx, withcontext.ProduceWithContext(ctx, rps)))
+	// ef never drops below the limit
+	for _, ef := range []int{1, 10, 200} {
+		options := models.SearchVectorFlatOptions{Vector: rps[0].Vector, Limit: 10, EfSearch: ef}
+		rSet, results, err := inv.Search(ctx, options, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 10, rSet.GetCardinality())
+		require.Len(t, results, 10)
+		require.True(t, slices.IsSortedFunc(results, func(a, b models.SearchResult) int {
+			return cmp.Compare(*a.Distance, *b.Distance)
+		}))
+		require.Equal(t, rps[0].Id, results[0].NodeId)
+		require.Equal(t, float32(0), *results[0].Distance)
+	}
+	// a reopened index loads the stored graph and answers the same
+	options := models.SearchVectorFlatOptions{Vector: rps[1].Vector, Limit: 10}
+	_, want, err := inv.Search(ctx, options, nil)
+	require.NoError(t, err)
+	reopened, err := hnsw.NewIndexHNSW(hnswParams, bucket)
+	require.NoError(t, err)
+	_, got, err := reopened.Search(ctx, options, nil)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
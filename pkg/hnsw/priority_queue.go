@@ -52,3 +52,17 @@ func (pq *PriorityQueue) Pop() interface{} {
 func (pq PriorityQueue) IsEmpty() bool {
 	return len(pq) == 0
 }
+
+// MinPriorityQueue implements a min-heap, its top is the least similar item.
+type MinPriorityQueue struct {
+	PriorityQueue
+}
+
+func (pq MinPriorityQueue) Less(i, j int) bool {
+	return pq.PriorityQueue[i].score < pq.PriorityQueue[j].score
+}
+
+// Worst returns the lowest score in the heap.
+func (pq MinPriorityQueue) Worst() float32 {
+	return pq.PriorityQueue[0].score
+}
@@ -0,0 +1,258 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package hnsw
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ------------------------------
+// Tombstones and Compaction
+// ------------------------------
+
+func (h *HNSW) isTombstone(id uint64) bool {
+	_, deleted := h.tombstones[id]
+	return deleted
+}
+
+// Compactor runs the compactions of a graph in the background, one at a
+// time, until it is stopped. It outlives the graph instances it serves, the
+// owner of the storage runs every compaction in a write transaction of its
+// own, see IndexHNSW.Compact.
+type Compactor struct {
+	due    chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewCompactor starts a compactor that hands the compactions asked for to
+// run. A failed compaction is retried once the next delete asks again.
+func NewCompactor(ctx context.Context, run func(ctx context.Context) error) *Compactor {
+	ctx, cancel := context.WithCancel(ctx)
+	c := &Compactor{
+		due:    make(chan struct{}, 1),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(c.done)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.due:
+				if err := run(ctx); err != nil && ctx.Err() == nil {
+					log.Warn().Err(err).Msg("HNSW compaction failed")
+				}
+			}
+		}
+	}()
+	return c
+}
+
+// notify asks for a compaction without waiting for it, a graph without
+// compactor keeps its tombstones until Compact is called.
+func (c *Compactor) notify() {
+	if c == nil {
+		return
+	}
+	select {
+	case c.due <- struct{}{}:
+	default:
+	}
+}
+
+// Stop cancels the running compaction and waits until the compactor is done.
+func (c *Compactor) Stop() {
+	c.cancel()
+	<-c.done
+}
+
+// SetCompactor hands the compactions of the graph to c.
+func (h *HNSW) SetCompactor(c *Compactor) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.compactor = c
+}
+
+// Compact removes the tombstones from the graph. The nodes that link to one
+// of them pick their neighbors on that level again, see repair. Once ctx is
+// done it stops with ctx.Err() and the tombstones left stay.
+func (h *HNSW) Compact(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.tombstones) == 0 {
+		return nil
+	}
+	if err := h.writable(); err != nil {
+		return err
+	}
+	affected := make(map[uint64]struct{})
+	for id := range h.tombstones {
+		for _, source := range h.incoming[id] {
+			if !h.isTombstone(source) {
+				affected[source] = struct{}{}
+			}
+		}
+	}
+	var i int
+	for id := range affected {
+		if i++; i%checkInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		node := h.Nodes[id]
+		for level, neighbors := range node.Neighbors {
+			if slices.ContainsFunc(neighbors, h.isTombstone) {
+				h.repair(node, level, h.isTombstone)
+			}
+		}
+	}
+	gone := make(map[uint64]*Node, len(h.tombstones))
+	for id := range h.tombstones {
+		gone[id] = h.Nodes[id]
+		h.forget(gone[id])
+	}
+	if entry, removed := gone[h.EntryPointID]; removed {
+		h.EntryPointID = h.nextEntryPoint(entry, gone)
+	}
+	return nil
+}
+
+// unlink takes a node out of the graph right away, the nodes that link to it
+// are repaired before it goes.
+func (h *HNSW) unlink(node *Node) {
+	removed := func(id uint64) bool {
+		return id == node.ID
+	}
+	for _, source := range slices.Clone(h.incoming[node.ID]) {
+		neighbor := h.Nodes[source]
+		for level, neighbors := range neighbor.Neighbors {
+			if slices.Contains(neighbors, node.ID) {
+				h.repair(neighbor, level, removed)
+			}
+		}
+	}
+	h.forget(node)
+	if h.EntryPointID == node.ID {
+		h.EntryPointID = h.nextEntryPoint(node, nil)
+	}
+}
+
+// forget deletes a node nothing links to anymore along with its edges.
+func (h *HNSW) forget(node *Node) {
+	for _, neighbors := range node.Neighbors {
+		for _, id := range neighbors {
+			h.dropSource(id, node.ID)
+		}
+	}
+	delete(h.incoming, node.ID)
+	delete(h.Nodes, node.ID)
+	h.levels[node.Level]--
+	delete(h.tombstones, node.ID)
+	h.touch(node.ID)
+}
+
+// repair selects the neighbors of a node on a level again once some of them
+// are removed. The candidates are the neighbors left and the neighbors of the
+// removed ones, followed through removed nodes as far as they go, so the
+// region behind the removed nodes stays reachable.
+func (h *HNSW) repair(node *Node, level int, removed func(uint64) bool) {
+	seen := map[uint64]bool{node.ID: true}
+	queue := slices.Clone(node.Neighbors[level])
+	var candidates []*Item
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if removed(id) {
+			queue = append(queue, h.neighbors(id, level)...)
+			continue
+		}
+		neighbor, exists := h.Nodes[id]
+		if !exists {
+			continue
+		}
+		candidates = append(candidates, &Item{id: id, score: h.SimilarityFunc(node.Vector, neighbor.Vector)})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	h.setNeighbors(node, level, h.selectNeighbors(candidates, h.maxConnections(level)))
+}
+
+// nextEntryPoint returns a node on the highest level left, the entry point of
+// a graph whose entry point old was removed. The nodes of that level are
+// searched from old through the removed nodes in gone, which are forgotten
+// already. Only a level cut off from old is scanned for. It prefers nodes
+// that are not tombstones.
+func (h *HNSW) nextEntryPoint(old *Node, gone map[uint64]*Node) uint64 {
+	top := len(h.levels) - 1
+	for top >= 0 && h.levels[top] == 0 {
+		top--
+	}
+	if top < 0 {
+		return 0 // 0 indicates no entry point
+	}
+	var tombstone uint64
+	seen := map[uint64]bool{old.ID: true}
+	queue := []*Node{old}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if top >= len(node.Neighbors) {
+			continue
+		}
+		for _, id := range node.Neighbors[top] {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			neighbor, exists := h.Nodes[id]
+			switch {
+			case !exists:
+				if neighbor = gone[id]; neighbor == nil {
+					continue
+				}
+			case !h.isTombstone(id):
+				return id
+			case tombstone == 0:
+				tombstone = id
+			}
+			queue = append(queue, neighbor)
+		}
+	}
+	if tombstone != 0 {
+		return tombstone
+	}
+	for id, node := range h.Nodes {
+		if node.Level == top {
+			return id
+		}
+	}
+	return 0
+}
@@ -0,0 +1,130 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package merkle builds a fixed shape hash tree over point ids. The first byte
+// of an id picks one of 256 leaves, so two trees built on different nodes can
+// be compared level by level and the leaves that differ name the id ranges
+// that have to be synced.
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Depth is the level of the leaves, the root is level 0
+	Depth  = 8
+	Leaves = 1 << Depth
+)
+
+// Builder hashes entries into their leaves. A leaf combines the hashes of
+// its entries with XOR, so entries may be added in any order and two nodes
+// that store the same entries agree on the leaf however they scanned them.
+type Builder struct {
+	leaves [Leaves][sha256.Size]byte
+	counts [Leaves]uint64
+}
+
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Add hashes an id along with the state it is compared by. Ids are added
+// once each.
+func (b *Builder) Add(id uuid.UUID, state []byte) {
+	h := sha256.New()
+	h.Write(id[:])
+	h.Write(state)
+	sum := h.Sum(nil)
+	leaf := &b.leaves[Leaf(id)]
+	for i := range leaf {
+		leaf[i] ^= sum[i]
+	}
+	b.counts[Leaf(id)]++
+}
+
+func (b *Builder) Build() *Tree {
+	t := &Tree{levels: make([][][]byte, Depth+1)}
+	leaves := make([][]byte, Leaves)
+	for i, combined := range b.leaves {
+		// empty leaves hash to nil so they are cheap to tell apart
+		if b.counts[i] > 0 {
+			h := sha256.New()
+			h.Write(combined[:])
+			h.Write(binary.BigEndian.AppendUint64(nil, b.counts[i]))
+			leaves[i] = h.Sum(nil)
+		}
+	}
+	t.levels[Depth] = leaves
+	for level := Depth - 1; level >= 0; level-- {
+		below := t.levels[level+1]
+		nodes := make([][]byte, len(below)/2)
+		for i := range nodes {
+			left, right := below[2*i], below[2*i+1]
+			if left == nil && right == nil {
+				continue
+			}
+			h := sha256.New()
+			h.Write(left)
+			h.Write([]byte{0})
+			h.Write(right)
+			nodes[i] = h.Sum(nil)
+		}
+		t.levels[level] = nodes
+	}
+	return t
+}
+
+// Leaf returns the leaf an id falls into.
+func Leaf(id uuid.UUID) int {
+	return int(id[0])
+}
+
+type Tree struct {
+	levels [][][]byte
+}
+
+func (t *Tree) Root() []byte {
+	return t.levels[0][0]
+}
+
+// Level returns the 1<<level node hashes of a level, nil for empty subtrees.
+func (t *Tree) Level(level int) ([][]byte, error) {
+	if level < 0 || level > Depth {
+		return nil, fmt.Errorf("invalid tree level %d, expected 0 to %d", level, Depth)
+	}
+	return t.levels[level], nil
+}
+
+// Diff returns the leaves that differ from the leaf level of another tree.
+func (t *Tree) Diff(leaves [][]byte) ([]int, error) {
+	if len(leaves) != Leaves {
+		return nil, fmt.Errorf("expected %d leaves, got %d", Leaves, len(leaves))
+	}
+	var diff []int
+	for i, leaf := range t.levels[Depth] {
+		if !bytes.Equal(leaf, leaves[i]) {
+			diff = append(diff, i)
+		}
+	}
+	return diff, nil
+}
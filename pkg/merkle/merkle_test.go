@@ -0,0 +1,84 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package merkle
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func build(ids []uuid.UUID, state func(uuid.UUID) []byte) *Tree {
+	b := NewBuilder()
+	for _, id := range ids {
+		b.Add(id, state(id))
+	}
+	return b.Build()
+}
+
+func TestTreeDiff(t *testing.T) {
+	ids := make([]uuid.UUID, 1000)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	// the two ids below land in leaves 3 and 200
+	changed, missing := ids[0], ids[1]
+	changed[0], missing[0] = 3, 200
+	ids[0], ids[1] = changed, missing
+
+	version := func(uuid.UUID) []byte { return []byte{1} }
+	a, b := build(ids, version), build(ids, version)
+	require.Equal(t, a.Root(), b.Root())
+	leaves, err := b.Level(Depth)
+	require.NoError(t, err)
+	diff, err := a.Diff(leaves)
+	require.NoError(t, err)
+	require.Empty(t, diff)
+	// the order the entries are added in does not matter
+	reversed := slices.Clone(ids)
+	slices.Reverse(reversed)
+	require.Equal(t, a.Root(), build(reversed, version).Root())
+
+	// a newer version and a missing point each show up as their leaf
+	b = build(append([]uuid.UUID{changed}, ids[2:]...), func(id uuid.UUID) []byte {
+		if id == changed {
+			return []byte{2}
+		}
+		return []byte{1}
+	})
+	require.NotEqual(t, a.Root(), b.Root())
+	leaves, err = b.Level(Depth)
+	require.NoError(t, err)
+	diff, err = a.Diff(leaves)
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 200}, diff)
+
+	// the empty tree has no root and differs in every populated leaf
+	empty := NewBuilder().Build()
+	require.Nil(t, empty.Root())
+	leaves, err = a.Level(Depth)
+	require.NoError(t, err)
+	diff, err = empty.Diff(leaves)
+	require.NoError(t, err)
+	require.NotEmpty(t, diff)
+
+	_, err = a.Level(Depth + 1)
+	require.Error(t, err)
+}
@@ -31,6 +31,7 @@ const (
 const (
 	IndexTypeVectorFlat   = "vectorFlat"
 	IndexTypeVectorVamana = "vectorVamana"
+	IndexTypeVectorHnsw   = "vectorHnsw"
 	IndexTypeText         = "text"
 	IndexTypeString       = "string"
 	IndexTypeInteger      = "integer"
@@ -28,6 +28,17 @@ type IndexOptions struct {
 	StringArray *IndexStringArrayParameters `json:"stringArray,omitempty"`
 }
 
+// VectorSize returns the dimension of a vector index, zero for other types.
+func (o IndexOptions) VectorSize() uint {
+	switch {
+	case o.Type == IndexTypeVectorFlat && o.VectorFlat != nil:
+		return o.VectorFlat.VectorSize
+	case o.Type == IndexTypeVectorHnsw && o.VectorHnsw != nil:
+		return o.VectorHnsw.VectorSize
+	}
+	return 0
+}
+
 type IndexVectorFlatParameters struct {
 	VectorSize     uint       `json:"vectorSize" binding:"required,min=1,max=4096"`
 	DistanceMetric string     `json:"distanceMetric" binding:"required,oneof=euclidean cosine dot hamming jaccard haversine"`
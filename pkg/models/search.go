@@ -1,7 +1,11 @@
 package models
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,6 +18,26 @@ type SearchRequest struct {
 	Limit  int          `json:"limit" binding:"required,min=1,max=100"`
 }
 
+// Validate checks the request against the bounds of its binding tags and the
+// query against the index schema it runs on.
+func (r SearchRequest) Validate(schema IndexSchema) error {
+	if r.Limit < 1 || r.Limit > 100 {
+		return fmt.Errorf("invalid limit %d, expected 1 to 100", r.Limit)
+	}
+	if r.Offset < 0 {
+		return fmt.Errorf("invalid offset %d", r.Offset)
+	}
+	if len(r.Sort) > 10 {
+		return fmt.Errorf("too many sort options, expected at most 10 got %d", len(r.Sort))
+	}
+	for _, opt := range r.Sort {
+		if opt.Property == "" {
+			return fmt.Errorf("sort option without property")
+		}
+	}
+	return r.Query.Validate(schema)
+}
+
 // ---------------------------
 
 type Query struct {
@@ -77,12 +101,20 @@ func (q Query) Validate(schema IndexSchema) error {
 	}
 	// Are the options given correctly?
 	switch value.Type {
-	case IndexTypeVectorFlat:
+	case IndexTypeVectorFlat, IndexTypeVectorHnsw:
+		// both vector indexes answer the same near query
 		if q.VectorFlat == nil {
 			return fmt.Errorf("vectorFlat query options not provided for property %s", q.Property)
 		}
-		if len(q.VectorFlat.Vector) != int(value.VectorFlat.VectorSize) {
-			return fmt.Errorf("vectorFlat query vector length mismatch for property %s, expected %d got %d", q.Property, value.VectorFlat.VectorSize, len(q.VectorFlat.Vector))
+		vectorSize := value.VectorSize()
+		if len(q.VectorFlat.Vector) != int(vectorSize) {
+			return fmt.Errorf("vectorFlat query vector length mismatch for property %s, expected %d got %d", q.Property, vectorSize, len(q.VectorFlat.Vector))
+		}
+		if q.VectorFlat.Operator != "near" {
+			return fmt.Errorf("invalid operator %s for %s, expected near", q.VectorFlat.Operator, q.Property)
+		}
+		if q.VectorFlat.Limit < 1 || q.VectorFlat.Limit > 75 {
+			return fmt.Errorf("invalid vectorFlat limit %d for property %s, expected 1 to 75", q.VectorFlat.Limit, q.Property)
 		}
 		if q.VectorFlat.Filter != nil {
 			if err := q.VectorFlat.Filter.Validate(schema); err != nil {
@@ -137,6 +169,24 @@ type SearchResult struct {
 	HybridScore float32 `json:"_hybridScore" msgpack:"_hybridScore"`
 }
 
+// MarshalJSON puts the decoded point data next to the id and the scores, the
+// encoded data stays internal.
+func (r SearchResult) MarshalJSON() ([]byte, error) {
+	out := make(map[string]any, len(r.DecodedData)+4)
+	for k, v := range r.DecodedData {
+		out[k] = v
+	}
+	out["_id"] = r.Id
+	if r.Distance != nil {
+		out["_distance"] = *r.Distance
+	}
+	if r.Score != nil {
+		out["_score"] = *r.Score
+	}
+	out["_hybridScore"] = r.HybridScore
+	return json.Marshal(out)
+}
+
 // ---------------------------
 
 type SortOption struct {
@@ -159,6 +209,26 @@ type SearchVectorFlatOptions struct {
 	Limit    int       `json:"limit" binding:"required,min=1,max=75"`
 	Filter   *Query    `json:"filter"`
 	Weight   *float32  `json:"weight"`
+	// EfSearch is how many candidates an HNSW search keeps, more find closer
+	// points but take longer. Zero picks the index default, the flat index
+	// ignores it
+	EfSearch int `json:"efSearch" binding:"omitempty,min=1,max=1000"`
+	// Deadline ends the search with the points found so far and
+	// ErrPartialResults, the zero time never does
+	Deadline time.Time `json:"-"`
+}
+
+// ErrPartialResults comes with the results of a vector search that reached
+// its deadline, closer points may be missing.
+var ErrPartialResults = errors.New("search deadline reached, results are partial")
+
+// Budget returns the context a vector search runs in, it ends at the
+// deadline of the options.
+func (o SearchVectorFlatOptions) Budget(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.Deadline.IsZero() {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, o.Deadline)
 }
 
 type SearchTextOptions struct {
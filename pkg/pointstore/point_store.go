@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/sjy-dv/nnv/pkg/conversion"
+	"github.com/sjy-dv/nnv/pkg/hlc"
 	"github.com/sjy-dv/nnv/pkg/models"
 	"github.com/sjy-dv/nnv/storage"
 )
@@ -16,7 +17,74 @@ var ErrPointDoesNotExist = errors.New("point does not exist")
 
 type ShardPoint struct {
 	models.Point
-	NodeId uint64
+	NodeId  uint64
+	Version Version
+}
+
+// Version orders the writes of a point. Timestamps decide, the origin node
+// breaks ties so every node picks the same winner.
+type Version struct {
+	Timestamp hlc.Timestamp
+	Origin    string
+}
+
+func (v Version) IsZero() bool {
+	return v.Timestamp == 0 && v.Origin == ""
+}
+
+func (v Version) Newer(than Version) bool {
+	if v.Timestamp != than.Timestamp {
+		return v.Timestamp > than.Timestamp
+	}
+	return v.Origin > than.Origin
+}
+
+func encodeVersion(v Version) []byte {
+	return append(conversion.Uint64ToBytes(uint64(v.Timestamp)), v.Origin...)
+}
+
+func decodeVersion(b []byte) (Version, error) {
+	if len(b) < 8 {
+		return Version{}, errors.New("could not decode point version")
+	}
+	return Version{
+		Timestamp: hlc.Timestamp(conversion.BytesToUint64(b[:8])),
+		Origin:    string(b[8:]),
+	}, nil
+}
+
+// GetVersion returns the version of the last write of a point. Deleted points
+// leave a tombstone, a point never written has the zero version.
+func GetVersion(storage storage.Storage, pointId uuid.UUID) (v Version, deleted bool, err error) {
+	if b := storage.Get(PointKey(pointId, 'v')); b != nil {
+		v, err = decodeVersion(b)
+		return v, false, err
+	}
+	if b := storage.Get(PointKey(pointId, 't')); b != nil {
+		v, err = decodeVersion(b)
+		return v, true, err
+	}
+	return Version{}, false, nil
+}
+
+// SetTombstone records the version of a delete, also for points that were
+// never stored here.
+func SetTombstone(storage storage.Storage, pointId uuid.UUID, v Version) error {
+	if err := storage.Delete(PointKey(pointId, 'v')); err != nil {
+		return fmt.Errorf("could not delete point version: %w", err)
+	}
+	if err := storage.Put(PointKey(pointId, 't'), encodeVersion(v)); err != nil {
+		return fmt.Errorf("could not set tombstone: %w", err)
+	}
+	return nil
+}
+
+// DropTombstone forgets the delete of a point, as if it was never stored here.
+func DropTombstone(storage storage.Storage, pointId uuid.UUID) error {
+	if err := storage.Delete(PointKey(pointId, 't')); err != nil {
+		return fmt.Errorf("could not drop tombstone: %w", err)
+	}
+	return nil
 }
 
 func PointKey(id uuid.UUID, suffix byte) []byte {
@@ -36,6 +104,12 @@ func SetPoint(storage storage.Storage, point ShardPoint) error {
 	if err := storage.Put(PointKey(point.Id, 'i'), conversion.Uint64ToBytes(point.NodeId)); err != nil {
 		return fmt.Errorf("could not set node id: %w", err)
 	}
+	if err := storage.Put(PointKey(point.Id, 'v'), encodeVersion(point.Version)); err != nil {
+		return fmt.Errorf("could not set point version: %w", err)
+	}
+	if err := storage.Delete(PointKey(point.Id, 't')); err != nil {
+		return fmt.Errorf("could not delete tombstone: %w", err)
+	}
 	// ---------------------------
 	// Handle point data
 	if len(point.Data) > 0 {
@@ -70,12 +144,17 @@ func GetPointByUUID(storage storage.Storage, pointId uuid.UUID) (ShardPoint, err
 		return ShardPoint{}, err
 	}
 	data := storage.Get(conversion.NodeKey(nodeId, 'd'))
+	version, _, err := GetVersion(storage, pointId)
+	if err != nil {
+		return ShardPoint{}, err
+	}
 	sp := ShardPoint{
 		Point: models.Point{
 			Id:   pointId,
 			Data: data,
 		},
-		NodeId: nodeId,
+		NodeId:  nodeId,
+		Version: version,
 	}
 	return sp, nil
 }
@@ -93,17 +172,24 @@ func GetPointByNodeId(storage storage.Storage, nodeId uint64, withData bool) (Sh
 	if withData {
 		data = storage.Get(conversion.NodeKey(nodeId, 'd'))
 	}
+	version, _, err := GetVersion(storage, pointId)
+	if err != nil {
+		return ShardPoint{}, err
+	}
 	sp := ShardPoint{
 		Point: models.Point{
 			Id:   pointId,
 			Data: data,
 		},
-		NodeId: nodeId,
+		NodeId:  nodeId,
+		Version: version,
 	}
 	return sp, nil
 }
 
-func DeletePoint(storage storage.Storage, pointId uuid.UUID, nodeId uint64) error {
+// DeletePoint removes the point and leaves a tombstone with the version of the
+// delete.
+func DeletePoint(storage storage.Storage, pointId uuid.UUID, nodeId uint64, version Version) error {
 	if err := storage.Delete(PointKey(pointId, 'i')); err != nil {
 		return fmt.Errorf("could not delete point id: %w", err)
 	}
@@ -113,5 +199,5 @@ func DeletePoint(storage storage.Storage, pointId uuid.UUID, nodeId uint64) erro
 	if err := storage.Delete(conversion.NodeKey(nodeId, 'd')); err != nil {
 		return fmt.Errorf("could not delete point data: %w", err)
 	}
-	return nil
+	return SetTombstone(storage, pointId, version)
 }
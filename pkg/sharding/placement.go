@@ -0,0 +1,158 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sharding
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// Placement decides which shard owns a point. Shards are identified by name
+// (their address), so a placement built for a different shard list keeps most
+// points where they were.
+type Placement interface {
+	// Locate returns the index of the owning shard in the shard list the
+	// placement was built for
+	Locate(id uuid.UUID) int
+}
+
+type PlacementType int
+
+const (
+	// Rendezvous hashing, every shard scores the point and the highest wins
+	Rendezvous PlacementType = iota
+	// consistent hash ring with virtual nodes
+	Ring
+	// ShardTraffic, nearly every point moves when the shard count changes
+	Modulo
+)
+
+// DefaultVirtualNodes spreads each shard over enough points of the ring to
+// keep the load within a few percent of even.
+const DefaultVirtualNodes = 160
+
+func NewPlacement(t PlacementType, shards []string) (Placement, error) {
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("placement needs at least one shard")
+	}
+	switch t {
+	case Rendezvous:
+		return NewRendezvousPlacement(shards), nil
+	case Ring:
+		return NewRingPlacement(shards, DefaultVirtualNodes), nil
+	case Modulo:
+		return moduloPlacement(len(shards)), nil
+	}
+	return nil, fmt.Errorf("unknown placement type %d", t)
+}
+
+// mix is the splitmix64 finalizer, fnv alone leaves the high bits of short
+// keys poorly distributed.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return mix(h.Sum64())
+}
+
+func hashId(id uuid.UUID) uint64 {
+	return mix(binary.LittleEndian.Uint64(id[:8]) ^ mix(binary.LittleEndian.Uint64(id[8:])))
+}
+
+type moduloPlacement int
+
+func (m moduloPlacement) Locate(id uuid.UUID) int {
+	return int(ShardTraffic(id, uint64(m)))
+}
+
+type rendezvousPlacement struct {
+	seeds []uint64
+}
+
+func NewRendezvousPlacement(shards []string) Placement {
+	seeds := make([]uint64, len(shards))
+	for i, shard := range shards {
+		seeds[i] = hashString(shard)
+	}
+	return &rendezvousPlacement{seeds: seeds}
+}
+
+func (r *rendezvousPlacement) Locate(id uuid.UUID) int {
+	h := hashId(id)
+	best, bestScore := 0, uint64(0)
+	for i, seed := range r.seeds {
+		if score := mix(h ^ seed); score > bestScore || i == 0 {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+type ringPlacement struct {
+	points []uint64
+	owners []int
+}
+
+func NewRingPlacement(shards []string, virtualNodes int) Placement {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+	type vnode struct {
+		point uint64
+		owner int
+	}
+	vnodes := make([]vnode, 0, len(shards)*virtualNodes)
+	for i, shard := range shards {
+		for v := 0; v < virtualNodes; v++ {
+			vnodes = append(vnodes, vnode{point: hashString(shard + "#" + strconv.Itoa(v)), owner: i})
+		}
+	}
+	sort.Slice(vnodes, func(i, j int) bool { return vnodes[i].point < vnodes[j].point })
+	r := &ringPlacement{
+		points: make([]uint64, len(vnodes)),
+		owners: make([]int, len(vnodes)),
+	}
+	for i, vn := range vnodes {
+		r.points[i] = vn.point
+		r.owners[i] = vn.owner
+	}
+	return r
+}
+
+// Locate walks clockwise from the point hash to the next virtual node.
+func (r *ringPlacement) Locate(id uuid.UUID) int {
+	h := hashId(id)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[i]
+}
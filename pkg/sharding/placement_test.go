@@ -0,0 +1,81 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sharding
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// moved counts the points whose owning shard name differs between two shard
+// lists.
+func moved(t *testing.T, pt PlacementType, ids []uuid.UUID, before, after []string) float64 {
+	t.Helper()
+	from, err := NewPlacement(pt, before)
+	require.NoError(t, err)
+	to, err := NewPlacement(pt, after)
+	require.NoError(t, err)
+	count := 0
+	for _, id := range ids {
+		if before[from.Locate(id)] != after[to.Locate(id)] {
+			count++
+		}
+	}
+	return float64(count) / float64(len(ids))
+}
+
+func TestPlacementMovement(t *testing.T) {
+	ids := make([]uuid.UUID, 20000)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	three := []string{"node-0:50051", "node-1:50051", "node-2:50051"}
+	four := append(append([]string{}, three...), "node-3:50051")
+	withoutMiddle := []string{"node-0:50051", "node-2:50051"}
+
+	for _, tc := range []struct {
+		name string
+		pt   PlacementType
+	}{{"rendezvous", Rendezvous}, {"ring", Ring}} {
+		t.Run(tc.name, func(t *testing.T) {
+			// ideally a quarter moves to the new shard and a third off the
+			// removed one
+			added := moved(t, tc.pt, ids, three, four)
+			removed := moved(t, tc.pt, ids, three, withoutMiddle)
+			t.Logf("moved on add %.3f, on remove %.3f", added, removed)
+			require.InDelta(t, 0.25, added, 0.05)
+			require.InDelta(t, 1.0/3, removed, 0.05)
+
+			p, err := NewPlacement(tc.pt, four)
+			require.NoError(t, err)
+			load := make([]int, len(four))
+			for _, id := range ids {
+				load[p.Locate(id)]++
+			}
+			for _, l := range load {
+				require.InDelta(t, len(ids)/len(four), l, float64(len(ids))*0.05)
+			}
+		})
+	}
+
+	added := moved(t, Modulo, ids, three, four)
+	t.Logf("modulo moved on add %.3f", added)
+	require.Greater(t, added, 0.5)
+}
@@ -0,0 +1,149 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package tenant scopes collection names to tenants. A call names its tenant
+// in the x-nnv-tenant metadata, with authentication on callers act in the
+// tenant they are bound to and only operators name others. Calls without a
+// tenant use the default one.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/auth"
+	"github.com/sjy-dv/nnv/pkg/errcode"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// Default is the tenant of calls that name none
+	Default = ""
+	// All lists the collections of every tenant, for operators and resharding
+	All = "*"
+	// MetadataKey carries the tenant of a call, the HTTP API reads the
+	// header of the same name
+	MetadataKey = auth.TenantKey
+)
+
+var ErrInvalidTenant = errors.New("invalid tenant")
+
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+func Validate(name string) error {
+	if name == Default || name == All || namePattern.MatchString(name) {
+		return nil
+	}
+	return fmt.Errorf("%w name %q, expected %s", ErrInvalidTenant, name, namePattern)
+}
+
+type tenantKey struct{}
+
+func NewContext(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, name)
+}
+
+// FromContext returns the tenant of a call, Default when it names none.
+func FromContext(ctx context.Context) string {
+	name, _ := ctx.Value(tenantKey{}).(string)
+	return name
+}
+
+// Resolve returns the tenant a call that requested one acts in. With
+// authentication on, callers act in the tenant they are bound to or the
+// default one, only operators pick any tenant, see auth.Identity.
+func Resolve(ctx context.Context, requested string) (string, error) {
+	if err := Validate(requested); err != nil {
+		return "", err
+	}
+	id, ok := auth.FromContext(ctx)
+	if !ok {
+		return requested, nil
+	}
+	return id.ResolveTenant(requested)
+}
+
+// ServerOptions put the tenant of every call into its context. They go after
+// the options of authentication, which bind callers to their tenant.
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			ctx, err := incoming(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx, err := incoming(ss.Context())
+			if err != nil {
+				return err
+			}
+			return handler(srv, &tenantStream{ServerStream: ss, ctx: ctx})
+		}),
+	}
+}
+
+type tenantStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tenantStream) Context() context.Context {
+	return s.ctx
+}
+
+func incoming(ctx context.Context) (context.Context, error) {
+	var requested string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(MetadataKey); len(v) > 0 {
+		requested = v[0]
+	}
+	name, err := Resolve(ctx, requested)
+	if err != nil {
+		code := pb.ErrorCode_INVALID_ARGUMENT
+		if errors.Is(err, auth.ErrPermissionDenied) {
+			code = pb.ErrorCode_PERMISSION_DENIED
+		}
+		return nil, errcode.Error(code, err.Error(), nil)
+	}
+	return NewContext(ctx, name), nil
+}
+
+// Forward names the tenant of the context in the calls made with it.
+func Forward() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(outgoing(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(outgoing(ctx), desc, cc, method, opts...)
+		}),
+	}
+}
+
+func outgoing(ctx context.Context) context.Context {
+	name := FromContext(ctx)
+	if name == Default {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, MetadataKey, name)
+}
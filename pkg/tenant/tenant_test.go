@@ -0,0 +1,71 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sjy-dv/nnv/pkg/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+	for _, name := range []string{Default, All, "team-a"} {
+		got, err := Resolve(ctx, name)
+		require.NoError(t, err)
+		require.Equal(t, name, got)
+	}
+	_, err := Resolve(ctx, "../etc")
+	require.ErrorIs(t, err, ErrInvalidTenant)
+
+	// bound callers stay in their tenant
+	config := &auth.Config{
+		Authenticators: []auth.Authenticator{auth.APIKeys{"k1": "alice", "k2": "ops", "k3": "bob"}},
+		Tenants:        map[string]string{"alice": "team-a"},
+		Operators:      []string{"ops"},
+	}
+	alice, err := config.Authenticate(auth.Credentials{APIKey: "k1"})
+	require.NoError(t, err)
+	ctx = auth.NewContext(context.Background(), alice)
+	for _, name := range []string{Default, "team-a"} {
+		got, err := Resolve(ctx, name)
+		require.NoError(t, err)
+		require.Equal(t, "team-a", got)
+	}
+	for _, name := range []string{"team-b", All} {
+		_, err = Resolve(ctx, name)
+		require.ErrorIs(t, err, auth.ErrPermissionDenied)
+	}
+
+	// unbound callers stay in the default tenant, operators go anywhere
+	bob, err := config.Authenticate(auth.Credentials{APIKey: "k3"})
+	require.NoError(t, err)
+	for _, name := range []string{"team-b", All} {
+		_, err = Resolve(auth.NewContext(context.Background(), bob), name)
+		require.ErrorIs(t, err, auth.ErrPermissionDenied)
+	}
+	ops, err := config.Authenticate(auth.Credentials{APIKey: "k2"})
+	require.NoError(t, err)
+	for _, name := range []string{"team-b", All} {
+		got, err := Resolve(auth.NewContext(context.Background(), ops), name)
+		require.NoError(t, err)
+		require.Equal(t, name, got)
+	}
+}
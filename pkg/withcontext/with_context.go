@@ -183,6 +183,7 @@ func MergeErrorsWithContext(ctx context.Context, cs ...<-chan error) <-chan erro
 	go func() {
 		wg.Wait()
 		errC <- context.Cause(ctx)
+		cancel(nil)
 		close(errC)
 	}()
 	return errC
@@ -202,6 +203,12 @@ func SinkWithContext[T any](ctx context.Context, in <-chan T, sinkFn func(T) err
 					errC <- nil
 					return
 				}
+				// select picks at random when both are ready, a cancelled
+				// sink must not take further items
+				if err := ctx.Err(); err != nil {
+					errC <- err
+					return
+				}
 				if err := sinkFn(b); err != nil {
 					errC <- err
 					return
@@ -0,0 +1,175 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package replication shares the writes of every node through a JetStream
+// stream. Each node publishes its own mutations and replays those of its peers
+// with a durable consumer, conflicts are left to the node applying them.
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/rs/zerolog/log"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	DefaultStream = "NNV_MUTATIONS"
+	// subjects are <prefix>.<collection>
+	subjectPrefix = "nnv.mutations"
+	// a failed mutation is retried after this delay
+	retryDelay = time.Second
+	// the node that published a mutation, which is not always its origin
+	publisherHeader = "Nnv-Node"
+)
+
+var nodeIdPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+type Config struct {
+	// NATS server url, several may be given comma separated
+	URL    string
+	Stream string
+	// mutations older than this are dropped from the stream, zero keeps
+	// them forever
+	MaxAge time.Duration
+}
+
+// ApplyFunc applies a mutation of a peer. An error makes the stream deliver
+// the mutation again later.
+type ApplyFunc func(ctx context.Context, m *pb.Mutation) error
+
+type Replicator struct {
+	nodeId  string
+	nc      *nats.Conn
+	js      jetstream.JetStream
+	stream  jetstream.Stream
+	consume jetstream.ConsumeContext
+	cancel  context.CancelFunc
+}
+
+// Connect ensures the stream exists. nodeId names the durable consumer of the
+// node and tags the mutations it publishes, it has to be unique and stable
+// across restarts.
+func Connect(ctx context.Context, config Config, nodeId string) (*Replicator, error) {
+	if !nodeIdPattern.MatchString(nodeId) {
+		return nil, fmt.Errorf("invalid node id %q, expected %s", nodeId, nodeIdPattern)
+	}
+	if config.Stream == "" {
+		config.Stream = DefaultStream
+	}
+	nc, err := nats.Connect(config.URL, nats.Name("nnv-"+nodeId), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats %s: %w", config.URL, err)
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+	stream, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     config.Stream,
+		Subjects: []string{subjectPrefix + ".>"},
+		Storage:  jetstream.FileStorage,
+		MaxAge:   config.MaxAge,
+	})
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to create stream %s: %w", config.Stream, err)
+	}
+	return &Replicator{nodeId: nodeId, nc: nc, js: js, stream: stream}, nil
+}
+
+func (r *Replicator) NodeId() string {
+	return r.nodeId
+}
+
+// Publish returns once the stream has stored the mutation.
+func (r *Replicator) Publish(ctx context.Context, m *pb.Mutation) error {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	msg := nats.NewMsg(subjectPrefix + "." + m.GetCollectionName())
+	msg.Data = data
+	msg.Header.Set(publisherHeader, r.nodeId)
+	if _, err := r.js.PublishMsg(ctx, msg); err != nil {
+		return fmt.Errorf("failed to publish mutation: %w", err)
+	}
+	return nil
+}
+
+// Subscribe starts replaying the mutations of the peers. A node that is new to
+// the stream starts from its first message, a known one where it left off.
+func (r *Replicator) Subscribe(ctx context.Context, apply ApplyFunc) error {
+	if r.consume != nil {
+		return errors.New("already subscribed")
+	}
+	consumer, err := r.stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:       r.nodeId,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		DeliverPolicy: jetstream.DeliverAllPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create consumer %s: %w", r.nodeId, err)
+	}
+	applyCtx, cancel := context.WithCancel(context.Background())
+	consume, err := consumer.Consume(func(msg jetstream.Msg) {
+		r.handle(applyCtx, msg, apply)
+	}, jetstream.ConsumeErrHandler(func(_ jetstream.ConsumeContext, err error) {
+		log.Warn().Err(err).Msg("replication consumer")
+	}))
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to consume stream: %w", err)
+	}
+	r.consume, r.cancel = consume, cancel
+	return nil
+}
+
+func (r *Replicator) handle(ctx context.Context, msg jetstream.Msg, apply ApplyFunc) {
+	m := &pb.Mutation{}
+	if err := proto.Unmarshal(msg.Data(), m); err != nil {
+		log.Error().Err(err).Str("subject", msg.Subject()).Msg("dropping undecodable mutation")
+		msg.Term()
+		return
+	}
+	if msg.Headers().Get(publisherHeader) == r.nodeId {
+		msg.Ack()
+		return
+	}
+	if err := apply(ctx, m); err != nil {
+		log.Warn().Err(err).Str("origin", m.GetOrigin()).Str("id", m.GetId()).Msg("failed to apply mutation, retrying")
+		msg.NakWithDelay(retryDelay)
+		return
+	}
+	msg.Ack()
+}
+
+func (r *Replicator) Close() {
+	if r.consume != nil {
+		r.consume.Stop()
+		r.cancel()
+	}
+	r.nc.Close()
+}
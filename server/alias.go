@@ -0,0 +1,209 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/tenant"
+)
+
+var (
+	ErrAliasNotFound     = errors.New("alias not found")
+	ErrAliasExists       = errors.New("alias already exists")
+	ErrCollectionAliased = errors.New("collection has aliases")
+)
+
+// the aliases of a tenant are kept next to its collections
+const aliasesFile = "aliases.json"
+
+func (s *Server) loadAliases(name string) error {
+	b, err := os.ReadFile(filepath.Join(s.tenantDir(name), aliasesFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read aliases: %w", err)
+	}
+	aliases := make(map[string]string)
+	if err := json.Unmarshal(b, &aliases); err != nil {
+		return fmt.Errorf("failed to decode aliases of tenant %q: %w", name, err)
+	}
+	s.aliases[name] = aliases
+	return nil
+}
+
+// lookup finds a collection by its name or one of its aliases, it runs with
+// s.mu held.
+func (s *Server) lookup(t, name string) (*collection, bool) {
+	if col, ok := s.collections[collectionKey{tenant: t, name: name}]; ok {
+		return col, true
+	}
+	target, ok := s.aliases[t][name]
+	if !ok {
+		return nil, false
+	}
+	col, ok := s.collections[collectionKey{tenant: t, name: target}]
+	return col, ok
+}
+
+// changeAliases hands change a copy of the aliases of the tenant of ctx and
+// keeps the copy once it is stored. Requests resolve their collection under
+// s.mu, every request sees all aliases before or after the change.
+func (s *Server) changeAliases(ctx context.Context, change func(t string, aliases map[string]string) error) error {
+	t, err := singleTenant(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	aliases := maps.Clone(s.aliases[t])
+	if aliases == nil {
+		aliases = make(map[string]string)
+	}
+	if err := change(t, aliases); err != nil {
+		return err
+	}
+	dir := s.tenantDir(t)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create tenant directory %s: %w", dir, err)
+	}
+	b, err := json.Marshal(aliases)
+	if err != nil {
+		return err
+	}
+	// renaming replaces the file at once, a crash leaves either version
+	path := filepath.Join(dir, aliasesFile)
+	if err := os.WriteFile(path+".tmp", b, 0644); err != nil {
+		return fmt.Errorf("failed to store aliases: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to store aliases: %w", err)
+	}
+	s.aliases[t] = aliases
+	return nil
+}
+
+// aliasTarget checks that an alias can point to name, aliases of aliases are
+// not resolved.
+func (s *Server) aliasTarget(t, name string) error {
+	if _, ok := s.collections[collectionKey{tenant: t, name: name}]; !ok {
+		return fmt.Errorf("%w: %s", ErrCollectionNotFound, name)
+	}
+	return nil
+}
+
+func (s *Server) createAlias(ctx context.Context, alias, target string) error {
+	if !collectionNamePattern.MatchString(alias) {
+		return fmt.Errorf("%w: alias name %q, expected %s", ErrInvalidRequest, alias, collectionNamePattern)
+	}
+	return s.changeAliases(ctx, func(t string, aliases map[string]string) error {
+		if _, ok := aliases[alias]; ok {
+			return fmt.Errorf("%w: %s", ErrAliasExists, alias)
+		}
+		if _, ok := s.collections[collectionKey{tenant: t, name: alias}]; ok {
+			return fmt.Errorf("%w: %s is a collection", ErrCollectionExists, alias)
+		}
+		if err := s.aliasTarget(t, target); err != nil {
+			return err
+		}
+		aliases[alias] = target
+		return nil
+	})
+}
+
+func (s *Server) pointAlias(ctx context.Context, alias, target string) error {
+	return s.changeAliases(ctx, func(t string, aliases map[string]string) error {
+		if _, ok := aliases[alias]; !ok {
+			return fmt.Errorf("%w: %s", ErrAliasNotFound, alias)
+		}
+		if err := s.aliasTarget(t, target); err != nil {
+			return err
+		}
+		aliases[alias] = target
+		return nil
+	})
+}
+
+func (s *Server) swapAliases(ctx context.Context, alias, other string) error {
+	return s.changeAliases(ctx, func(t string, aliases map[string]string) error {
+		for _, name := range []string{alias, other} {
+			if _, ok := aliases[name]; !ok {
+				return fmt.Errorf("%w: %s", ErrAliasNotFound, name)
+			}
+		}
+		aliases[alias], aliases[other] = aliases[other], aliases[alias]
+		return nil
+	})
+}
+
+func (s *Server) deleteAlias(ctx context.Context, alias string) error {
+	return s.changeAliases(ctx, func(t string, aliases map[string]string) error {
+		if _, ok := aliases[alias]; !ok {
+			return fmt.Errorf("%w: %s", ErrAliasNotFound, alias)
+		}
+		delete(aliases, alias)
+		return nil
+	})
+}
+
+// listAliases returns the aliases of the tenant of ctx, those of every tenant
+// for tenant.All.
+func (s *Server) listAliases(ctx context.Context) []*pb.Alias {
+	name := tenant.FromContext(ctx)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var list []*pb.Alias
+	for t, aliases := range s.aliases {
+		if name != tenant.All && t != name {
+			continue
+		}
+		for alias, target := range aliases {
+			list = append(list, &pb.Alias{Tenant: t, Alias: alias, CollectionName: target})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].GetTenant() != list[j].GetTenant() {
+			return list[i].GetTenant() < list[j].GetTenant()
+		}
+		return list[i].GetAlias() < list[j].GetAlias()
+	})
+	return list
+}
+
+// aliasesOf names the aliases pointing to a collection, it runs with s.mu
+// held.
+func (s *Server) aliasesOf(key collectionKey) string {
+	var names []string
+	for alias, target := range s.aliases[key.tenant] {
+		if target == key.name {
+			names = append(names, alias)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
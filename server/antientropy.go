@@ -0,0 +1,363 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/cache"
+	"github.com/sjy-dv/nnv/pkg/conversion"
+	"github.com/sjy-dv/nnv/pkg/hlc"
+	"github.com/sjy-dv/nnv/pkg/merkle"
+	"github.com/sjy-dv/nnv/pkg/pointstore"
+	"github.com/sjy-dv/nnv/pkg/tenant"
+	"github.com/sjy-dv/nnv/storage"
+	"google.golang.org/grpc"
+)
+
+// peer is another replica of the same data, anti-entropy pushes the points
+// this node has newer versions of to it.
+type peer struct {
+	addr   string
+	conn   *grpc.ClientConn
+	client pb.LBCoordinatorClient
+}
+
+func dialPeers(addrs []string, opts ...grpc.DialOption) ([]*peer, error) {
+	peers := make([]*peer, 0, len(addrs))
+	for _, addr := range addrs {
+		conn, err := grpc.NewClient(addr, opts...)
+		if err != nil {
+			closePeers(peers)
+			return nil, fmt.Errorf("failed to dial peer %s: %w", addr, err)
+		}
+		peers = append(peers, &peer{addr: addr, conn: conn, client: pb.NewLBCoordinatorClient(conn)})
+	}
+	return peers, nil
+}
+
+func closePeers(peers []*peer) {
+	for _, p := range peers {
+		p.conn.Close()
+	}
+}
+
+// merkleTree hashes the id and version of every point and tombstone, a
+// tombstone never hashes like a live point of the same version. Points
+// written before versions existed are hashed with an empty version.
+// Tombstones past the horizon are left out, replicas agree whether they have
+// purged them yet or not.
+func (c *collection) merkleTree() (*merkle.Tree, error) {
+	b := merkle.NewBuilder()
+	err := c.db.Read(func(sc storage.StorageCoordinator) error {
+		points, err := sc.Get(pointsStorage)
+		if err != nil {
+			return err
+		}
+		return points.PrefixScan([]byte{'p'}, func(k, v []byte) error {
+			if len(k) != 18 {
+				return nil
+			}
+			id, err := uuid.FromBytes(k[1:17])
+			if err != nil {
+				return err
+			}
+			switch k[17] {
+			case 'i':
+				b.Add(id, append([]byte{'v'}, points.Get(pointstore.PointKey(id, 'v'))...))
+			case 't':
+				version, _, err := pointstore.GetVersion(points, id)
+				if err != nil {
+					return err
+				}
+				if !c.expired(version) {
+					b.Add(id, append([]byte{'t'}, v...))
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b.Build(), nil
+}
+
+// rangeEntries returns the points and the tombstones within the horizon of the
+// given leaves.
+func (c *collection) rangeEntries(leaves []int) ([]*pb.Row, []*pb.DeleteDataset, error) {
+	var rows []*pb.Row
+	var tombstones []*pb.DeleteDataset
+	err := c.db.Read(func(sc storage.StorageCoordinator) error {
+		points, err := sc.Get(pointsStorage)
+		if err != nil {
+			return err
+		}
+		vectors, err := sc.Get(vectorsStorage)
+		if err != nil {
+			return err
+		}
+		for _, leaf := range leaves {
+			err := points.PrefixScan([]byte{'p', byte(leaf)}, func(k, v []byte) error {
+				if len(k) != 18 {
+					return nil
+				}
+				id, err := uuid.FromBytes(k[1:17])
+				if err != nil {
+					return err
+				}
+				switch k[17] {
+				case 'i':
+					row, err := c.row(points, vectors, conversion.BytesToUint64(v), allFields)
+					if err != nil {
+						return err
+					}
+					rows = append(rows, row)
+				case 't':
+					version, _, err := pointstore.GetVersion(points, id)
+					if err != nil {
+						return err
+					}
+					if c.expired(version) {
+						return nil
+					}
+					tombstones = append(tombstones, &pb.DeleteDataset{
+						Id:             id.String(),
+						CollectionName: c.name,
+						Version:        uint64(version.Timestamp),
+						Origin:         version.Origin,
+						Internal:       true,
+					})
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return rows, tombstones, err
+}
+
+// checkDivergence compares the merkle tree of each collection with the one of
+// every peer. With repair the leaves that differ are pushed to the peer, which
+// keeps whatever version is newer. Pushing only goes one way, the peer
+// repairs this node when it runs its own check.
+func (s *Server) checkDivergence(ctx context.Context, collectionName string, repair bool) ([]*pb.Divergence, error) {
+	cols := s.listCollections(ctx)
+	if collectionName != "" {
+		col, err := s.getCollection(ctx, collectionName)
+		if err != nil {
+			return nil, err
+		}
+		cols = []*collection{col}
+	}
+	var divergences []*pb.Divergence
+	for _, col := range cols {
+		tree, err := col.merkleTree()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build merkle tree of %s: %w", col.name, err)
+		}
+		for _, p := range s.peers {
+			d := &pb.Divergence{Peer: p.addr, Tenant: col.tenant, CollectionName: col.name}
+			if err := s.compare(tenant.NewContext(ctx, col.tenant), col, tree, p, repair, d); err != nil {
+				d.ErrorMessage = err.Error()
+			}
+			divergences = append(divergences, d)
+		}
+	}
+	return divergences, nil
+}
+
+func (s *Server) compare(ctx context.Context, col *collection, tree *merkle.Tree, p *peer, repair bool, d *pb.Divergence) error {
+	root, err := p.client.GetMerkleTree(ctx, &pb.MerkleReq{CollectionName: col.name, Level: 0})
+	if err != nil {
+		return err
+	}
+	if len(root.GetHashes()) == 1 && bytes.Equal(root.GetHashes()[0], tree.Root()) {
+		return nil
+	}
+	leaves, err := p.client.GetMerkleTree(ctx, &pb.MerkleReq{CollectionName: col.name, Level: merkle.Depth})
+	if err != nil {
+		return err
+	}
+	diff, err := tree.Diff(leaves.GetHashes())
+	if err != nil {
+		return err
+	}
+	d.Ranges = uint32(len(diff))
+	if !repair || len(diff) == 0 {
+		return nil
+	}
+	d.Repaired, err = s.push(ctx, col, p, diff)
+	return err
+}
+
+// push streams the points of the leaves through the DataLoader of the peer and
+// replays the tombstones as internal deletes, which the peer keeps even for
+// points it never had. Both carry their versions so the peer ignores what it
+// has newer.
+func (s *Server) push(ctx context.Context, col *collection, p *peer, leaves []int) (uint64, error) {
+	rows, tombstones, err := col.rangeEntries(leaves)
+	if err != nil {
+		return 0, err
+	}
+	var pushed uint64
+	if len(rows) > 0 {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		loader, err := p.client.DataLoader(ctx)
+		if err != nil {
+			return 0, err
+		}
+		defer loader.CloseSend()
+		for _, row := range rows {
+			err := loader.Send(&pb.ModifyDataset{
+				Id:             row.GetId(),
+				CollectionName: col.name,
+				Vector:         row.GetVector(),
+				Metadata:       row.GetMetadata(),
+				Version:        row.GetVersion(),
+				Origin:         row.GetOrigin(),
+				Internal:       true,
+			})
+			if err != nil {
+				return pushed, err
+			}
+			resp, err := loader.Recv()
+			if err != nil {
+				return pushed, err
+			}
+			if !resp.GetResult() {
+				return pushed, errors.New(resp.GetErrorMessage())
+			}
+			pushed++
+		}
+	}
+	for _, tombstone := range tombstones {
+		if _, err := p.client.Delete(ctx, tombstone); err != nil {
+			return pushed, err
+		}
+		pushed++
+	}
+	return pushed, nil
+}
+
+// antiEntropy repairs the peers every interval until the server closes.
+func (s *Server) antiEntropy(interval time.Duration) {
+	defer s.background.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+		}
+		ctx, cancel := context.WithTimeout(tenant.NewContext(context.Background(), tenant.All), interval)
+		divergences, err := s.checkDivergence(ctx, "", true)
+		cancel()
+		if err != nil {
+			log.Error().Err(err).Msg("anti-entropy failed")
+			continue
+		}
+		for _, d := range divergences {
+			if d.GetErrorMessage() != "" {
+				log.Warn().Str("peer", d.GetPeer()).Str("tenant", d.GetTenant()).Str("collection", d.GetCollectionName()).Str("error", d.GetErrorMessage()).Msg("anti-entropy")
+			} else if d.GetRanges() > 0 {
+				log.Info().Str("peer", d.GetPeer()).Str("tenant", d.GetTenant()).Str("collection", d.GetCollectionName()).Uint32("ranges", d.GetRanges()).Uint64("repaired", d.GetRepaired()).Msg("replica repaired")
+			}
+		}
+	}
+}
+
+// expired reports whether a tombstone of the version is past the horizon.
+func (c *collection) expired(v pointstore.Version) bool {
+	return c.horizon > 0 && v.Timestamp < hlc.New(time.Now().Add(-c.horizon), 0)
+}
+
+// purge drops the tombstones past the horizon and returns how many.
+func (c *collection) purge() (int, error) {
+	purged := 0
+	err := c.write(func(sc storage.StorageCoordinator, txn *cache.Transaction) error {
+		points, err := sc.Get(pointsStorage)
+		if err != nil {
+			return err
+		}
+		var expired []uuid.UUID
+		err = points.PrefixScan([]byte{'p'}, func(k, v []byte) error {
+			if len(k) != 18 || k[17] != 't' {
+				return nil
+			}
+			id, err := uuid.FromBytes(k[1:17])
+			if err != nil {
+				return err
+			}
+			version, _, err := pointstore.GetVersion(points, id)
+			if err != nil {
+				return err
+			}
+			if c.expired(version) {
+				expired = append(expired, id)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, id := range expired {
+			if err := pointstore.DropTombstone(points, id); err != nil {
+				return err
+			}
+		}
+		purged = len(expired)
+		return nil
+	})
+	return purged, err
+}
+
+// purgeTombstones drops the tombstones past the horizon a few times per
+// horizon until the server closes.
+func (s *Server) purgeTombstones(horizon time.Duration) {
+	defer s.background.Done()
+	ticker := time.NewTicker(max(horizon/4, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+		}
+		for _, col := range s.tenantCollections(tenant.All) {
+			purged, err := col.purge()
+			if err != nil {
+				log.Error().Err(err).Str("tenant", col.tenant).Str("collection", col.name).Msg("failed to purge tombstones")
+			} else if purged > 0 {
+				log.Debug().Str("tenant", col.tenant).Str("collection", col.name).Int("purged", purged).Msg("tombstones purged")
+			}
+		}
+	}
+}
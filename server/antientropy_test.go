@@ -0,0 +1,189 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/server"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startPeers runs nodes that know each other as anti-entropy peers and purge
+// their tombstones after the horizon.
+func startPeers(t *testing.T, count int, horizon time.Duration) []pb.LBCoordinatorClient {
+	t.Helper()
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	addrs := make([]string, count)
+	listeners := make(map[string]*bufconn.Listener, count)
+	for i := range addrs {
+		addrs[i] = fmt.Sprintf("passthrough:///node-%d", i)
+		listeners[fmt.Sprintf("node-%d", i)] = bufconn.Listen(1 << 20)
+	}
+	dialOpts := []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return listeners[addr].DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	clients := make([]pb.LBCoordinatorClient, count)
+	for i := range clients {
+		node, err := server.New(server.Config{
+			DataDir:          t.TempDir(),
+			Stable:           true,
+			NodeId:           fmt.Sprintf("node-%d", i),
+			Peers:            slices.Delete(slices.Clone(addrs), i, i+1),
+			TombstoneHorizon: horizon,
+		}, dialOpts...)
+		require.NoError(t, err)
+		go node.Serve(listeners[fmt.Sprintf("node-%d", i)])
+		conn, err := grpc.NewClient(addrs[i], dialOpts...)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			conn.Close()
+			require.NoError(t, node.Close())
+		})
+		clients[i] = pb.NewLBCoordinatorClient(conn)
+		resp, err := clients[i].CreateCollection(context.Background(), &pb.Collection{CollectionName: "docs", Dimension: 2})
+		require.NoError(t, err)
+		require.True(t, resp.GetResponse().GetResult(), resp.GetResponse().GetErrorMessage())
+	}
+	return clients
+}
+
+func checkDivergence(t *testing.T, client pb.LBCoordinatorClient, repair bool) (ranges uint32, repaired uint64) {
+	t.Helper()
+	report, err := client.CheckDivergence(context.Background(), &pb.DivergenceReq{Repair: repair})
+	require.NoError(t, err)
+	require.True(t, report.GetResult(), report.GetErrorMessage())
+	for _, d := range report.GetDivergences() {
+		require.Empty(t, d.GetErrorMessage())
+		ranges += d.GetRanges()
+		repaired += d.GetRepaired()
+	}
+	return ranges, repaired
+}
+
+func TestAntiEntropy(t *testing.T) {
+	nodes := startPeers(t, 3, 0)
+	ctx := context.Background()
+	ids := make([]string, 50)
+	// the same points with the same versions everywhere
+	for _, node := range nodes {
+		loader, err := node.DataLoader(ctx)
+		require.NoError(t, err)
+		for i := range ids {
+			if ids[i] == "" {
+				ids[i] = uuid.NewString()
+			}
+			require.NoError(t, loader.Send(&pb.ModifyDataset{
+				Id:             ids[i],
+				CollectionName: "docs",
+				Vector:         []float32{float32(i), 0},
+				Version:        uint64(i + 1),
+				Origin:         "loader",
+			}))
+			resp, err := loader.Recv()
+			require.NoError(t, err)
+			require.True(t, resp.GetResult(), resp.GetErrorMessage())
+		}
+		require.NoError(t, loader.CloseSend())
+	}
+	for _, node := range nodes {
+		ranges, _ := checkDivergence(t, node, false)
+		require.Zero(t, ranges)
+	}
+
+	// each replica misses a different write
+	missing := uuid.NewString()
+	resp, err := nodes[0].Insert(ctx, &pb.ModifyDataset{Id: missing, CollectionName: "docs", Vector: []float32{7, 7}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	resp, err = nodes[1].Update(ctx, &pb.ModifyDataset{Id: ids[0], CollectionName: "docs", Vector: []float32{8, 8}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	resp, err = nodes[2].Delete(ctx, &pb.DeleteDataset{Id: ids[1], CollectionName: "docs"})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	// the tombstone reaches the peers that never had the point
+	gone := uuid.NewString()
+	resp, err = nodes[0].Insert(ctx, &pb.ModifyDataset{Id: gone, CollectionName: "docs", Vector: []float32{9, 9}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	resp, err = nodes[0].Delete(ctx, &pb.DeleteDataset{Id: gone, CollectionName: "docs"})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+
+	ranges, _ := checkDivergence(t, nodes[0], false)
+	require.NotZero(t, ranges)
+	for _, node := range nodes {
+		_, repaired := checkDivergence(t, node, true)
+		require.NotZero(t, repaired)
+	}
+	for _, node := range nodes {
+		ranges, _ := checkDivergence(t, node, false)
+		require.Zero(t, ranges)
+		require.Equal(t, []float32{7, 7}, fetch(t, node, missing).GetVector())
+		require.Equal(t, []float32{8, 8}, fetch(t, node, ids[0]).GetVector())
+		require.Nil(t, fetch(t, node, ids[1]))
+		require.Nil(t, fetch(t, node, gone))
+		col, err := node.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+		require.NoError(t, err)
+		require.EqualValues(t, len(ids), col.GetCollectionSize())
+	}
+}
+
+func TestTombstoneHorizon(t *testing.T) {
+	nodes := startPeers(t, 2, time.Second)
+	ctx := context.Background()
+
+	// a plain delete of a point never stored leaves no tombstone
+	_, err := nodes[1].Delete(ctx, &pb.DeleteDataset{Id: uuid.NewString(), CollectionName: "docs"})
+	requireCode(t, err, pb.ErrorCode_NOT_FOUND)
+	ranges, _ := checkDivergence(t, nodes[1], false)
+	require.Zero(t, ranges)
+
+	id := uuid.NewString()
+	resp, err := nodes[0].Insert(ctx, &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{1, 1}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	resp, err = nodes[0].Delete(ctx, &pb.DeleteDataset{Id: id, CollectionName: "docs"})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	ranges, _ = checkDivergence(t, nodes[0], false)
+	require.NotZero(t, ranges)
+
+	// past the horizon the tombstone no longer counts and is not pushed
+	require.Eventually(t, func() bool {
+		ranges, _ := checkDivergence(t, nodes[0], false)
+		return ranges == 0
+	}, 5*time.Second, 100*time.Millisecond)
+	_, repaired := checkDivergence(t, nodes[0], true)
+	require.Zero(t, repaired)
+}
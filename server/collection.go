@@ -0,0 +1,438 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RoaringBitmap/roaring/roaring64"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/cache"
+	"github.com/sjy-dv/nnv/pkg/conversion"
+	"github.com/sjy-dv/nnv/pkg/flat"
+	"github.com/sjy-dv/nnv/pkg/hnsw"
+	"github.com/sjy-dv/nnv/pkg/models"
+	"github.com/sjy-dv/nnv/pkg/pointstore"
+	"github.com/sjy-dv/nnv/storage"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	collectionStorage = "collection"
+	pointsStorage     = pointstore.POINTS_STORAGE_NAME
+	vectorsStorage    = "vectors"
+	// property under which the vector index appears in the schema
+	vectorProperty = "vector"
+	// the HNSW graph compares with euclidean similarity, the flat index
+	// follows so both report comparable distances
+	distanceMetric = models.DistanceEuclidean
+	maxDimension   = 4096
+	hnswM          = 16
+	hnswEfConstr   = 100
+)
+
+var (
+	configKey     = []byte("config")
+	nextNodeIdKey = []byte("nextNodeId")
+	pointCountKey = []byte("pointCount")
+	// vector and metadata bytes of the points, what point byte quotas count
+	pointBytesKey = []byte("pointBytes")
+)
+
+type vectorIndex interface {
+	cache.Cachable
+	UpdateStorage(storage storage.Storage)
+	InsertUpdateDelete(ctx context.Context, points <-chan models.IndexVectorChange) <-chan error
+	Search(ctx context.Context, options models.SearchVectorFlatOptions, filter *roaring64.Bitmap) (*roaring64.Bitmap, []models.SearchResult, error)
+}
+
+type collection struct {
+	name string
+	// the name is unique within the tenant
+	tenant       string
+	path         string
+	config       *pb.Collection
+	schema       models.IndexSchema
+	db           storage.StorageLayer
+	cacheManager *cache.Manager
+	// a write holds the cached indexes until its storage transaction has
+	// committed, and bbolt cannot commit while a read transaction waits on
+	// those indexes. Reads therefore never overlap a write.
+	txMu sync.RWMutex
+	// stamps the versions of local writes, shared by the collections of a
+	// server
+	clock *clock
+	// usage of the tenant, shared by its collections
+	usage *usage
+	// what the running write added to usage, given back if it fails.
+	// Guarded by txMu.
+	pending struct{ points, bytes int64 }
+	// local writes are published through the outbox
+	replicated bool
+	// age after which tombstones are purged, zero keeps them
+	horizon time.Duration
+	// removes the tombstones of an HNSW index, nil for flat indexes
+	compactor *hnsw.Compactor
+}
+
+// parseSchema builds the index schema of a collection. Inverted index entries
+// are either a bare field name, indexed as a string, or "field:type" where
+// type is one of string, integer, float or stringArray.
+func parseSchema(config *pb.Collection) (models.IndexSchema, error) {
+	if config.GetDimension() == 0 || config.GetDimension() > maxDimension {
+		return nil, fmt.Errorf("invalid dimension %d, expected 1 to %d", config.GetDimension(), maxDimension)
+	}
+	schema := make(models.IndexSchema)
+	switch config.GetVectorIndex() {
+	case pb.VectorIndex_FLAT_INDEX:
+		schema[vectorProperty] = models.IndexOptions{
+			Type: models.IndexTypeVectorFlat,
+			VectorFlat: &models.IndexVectorFlatParameters{
+				VectorSize:     uint(config.GetDimension()),
+				DistanceMetric: distanceMetric,
+			},
+		}
+	case pb.VectorIndex_HNSW_INDEX:
+		schema[vectorProperty] = models.IndexOptions{
+			Type: models.IndexTypeVectorHnsw,
+			VectorHnsw: &models.IndexVectorHnswParameters{
+				VectorSize:     uint(config.GetDimension()),
+				DistanceMetric: distanceMetric,
+				M:              hnswM,
+				EfConstruction: hnswEfConstr,
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unknown vector index %v", config.GetVectorIndex())
+	}
+	for _, entry := range config.GetInvertedIndex() {
+		field, indexType, found := strings.Cut(entry, ":")
+		if !found {
+			indexType = models.IndexTypeString
+		}
+		switch field {
+		case "", vectorProperty, "_id", "_and", "_or":
+			return nil, fmt.Errorf("invalid inverted index field %q", field)
+		}
+		if _, ok := schema[field]; ok {
+			return nil, fmt.Errorf("duplicate inverted index field %s", field)
+		}
+		switch indexType {
+		case models.IndexTypeString, models.IndexTypeInteger, models.IndexTypeFloat, models.IndexTypeStringArray:
+		default:
+			return nil, fmt.Errorf("unsupported inverted index type %s for field %s", indexType, field)
+		}
+		schema[field] = models.IndexOptions{Type: indexType}
+	}
+	return schema, nil
+}
+
+func isVectorIndexType(indexType string) bool {
+	return indexType == models.IndexTypeVectorFlat || indexType == models.IndexTypeVectorHnsw
+}
+
+func invertedStorageName(field string) string {
+	return "index/" + field
+}
+
+func createCollection(path string, stable bool, cacheManager *cache.Manager, config *pb.Collection) (*collection, error) {
+	schema, err := parseSchema(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCollection, err)
+	}
+	config = proto.Clone(config).(*pb.Collection)
+	config.CollectionSize = 0
+	config.DiskSize = 0
+	config.CreateTimestamp = time.Now().UTC().Format(time.RFC3339)
+	configBytes, err := proto.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal collection config: %w", err)
+	}
+	db, err := storage.Open(path, stable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open collection storage: %w", err)
+	}
+	err = db.Write(func(sc storage.StorageCoordinator) error {
+		meta, err := sc.Get(collectionStorage)
+		if err != nil {
+			return err
+		}
+		return meta.Put(configKey, configBytes)
+	})
+	if err == nil {
+		err = db.Flush()
+	}
+	if err != nil {
+		db.Close()
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to store collection config: %w", err)
+	}
+	return newCollection(path, config, schema, db, cacheManager), nil
+}
+
+func newCollection(path string, config *pb.Collection, schema models.IndexSchema, db storage.StorageLayer, cacheManager *cache.Manager) *collection {
+	c := &collection{
+		name:         config.GetCollectionName(),
+		path:         path,
+		config:       config,
+		schema:       schema,
+		db:           db,
+		cacheManager: cacheManager,
+	}
+	if schema[vectorProperty].Type == models.IndexTypeVectorHnsw {
+		c.compactor = hnsw.NewCompactor(context.Background(), c.compact)
+	}
+	return c
+}
+
+func openCollection(path string, stable bool, cacheManager *cache.Manager) (*collection, error) {
+	db, err := storage.Open(path, stable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open collection storage: %w", err)
+	}
+	config := &pb.Collection{}
+	err = db.Read(func(sc storage.StorageCoordinator) error {
+		meta, err := sc.Get(collectionStorage)
+		if err != nil {
+			return err
+		}
+		configBytes := meta.Get(configKey)
+		if configBytes == nil {
+			return errors.New("missing collection config")
+		}
+		return proto.Unmarshal(configBytes, config)
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	schema, err := parseSchema(config)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return newCollection(path, config, schema, db, cacheManager), nil
+}
+
+func (c *collection) vectorCacheName() string {
+	return c.path + "/" + vectorsStorage
+}
+
+func (c *collection) newVectorIndex(vectors storage.Storage) (vectorIndex, error) {
+	opts := c.schema[vectorProperty]
+	switch opts.Type {
+	case models.IndexTypeVectorFlat:
+		return flat.NewIndexFlat(*opts.VectorFlat, vectors)
+	case models.IndexTypeVectorHnsw:
+		index, err := hnsw.NewIndexHNSW(*opts.VectorHnsw, vectors)
+		if err != nil {
+			return nil, err
+		}
+		index.SetCompactor(c.compactor)
+		return index, nil
+	}
+	return nil, fmt.Errorf("unknown vector index type %s", opts.Type)
+}
+
+// withVectorIndex hands the shared vector index of the collection, bound to
+// the storage of the current transaction, to f. The storage of the index is
+// swapped for every transaction so even searches take it exclusively.
+func (c *collection) withVectorIndex(txn *cache.Transaction, sc storage.StorageCoordinator, f func(vectorIndex) error) error {
+	vectors, err := sc.Get(vectorsStorage)
+	if err != nil {
+		return err
+	}
+	return txn.With(c.vectorCacheName(), false, func() (cache.Cachable, error) {
+		return c.newVectorIndex(vectors)
+	}, func(cached cache.Cachable) error {
+		index := cached.(vectorIndex)
+		index.UpdateStorage(vectors)
+		return f(index)
+	})
+}
+
+// compact removes the tombstones of the HNSW index in a write of its own, so
+// the graph is flushed along with nothing else.
+func (c *collection) compact(ctx context.Context) error {
+	return c.write(func(sc storage.StorageCoordinator, txn *cache.Transaction) error {
+		return c.withVectorIndex(txn, sc, func(vi vectorIndex) error {
+			return vi.(hnsw.IndexHNSW).Compact(ctx)
+		})
+	})
+}
+
+func (c *collection) read(f func(sc storage.StorageCoordinator, txn *cache.Transaction) error) error {
+	c.txMu.RLock()
+	defer c.txMu.RUnlock()
+	return c.readLocked(f)
+}
+
+// readLocked is read for callers that already hold txMu.
+func (c *collection) readLocked(f func(sc storage.StorageCoordinator, txn *cache.Transaction) error) error {
+	txn := c.cacheManager.NewTransaction()
+	err := c.db.Read(func(sc storage.StorageCoordinator) error {
+		return f(sc, txn)
+	})
+	txn.Commit(err != nil)
+	return err
+}
+
+func (c *collection) write(f func(sc storage.StorageCoordinator, txn *cache.Transaction) error) error {
+	c.txMu.Lock()
+	defer c.txMu.Unlock()
+	c.pending.points, c.pending.bytes = 0, 0
+	txn := c.cacheManager.NewTransaction()
+	err := c.db.Write(func(sc storage.StorageCoordinator) error {
+		return f(sc, txn)
+	})
+	txn.Commit(err != nil)
+	if err != nil && c.usage != nil {
+		c.usage.add(-c.pending.points, -c.pending.bytes, true)
+	}
+	return err
+}
+
+// account changes the point and byte counters of the collection within a
+// write and reserves the change in the usage of the tenant. Exempt writes
+// copy points that are stored elsewhere and are not held to the quota.
+func (c *collection) account(meta storage.Storage, points, bytes int64, exempt bool) error {
+	if points == 0 && bytes == 0 {
+		return nil
+	}
+	if c.usage != nil {
+		if err := c.usage.add(points, bytes, exempt); err != nil {
+			return err
+		}
+		c.pending.points += points
+		c.pending.bytes += bytes
+	}
+	if err := setCounter(meta, pointCountKey, uint64(int64(getCounter(meta, pointCountKey))+points)); err != nil {
+		return err
+	}
+	return setCounter(meta, pointBytesKey, uint64(int64(getCounter(meta, pointBytesKey))+bytes))
+}
+
+// pointBytes is what a point with data takes in Quota.PointBytes.
+func (c *collection) pointBytes(data []byte) int64 {
+	return int64(len(data)) + 4*int64(c.config.GetDimension())
+}
+
+// size reads the point and byte counters of the collection. Collections
+// written before bytes were counted get them counted now.
+func (c *collection) size() (points, bytes int64, err error) {
+	counted := true
+	err = c.db.Read(func(sc storage.StorageCoordinator) error {
+		meta, err := sc.Get(collectionStorage)
+		if err != nil {
+			return err
+		}
+		points, bytes = int64(getCounter(meta, pointCountKey)), int64(getCounter(meta, pointBytesKey))
+		if meta.Get(pointBytesKey) != nil || points == 0 {
+			return nil
+		}
+		store, err := sc.Get(pointsStorage)
+		if err != nil {
+			return err
+		}
+		counted, bytes = false, points*c.pointBytes(nil)
+		return store.ForEach(func(k, v []byte) error {
+			if _, ok := conversion.NodeIdFromKey(k, 'd'); ok {
+				bytes += int64(len(v))
+			}
+			return nil
+		})
+	})
+	if err != nil || counted {
+		return points, bytes, err
+	}
+	err = c.db.Write(func(sc storage.StorageCoordinator) error {
+		meta, err := sc.Get(collectionStorage)
+		if err != nil {
+			return err
+		}
+		return setCounter(meta, pointBytesKey, uint64(bytes))
+	})
+	return points, bytes, err
+}
+
+func getCounter(meta storage.Storage, key []byte) uint64 {
+	v := meta.Get(key)
+	if v == nil {
+		return 0
+	}
+	return conversion.BytesToUint64(v)
+}
+
+func setCounter(meta storage.Storage, key []byte, value uint64) error {
+	return meta.Put(key, conversion.Uint64ToBytes(value))
+}
+
+func (c *collection) pointCount() (uint64, error) {
+	var count uint64
+	err := c.db.Read(func(sc storage.StorageCoordinator) error {
+		meta, err := sc.Get(collectionStorage)
+		if err != nil {
+			return err
+		}
+		count = getCounter(meta, pointCountKey)
+		return nil
+	})
+	return count, err
+}
+
+// info reports the stored config along with the current size of the
+// collection.
+func (c *collection) info() (*pb.Collection, error) {
+	info := proto.Clone(c.config).(*pb.Collection)
+	count, err := c.pointCount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count points: %w", err)
+	}
+	info.CollectionSize = count
+	size, err := c.db.SizeInBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disk size: %w", err)
+	}
+	info.DiskSize = uint64(size)
+	info.Tenant = c.tenant
+	return info, nil
+}
+
+func (c *collection) close() error {
+	if c.compactor != nil {
+		c.compactor.Stop()
+	}
+	c.cacheManager.Release(c.vectorCacheName())
+	return c.db.Close()
+}
+
+func (c *collection) drop() error {
+	if err := c.close(); err != nil {
+		return err
+	}
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove collection storage: %w", err)
+	}
+	return nil
+}
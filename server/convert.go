@@ -0,0 +1,216 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
+// anyToValue unpacks a well known protobuf type into the plain Go value that
+// is stored in the msgpack point document.
+func anyToValue(a *anypb.Any) (any, error) {
+	msg, err := a.UnmarshalNew()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+	}
+	switch v := msg.(type) {
+	case *wrapperspb.StringValue:
+		return v.GetValue(), nil
+	case *wrapperspb.BoolValue:
+		return v.GetValue(), nil
+	case *wrapperspb.Int32Value:
+		return int64(v.GetValue()), nil
+	case *wrapperspb.Int64Value:
+		return v.GetValue(), nil
+	case *wrapperspb.UInt32Value:
+		return int64(v.GetValue()), nil
+	case *wrapperspb.UInt64Value:
+		if v.GetValue() > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: uint64 value %d overflows int64", ErrInvalidMetadata, v.GetValue())
+		}
+		return int64(v.GetValue()), nil
+	case *wrapperspb.FloatValue:
+		return float64(v.GetValue()), nil
+	case *wrapperspb.DoubleValue:
+		return v.GetValue(), nil
+	case *wrapperspb.BytesValue:
+		return v.GetValue(), nil
+	case *structpb.Value:
+		return v.AsInterface(), nil
+	case *structpb.ListValue:
+		return v.AsSlice(), nil
+	case *structpb.Struct:
+		return v.AsMap(), nil
+	}
+	return nil, fmt.Errorf("%w: unsupported type %s", ErrInvalidMetadata, a.GetTypeUrl())
+}
+
+// valueToAny is the reverse of anyToValue for values decoded from a point
+// document.
+func valueToAny(v any) (*anypb.Any, error) {
+	var msg proto.Message
+	switch v := v.(type) {
+	case string:
+		msg = wrapperspb.String(v)
+	case bool:
+		msg = wrapperspb.Bool(v)
+	case int64:
+		msg = wrapperspb.Int64(v)
+	case uint64:
+		msg = wrapperspb.UInt64(v)
+	case float32:
+		msg = wrapperspb.Float(v)
+	case float64:
+		msg = wrapperspb.Double(v)
+	case []byte:
+		msg = wrapperspb.Bytes(v)
+	default:
+		sv, err := structpb.NewValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+		}
+		msg = sv
+	}
+	return anypb.New(msg)
+}
+
+func metadataToDocument(metadata map[string]*anypb.Any) (map[string]any, error) {
+	doc := make(map[string]any, len(metadata))
+	for k, a := range metadata {
+		v, err := anyToValue(a)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", k, err)
+		}
+		doc[k] = v
+	}
+	return doc, nil
+}
+
+func documentToMetadata(doc map[string]any) (map[string]*anypb.Any, error) {
+	metadata := make(map[string]*anypb.Any, len(doc))
+	for k, v := range doc {
+		a, err := valueToAny(v)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", k, err)
+		}
+		metadata[k] = a
+	}
+	return metadata, nil
+}
+
+func encodeDocument(doc map[string]any) ([]byte, error) {
+	if len(doc) == 0 {
+		return nil, nil
+	}
+	data, err := msgpack.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+	}
+	return data, nil
+}
+
+func decodeDocument(data []byte) (map[string]any, error) {
+	doc := make(map[string]any)
+	if len(data) == 0 {
+		return doc, nil
+	}
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	// Integers come back as int64 and floats as float64 regardless of how
+	// compact msgpack stored them, which is what the inverted indexes expect.
+	dec.UseLooseInterfaceDecoding(true)
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode point data: %w", err)
+	}
+	return doc, nil
+}
+
+func asString(v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("expected string got %T", v)
+	}
+	return s, nil
+}
+
+func asInteger(v any) (int64, error) {
+	switch v := v.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, fmt.Errorf("integer %d overflows int64", v)
+		}
+		return int64(v), nil
+	case float64:
+		// JSON numbers arrive as floats through structpb
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("expected integer got %v", v)
+		}
+		return int64(v), nil
+	}
+	return 0, fmt.Errorf("expected integer got %T", v)
+}
+
+func asFloat(v any) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	}
+	return 0, fmt.Errorf("expected float got %T", v)
+}
+
+func asStringArray(v any) ([]string, error) {
+	switch v := v.(type) {
+	case []string:
+		return v, nil
+	case []any:
+		arr := make([]string, len(v))
+		for i, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected string array element got %T", e)
+			}
+			arr[i] = s
+		}
+		return arr, nil
+	}
+	return nil, fmt.Errorf("expected string array got %T", v)
+}
@@ -0,0 +1,79 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/auth"
+	"github.com/sjy-dv/nnv/pkg/cache"
+	"github.com/sjy-dv/nnv/pkg/errcode"
+	"github.com/sjy-dv/nnv/pkg/pointstore"
+	"github.com/sjy-dv/nnv/pkg/tenant"
+	"github.com/sjy-dv/nnv/storage"
+)
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// errorCodes classify the errors of the node, the first one an error wraps
+// decides.
+var errorCodes = []struct {
+	err  error
+	code pb.ErrorCode
+}{
+	{ErrPreconditionFailed, pb.ErrorCode_PRECONDITION_FAILED},
+	{ErrCollectionNotFound, pb.ErrorCode_NOT_FOUND},
+	{ErrAliasNotFound, pb.ErrorCode_NOT_FOUND},
+	{pointstore.ErrPointDoesNotExist, pb.ErrorCode_NOT_FOUND},
+	{cache.ErrNotFound, pb.ErrorCode_NOT_FOUND},
+	{ErrCollectionExists, pb.ErrorCode_ALREADY_EXISTS},
+	{ErrAliasExists, pb.ErrorCode_ALREADY_EXISTS},
+	{ErrCollectionAliased, pb.ErrorCode_PRECONDITION_FAILED},
+	{ErrPointExists, pb.ErrorCode_ALREADY_EXISTS},
+	{ErrDimensionMismatch, pb.ErrorCode_DIMENSION_MISMATCH},
+	{ErrInvalidFilter, pb.ErrorCode_INVALID_FILTER},
+	{ErrInvalidQuery, pb.ErrorCode_INVALID_FILTER},
+	{ErrInvalidMetadata, pb.ErrorCode_MARSHAL_ERROR},
+	{ErrInvalidPointId, pb.ErrorCode_INVALID_ARGUMENT},
+	{ErrInvalidCollection, pb.ErrorCode_INVALID_ARGUMENT},
+	{ErrInvalidRequest, pb.ErrorCode_INVALID_ARGUMENT},
+	{tenant.ErrInvalidTenant, pb.ErrorCode_INVALID_ARGUMENT},
+	{storage.ErrReadOnly, pb.ErrorCode_READ_ONLY},
+	{ErrQuotaExceeded, pb.ErrorCode_QUOTA_EXCEEDED},
+	{auth.ErrUnauthenticated, pb.ErrorCode_UNAUTHENTICATED},
+	{auth.ErrPermissionDenied, pb.ErrorCode_PERMISSION_DENIED},
+	{context.Canceled, pb.ErrorCode_CANCELLED},
+	{context.DeadlineExceeded, pb.ErrorCode_DEADLINE_EXCEEDED},
+}
+
+func errorCode(err error) pb.ErrorCode {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return pb.ErrorCode_UNDEFINED
+}
+
+// statusError is the gRPC status of a failed RPC, its ErrorInfo detail carries
+// the error code.
+func statusError(err error) error {
+	return errcode.Error(errorCode(err), err.Error(), nil)
+}
@@ -0,0 +1,421 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/auth"
+	"github.com/sjy-dv/nnv/pkg/models"
+	"github.com/sjy-dv/nnv/pkg/pointstore"
+	"github.com/sjy-dv/nnv/pkg/tenant"
+)
+
+var ErrInvalidRequest = errors.New("invalid request")
+
+// largest request body the JSON API reads
+const maxBodySize = 32 << 20
+
+// collectionJSON is a collection in the JSON API, the index is "flat" or
+// "hnsw" and inverted index entries are written as for the gRPC API.
+type collectionJSON struct {
+	Name          string             `json:"name"`
+	Dimension     uint64             `json:"dimension"`
+	InvertedIndex []string           `json:"invertedIndex,omitempty"`
+	VectorIndex   string             `json:"vectorIndex"`
+	Size          uint64             `json:"size"`
+	DiskSize      uint64             `json:"diskSize"`
+	CreatedAt     string             `json:"createdAt,omitempty"`
+	Schema        models.IndexSchema `json:"schema,omitempty"`
+}
+
+type pointsJSON struct {
+	Points []models.PointAsMap `json:"points"`
+}
+
+type searchJSON struct {
+	Results []models.SearchResult `json:"results"`
+	Latency string                `json:"latency"`
+}
+
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+var vectorIndexNames = map[pb.VectorIndex]string{
+	pb.VectorIndex_FLAT_INDEX: "flat",
+	pb.VectorIndex_HNSW_INDEX: "hnsw",
+}
+
+// httpAPI serves collections, points and search as JSON. Writes go through
+// the same path as the gRPC API, so they are versioned and replicated alike.
+type httpAPI struct {
+	rpc  *rpcServer
+	auth *auth.Config
+}
+
+// HTTPHandler returns the JSON API of the node:
+//
+//	GET    /v1/collections
+//	POST   /v1/collections
+//	GET    /v1/collections/{name}
+//	DELETE /v1/collections/{name}
+//	PUT    /v1/collections/{name}/points
+//	GET    /v1/collections/{name}/points/{id}
+//	DELETE /v1/collections/{name}/points/{id}
+//	POST   /v1/collections/{name}/search
+//
+// With authentication on, callers send their API key as
+// "Authorization: Bearer <key>" or a client certificate. Collections are
+// those of the tenant in the X-Nnv-Tenant header.
+func (s *Server) HTTPHandler() http.Handler {
+	api := &httpAPI{rpc: &rpcServer{server: s}, auth: s.config.Auth}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/collections", api.guard(auth.None, api.listCollections))
+	// the name is in the body, createCollection authorizes it
+	mux.HandleFunc("POST /v1/collections", api.guard(auth.None, api.createCollection))
+	mux.HandleFunc("GET /v1/collections/{name}", api.guard(auth.Read, api.getCollection))
+	mux.HandleFunc("DELETE /v1/collections/{name}", api.guard(auth.Admin, api.dropCollection))
+	mux.HandleFunc("PUT /v1/collections/{name}/points", api.guard(auth.Write, api.upsertPoints))
+	mux.HandleFunc("GET /v1/collections/{name}/points/{id}", api.guard(auth.Read, api.getPoint))
+	mux.HandleFunc("DELETE /v1/collections/{name}/points/{id}", api.guard(auth.Write, api.deletePoint))
+	mux.HandleFunc("POST /v1/collections/{name}/search", api.guard(auth.Read, api.search))
+	return mux
+}
+
+// guard authenticates the caller and authorizes need on the collection of the
+// path. The identity and the tenant, named by the X-Nnv-Tenant header, are in
+// the context of the request handed to h.
+func (a *httpAPI) guard(need auth.Permission, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if a.auth != nil {
+			creds := auth.Credentials{APIKey: auth.BearerToken(r.Header.Get("Authorization"))}
+			if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+				creds.Certificates = r.TLS.VerifiedChains[0]
+			}
+			id, err := a.auth.Authenticate(creds)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			ctx = auth.NewContext(ctx, id)
+		}
+		name, err := tenant.Resolve(ctx, r.Header.Get(tenant.MetadataKey))
+		if id, ok := auth.FromContext(ctx); ok && err == nil {
+			err = id.Authorize(name, r.PathValue("name"), need)
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		h(w, r.WithContext(tenant.NewContext(ctx, name)))
+	}
+}
+
+// ServeJSON serves the JSON API on lis until the server closes.
+func (s *Server) ServeJSON(lis net.Listener) error {
+	if s.config.TLS != nil {
+		lis = tls.NewListener(lis, s.config.TLS)
+	}
+	s.httpServer = &http.Server{Handler: s.HTTPHandler()}
+	log.Info().Str("addr", lis.Addr().String()).Msg("nnv node http listening")
+	if err := s.httpServer.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) ListenAndServeJSON() error {
+	addr := net.JoinHostPort(s.config.Host, s.config.HTTPPort)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return s.ServeJSON(lis)
+}
+
+func httpStatus(err error) int {
+	switch errorCode(err) {
+	case pb.ErrorCode_NOT_FOUND:
+		return http.StatusNotFound
+	case pb.ErrorCode_ALREADY_EXISTS:
+		return http.StatusConflict
+	case pb.ErrorCode_INVALID_ARGUMENT, pb.ErrorCode_MARSHAL_ERROR,
+		pb.ErrorCode_DIMENSION_MISMATCH, pb.ErrorCode_INVALID_FILTER:
+		return http.StatusBadRequest
+	case pb.ErrorCode_PRECONDITION_FAILED:
+		return http.StatusPreconditionFailed
+	case pb.ErrorCode_READ_ONLY:
+		return http.StatusForbidden
+	case pb.ErrorCode_QUOTA_EXCEEDED:
+		return http.StatusTooManyRequests
+	case pb.ErrorCode_UNAUTHENTICATED:
+		return http.StatusUnauthorized
+	case pb.ErrorCode_PERMISSION_DENIED:
+		return http.StatusForbidden
+	case pb.ErrorCode_DEADLINE_EXCEEDED:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Warn().Err(err).Msg("failed to write http response")
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, httpStatus(err), errorJSON{Error: err.Error()})
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	return nil
+}
+
+func toCollectionJSON(col *collection) (collectionJSON, error) {
+	info, err := col.info()
+	if err != nil {
+		return collectionJSON{}, err
+	}
+	return collectionJSON{
+		Name:          info.GetCollectionName(),
+		Dimension:     info.GetDimension(),
+		InvertedIndex: info.GetInvertedIndex(),
+		VectorIndex:   vectorIndexNames[info.GetVectorIndex()],
+		Size:          info.GetCollectionSize(),
+		DiskSize:      info.GetDiskSize(),
+		CreatedAt:     info.GetCreateTimestamp(),
+	}, nil
+}
+
+func (a *httpAPI) listCollections(w http.ResponseWriter, r *http.Request) {
+	cols := make([]collectionJSON, 0)
+	for _, col := range a.rpc.server.listCollections(r.Context()) {
+		if !auth.Allowed(r.Context(), col.tenant, col.name, auth.Read) {
+			continue
+		}
+		c, err := toCollectionJSON(col)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		cols = append(cols, c)
+	}
+	writeJSON(w, http.StatusOK, map[string][]collectionJSON{"collections": cols})
+}
+
+func (a *httpAPI) createCollection(w http.ResponseWriter, r *http.Request) {
+	var req collectionJSON
+	if err := readJSON(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	if id, ok := auth.FromContext(r.Context()); ok {
+		if err := id.Authorize(tenant.FromContext(r.Context()), req.Name, auth.Admin); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	config := &pb.Collection{
+		CollectionName: req.Name,
+		Dimension:      req.Dimension,
+		InvertedIndex:  req.InvertedIndex,
+	}
+	switch req.VectorIndex {
+	case "", "flat":
+		config.VectorIndex = pb.VectorIndex_FLAT_INDEX
+	case "hnsw":
+		config.VectorIndex = pb.VectorIndex_HNSW_INDEX
+	default:
+		writeError(w, fmt.Errorf("%w: unknown vector index %q, expected flat or hnsw", ErrInvalidCollection, req.VectorIndex))
+		return
+	}
+	col, err := a.rpc.server.createCollection(r.Context(), config)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	log.Info().Str("collection", col.name).Msg("collection created")
+	c, err := toCollectionJSON(col)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	c.Schema = col.schema
+	writeJSON(w, http.StatusCreated, c)
+}
+
+func (a *httpAPI) getCollection(w http.ResponseWriter, r *http.Request) {
+	col, err := a.rpc.server.getCollection(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	c, err := toCollectionJSON(col)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	c.Schema = col.schema
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (a *httpAPI) dropCollection(w http.ResponseWriter, r *http.Request) {
+	if err := a.rpc.server.dropCollection(r.Context(), r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	log.Info().Str("collection", r.PathValue("name")).Msg("collection dropped")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// upsertPoints writes every point of the body in order and stops at the first
+// that fails. A point is its vector under "vector", its metadata in the other
+// fields and an optional "_id", points without one get a new id.
+func (a *httpAPI) upsertPoints(w http.ResponseWriter, r *http.Request) {
+	var req pointsJSON
+	if err := readJSON(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	ids := make([]string, 0, len(req.Points))
+	for i, point := range req.Points {
+		dataset, err := pointToDataset(r.PathValue("name"), point)
+		if err == nil {
+			err = a.rpc.write(r.Context(), dataset, writeUpsert)
+		}
+		if err != nil {
+			writeError(w, fmt.Errorf("point %d: %w", i, err))
+			return
+		}
+		ids = append(ids, dataset.GetId())
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"ids": ids})
+}
+
+func pointToDataset(collectionName string, point models.PointAsMap) (*pb.ModifyDataset, error) {
+	id, err := point.ExtractIdField(true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPointId, err)
+	}
+	values, ok := point[vectorProperty].([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing or invalid %s", ErrInvalidRequest, vectorProperty)
+	}
+	delete(point, vectorProperty)
+	vector := make([]float32, len(values))
+	for i, v := range values {
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s element %d is not a number", ErrInvalidRequest, vectorProperty, i)
+		}
+		vector[i] = float32(f)
+	}
+	metadata, err := documentToMetadata(point)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ModifyDataset{
+		Id:             id.String(),
+		CollectionName: collectionName,
+		Vector:         vector,
+		Metadata:       metadata,
+	}, nil
+}
+
+func (a *httpAPI) getPoint(w http.ResponseWriter, r *http.Request) {
+	col, err := a.rpc.server.getCollection(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	id, err := parsePointId(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	rows, err := col.getPoints([]uuid.UUID{id}, allFields)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(rows) == 0 {
+		writeError(w, fmt.Errorf("%w: %s", pointstore.ErrPointDoesNotExist, id))
+		return
+	}
+	point, err := metadataToDocument(rows[0].GetMetadata())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	point["_id"] = rows[0].GetId()
+	point[vectorProperty] = rows[0].GetVector()
+	writeJSON(w, http.StatusOK, point)
+}
+
+func (a *httpAPI) deletePoint(w http.ResponseWriter, r *http.Request) {
+	err := a.rpc.remove(r.Context(), &pb.DeleteDataset{
+		Id:             r.PathValue("id"),
+		CollectionName: r.PathValue("name"),
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// search runs a models.SearchRequest, the results carry _distance for vector
+// matches, _score for index matches and the _hybridScore they are ranked by.
+func (a *httpAPI) search(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	col, err := a.rpc.server.getCollection(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var req models.SearchRequest
+	if err := readJSON(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	results, err := col.query(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, searchJSON{Results: results, Latency: time.Since(startTime).String()})
+}
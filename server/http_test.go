@@ -0,0 +1,233 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sjy-dv/nnv/pkg/auth"
+	"github.com/sjy-dv/nnv/server"
+	"github.com/stretchr/testify/require"
+)
+
+type httpClient struct {
+	t   *testing.T
+	url string
+	// sent as bearer token when set
+	apiKey string
+}
+
+func startHTTPNode(t *testing.T) *httpClient {
+	t.Helper()
+	return startHTTPNodeConfig(t, server.Config{DataDir: t.TempDir(), Stable: true})
+}
+
+func startHTTPNodeConfig(t *testing.T, config server.Config) *httpClient {
+	t.Helper()
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	node, err := server.New(config)
+	require.NoError(t, err)
+	ts := httptest.NewServer(node.HTTPHandler())
+	t.Cleanup(func() {
+		ts.Close()
+		require.NoError(t, node.Close())
+	})
+	return &httpClient{t: t, url: ts.URL + "/v1"}
+}
+
+// do sends body as JSON and decodes the JSON answer into a map.
+func (c *httpClient) do(method, path string, body any) (int, map[string]any) {
+	c.t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(c.t, json.NewEncoder(&buf).Encode(body))
+	}
+	req, err := http.NewRequest(method, c.url+path, &buf)
+	require.NoError(c.t, err)
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(c.t, err)
+	defer resp.Body.Close()
+	var out map[string]any
+	if resp.StatusCode != http.StatusNoContent {
+		require.NoError(c.t, json.NewDecoder(resp.Body).Decode(&out))
+	}
+	return resp.StatusCode, out
+}
+
+func resultIds(t *testing.T, out map[string]any) []string {
+	t.Helper()
+	var ids []string
+	for _, res := range out["results"].([]any) {
+		ids = append(ids, res.(map[string]any)["_id"].(string))
+	}
+	return ids
+}
+
+func TestHTTPAPI(t *testing.T) {
+	c := startHTTPNode(t)
+
+	status, out := c.do(http.MethodPost, "/collections", map[string]any{
+		"name":          "docs",
+		"dimension":     2,
+		"invertedIndex": []string{"kind", "rank:integer"},
+	})
+	require.Equal(t, http.StatusCreated, status, out)
+	require.Equal(t, "flat", out["vectorIndex"])
+	require.Contains(t, out["schema"], "rank")
+	status, _ = c.do(http.MethodPost, "/collections", map[string]any{"name": "docs", "dimension": 2})
+	require.Equal(t, http.StatusConflict, status)
+	status, _ = c.do(http.MethodPost, "/collections", map[string]any{"name": "bad", "dimension": 0})
+	require.Equal(t, http.StatusBadRequest, status)
+
+	ids := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+	status, out = c.do(http.MethodPut, "/collections/docs/points", map[string]any{"points": []map[string]any{
+		{"_id": ids[0], "vector": []float32{0, 0}, "kind": "a", "rank": 3},
+		{"_id": ids[1], "vector": []float32{1, 0}, "kind": "b", "rank": 1},
+		{"_id": ids[2], "vector": []float32{5, 5}, "kind": "a", "rank": 2},
+		{"vector": []float32{9, 9}, "kind": "c", "rank": 4},
+	}})
+	require.Equal(t, http.StatusOK, status, out)
+	require.Len(t, out["ids"], 4)
+	status, out = c.do(http.MethodPut, "/collections/docs/points", map[string]any{"points": []map[string]any{
+		{"vector": []float32{1, 2, 3}},
+	}})
+	require.Equal(t, http.StatusBadRequest, status, out)
+
+	status, out = c.do(http.MethodGet, "/collections/docs/points/"+ids[1], nil)
+	require.Equal(t, http.StatusOK, status, out)
+	require.Equal(t, "b", out["kind"])
+	require.Equal(t, []any{1.0, 0.0}, out["vector"])
+	status, _ = c.do(http.MethodGet, "/collections/docs", nil)
+	require.Equal(t, http.StatusOK, status)
+
+	// nearest points of kind a
+	status, out = c.do(http.MethodPost, "/collections/docs/search", map[string]any{
+		"query": map[string]any{
+			"property": "vector",
+			"vectorFlat": map[string]any{
+				"vector":   []float32{1, 0},
+				"operator": "near",
+				"limit":    10,
+				"filter":   map[string]any{"property": "kind", "string": map[string]any{"value": "a", "operator": "equals"}},
+			},
+		},
+		"select": []string{"kind"},
+		"limit":  10,
+	})
+	require.Equal(t, http.StatusOK, status, out)
+	require.Equal(t, []string{ids[0], ids[2]}, resultIds(t, out))
+	first := out["results"].([]any)[0].(map[string]any)
+	require.Contains(t, first, "_distance")
+	require.Contains(t, first, "_hybridScore")
+	require.NotContains(t, first, "rank")
+
+	// index only matches sorted by a property
+	status, out = c.do(http.MethodPost, "/collections/docs/search", map[string]any{
+		"query": map[string]any{"property": "_or", "_or": []map[string]any{
+			{"property": "kind", "string": map[string]any{"value": "b", "operator": "equals"}},
+			{"property": "rank", "integer": map[string]any{"value": 2, "operator": "greaterThanOrEquals"}},
+		}},
+		"sort":  []map[string]any{{"property": "rank", "descending": true}},
+		"limit": 2,
+	})
+	require.Equal(t, http.StatusOK, status, out)
+	require.Len(t, out["results"], 2)
+	require.Equal(t, 4.0, out["results"].([]any)[0].(map[string]any)["rank"])
+	require.Equal(t, 1.0, out["results"].([]any)[0].(map[string]any)["_score"])
+
+	// pages cut the same order the whole result has
+	page := func(offset, limit int, sort []map[string]any) []string {
+		t.Helper()
+		status, out := c.do(http.MethodPost, "/collections/docs/search", map[string]any{
+			"query":  map[string]any{"property": "rank", "integer": map[string]any{"value": 1, "operator": "greaterThanOrEquals"}},
+			"sort":   sort,
+			"offset": offset,
+			"limit":  limit,
+		})
+		require.Equal(t, http.StatusOK, status, out)
+		return resultIds(t, out)
+	}
+	byRank := []map[string]any{{"property": "rank"}}
+	require.Equal(t, []string{ids[1], ids[2]}, page(0, 2, byRank))
+	require.Equal(t, []string{ids[2], ids[0]}, page(1, 2, byRank))
+	all := page(0, 10, nil)
+	require.Len(t, all, 4)
+	var pages []string
+	for offset := 0; offset < 5; offset++ {
+		pages = append(pages, page(offset, 1, nil)...)
+	}
+	require.Equal(t, all, pages)
+	require.Empty(t, page(4, 10, nil))
+
+	// queries are validated against the schema
+	status, out = c.do(http.MethodPost, "/collections/docs/search", map[string]any{
+		"query": map[string]any{"property": "missing", "string": map[string]any{"value": "x", "operator": "equals"}},
+		"limit": 10,
+	})
+	require.Equal(t, http.StatusBadRequest, status, out)
+	status, _ = c.do(http.MethodPost, "/collections/nope/search", map[string]any{"limit": 1})
+	require.Equal(t, http.StatusNotFound, status)
+
+	status, _ = c.do(http.MethodDelete, "/collections/docs/points/"+ids[1], nil)
+	require.Equal(t, http.StatusNoContent, status)
+	status, _ = c.do(http.MethodGet, "/collections/docs/points/"+ids[1], nil)
+	require.Equal(t, http.StatusNotFound, status)
+	status, _ = c.do(http.MethodDelete, "/collections/docs", nil)
+	require.Equal(t, http.StatusNoContent, status)
+	status, out = c.do(http.MethodGet, "/collections", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Empty(t, out["collections"])
+}
+
+func TestHTTPAuthorization(t *testing.T) {
+	c := startHTTPNodeConfig(t, server.Config{DataDir: t.TempDir(), Stable: true, Auth: &auth.Config{
+		Authenticators: []auth.Authenticator{auth.APIKeys{"admin-key": "admin", "reader-key": "reader"}},
+		Policy: auth.Policy{
+			"admin":  {"docs": auth.Admin},
+			"reader": {"docs": auth.Read},
+		},
+	}})
+
+	status, _ := c.do(http.MethodGet, "/collections", nil)
+	require.Equal(t, http.StatusUnauthorized, status)
+	c.apiKey = "reader-key"
+	status, _ = c.do(http.MethodPost, "/collections", map[string]any{"name": "docs", "dimension": 2})
+	require.Equal(t, http.StatusForbidden, status)
+	c.apiKey = "admin-key"
+	status, out := c.do(http.MethodPost, "/collections", map[string]any{"name": "docs", "dimension": 2})
+	require.Equal(t, http.StatusCreated, status, out)
+	// admin on docs only
+	status, _ = c.do(http.MethodPost, "/collections", map[string]any{"name": "other", "dimension": 2})
+	require.Equal(t, http.StatusForbidden, status)
+
+	c.apiKey = "reader-key"
+	status, _ = c.do(http.MethodPut, "/collections/docs/points", map[string]any{"points": []map[string]any{{"vector": []float32{1, 1}}}})
+	require.Equal(t, http.StatusForbidden, status)
+	status, out = c.do(http.MethodGet, "/collections", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, out["collections"], 1)
+}
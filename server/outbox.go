@@ -0,0 +1,183 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/tenant"
+	"github.com/sjy-dv/nnv/storage"
+	"google.golang.org/protobuf/proto"
+)
+
+// Local writes reach the peers through an outbox. A write stores its
+// mutation in the outbox of the collection inside its own transaction, the
+// relay publishes the outbox in order once the write has committed. A write
+// that is durable here is therefore never lost to the peers, however long
+// the stream is out of reach.
+
+const (
+	outboxStorage = "outbox"
+	// pause before an outbox the stream refused is tried again
+	relayInterval = time.Second
+	// bound of a single publish, the relay moves on to the next collection
+	// after it
+	relayTimeout = 5 * time.Second
+	// mutations read from an outbox at once
+	relayBatch = 256
+)
+
+var (
+	nextOutboxKey = []byte("nextOutbox")
+	errBatchFull  = errors.New("batch is full")
+)
+
+// enqueue stores the mutation of a local write in the outbox, it runs inside
+// the write. Nodes without replication keep no outbox.
+func (c *collection) enqueue(sc storage.StorageCoordinator, m *pb.Mutation) error {
+	if !c.replicated {
+		return nil
+	}
+	meta, err := sc.Get(collectionStorage)
+	if err != nil {
+		return err
+	}
+	outbox, err := sc.Get(outboxStorage)
+	if err != nil {
+		return err
+	}
+	seq := getCounter(meta, nextOutboxKey) + 1
+	if err := setCounter(meta, nextOutboxKey, seq); err != nil {
+		return err
+	}
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	// big endian keys sort in the order of the writes
+	return outbox.Put(binary.BigEndian.AppendUint64(nil, seq), data)
+}
+
+// relay publishes the outbox of the collection in order and removes what the
+// stream has stored. It stops at the first mutation the stream refuses.
+func (c *collection) relay(ctx context.Context, publish func(context.Context, *pb.Mutation) error) error {
+	for {
+		var keys [][]byte
+		var batch []*pb.Mutation
+		err := c.db.Read(func(sc storage.StorageCoordinator) error {
+			outbox, err := sc.Get(outboxStorage)
+			if err != nil {
+				return err
+			}
+			err = outbox.RangeScan(nil, nil, false, func(k, v []byte) error {
+				if len(keys) == relayBatch {
+					return errBatchFull
+				}
+				m := &pb.Mutation{}
+				if err := proto.Unmarshal(v, m); err != nil {
+					return err
+				}
+				keys = append(keys, slices.Clone(k))
+				batch = append(batch, m)
+				return nil
+			})
+			if errors.Is(err, errBatchFull) {
+				return nil
+			}
+			return err
+		})
+		if err != nil || len(keys) == 0 {
+			return err
+		}
+		var published int
+		for _, m := range batch {
+			if err = publish(ctx, m); err != nil {
+				break
+			}
+			published++
+		}
+		if published > 0 {
+			removeErr := c.db.Write(func(sc storage.StorageCoordinator) error {
+				outbox, err := sc.Get(outboxStorage)
+				if err != nil {
+					return err
+				}
+				for _, key := range keys[:published] {
+					if err := outbox.Delete(key); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if removeErr != nil {
+				return removeErr
+			}
+		}
+		if err != nil || len(keys) < relayBatch {
+			return err
+		}
+	}
+}
+
+// wakeRelay has the relay look at the outboxes without waiting.
+func (s *Server) wakeRelay() {
+	select {
+	case s.relayDue <- struct{}{}:
+	default:
+	}
+}
+
+// relay publishes the outboxes of the collections until the server closes.
+// Writes wake it up, outboxes the stream refused are tried again every
+// relayInterval.
+func (s *Server) relay() {
+	defer s.background.Done()
+	closing, stop := context.WithCancel(context.Background())
+	defer stop()
+	go func() {
+		<-s.closing
+		stop()
+	}()
+	ticker := time.NewTicker(relayInterval)
+	defer ticker.Stop()
+	for {
+		for _, col := range s.tenantCollections(tenant.All) {
+			if closing.Err() != nil {
+				return
+			}
+			ctx, cancel := context.WithTimeout(closing, relayTimeout)
+			err := col.relay(ctx, s.replicator.Publish)
+			cancel()
+			if err != nil && closing.Err() == nil {
+				log.Warn().Err(err).Str("tenant", col.tenant).Str("collection", col.name).Msg("failed to publish outbox, retrying")
+			}
+		}
+		select {
+		case <-s.closing:
+			return
+		case <-s.relayDue:
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,652 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/cache"
+	"github.com/sjy-dv/nnv/pkg/conversion"
+	"github.com/sjy-dv/nnv/pkg/hlc"
+	"github.com/sjy-dv/nnv/pkg/index"
+	"github.com/sjy-dv/nnv/pkg/models"
+	"github.com/sjy-dv/nnv/pkg/pointstore"
+	"github.com/sjy-dv/nnv/pkg/withcontext"
+	"github.com/sjy-dv/nnv/storage"
+)
+
+var (
+	ErrPointExists       = errors.New("point already exists")
+	ErrDimensionMismatch = errors.New("vector dimension mismatch")
+	ErrInvalidPointId    = errors.New("invalid point id")
+	// a conditional write found the point in another state
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+type writeMode int
+
+const (
+	writeInsert writeMode = iota
+	writeUpdate
+	writeUpsert
+)
+
+type pointWrite struct {
+	id     uuid.UUID
+	vector []float32
+	doc    map[string]any
+	data   []byte
+	// zero when this node stamps the write
+	version pointstore.Version
+	cond    precondition
+	// copies a point stored elsewhere, quotas do not apply
+	internal bool
+	// a mutation of a peer, which is not published again
+	replayed bool
+}
+
+// removal says how a delete treats the point it removes.
+type removal struct {
+	cond precondition
+	// the delete is ordered by the version of another replica, a replicated
+	// or repairing delete. Only ordered deletes leave a tombstone for a point
+	// unknown here, other deletes of unknown points fail.
+	ordered bool
+	// the point lives on in another shard, no tombstone is left
+	handoff bool
+	// a mutation of a peer, which is not published again
+	replayed bool
+}
+
+// precondition guards a write with the state of the point it replaces.
+type precondition struct {
+	ifAbsent bool
+	ifExists bool
+	// nil when the version is not checked
+	ifVersion *uint64
+}
+
+func requestPrecondition(p *pb.Precondition) precondition {
+	if p == nil {
+		return precondition{}
+	}
+	return precondition{ifAbsent: p.GetIfAbsent(), ifExists: p.GetIfExists(), ifVersion: p.IfVersionEquals}
+}
+
+// check runs inside the write transaction against the stored version of the
+// point, a tombstone counts as absent.
+func (p precondition) check(id uuid.UUID, v pointstore.Version, deleted bool) error {
+	exists := !deleted && !v.IsZero()
+	switch {
+	case p.ifAbsent && exists:
+		return fmt.Errorf("%w: point %s exists", ErrPreconditionFailed, id)
+	case p.ifExists && !exists:
+		return fmt.Errorf("%w: point %s does not exist", ErrPreconditionFailed, id)
+	case p.ifVersion != nil && !exists:
+		return fmt.Errorf("%w: point %s does not exist, expected version %d", ErrPreconditionFailed, id, *p.ifVersion)
+	case p.ifVersion != nil && uint64(v.Timestamp) != *p.ifVersion:
+		return fmt.Errorf("%w: point %s has version %d, expected %d", ErrPreconditionFailed, id, v.Timestamp, *p.ifVersion)
+	}
+	return nil
+}
+
+// clock stamps the writes of this node. The hybrid logical clock is shared by
+// the collections of a server and moves past every version it comes across,
+// so a local write always wins over the writes this node has seen.
+type clock struct {
+	*hlc.Clock
+	origin string
+}
+
+// resolve returns the version of a write to a point stored at prev and
+// whether the write wins. Versions too far ahead of the clock are refused.
+func (c *clock) resolve(v, prev pointstore.Version) (pointstore.Version, bool, error) {
+	if v.IsZero() {
+		ts, err := c.Update(prev.Timestamp)
+		if err != nil {
+			return pointstore.Version{}, false, err
+		}
+		return pointstore.Version{Timestamp: ts, Origin: c.origin}, true, nil
+	}
+	if _, err := c.Update(v.Timestamp); err != nil {
+		return pointstore.Version{}, false, fmt.Errorf("%w: version %d: %v", ErrInvalidRequest, v.Timestamp, err)
+	}
+	return v, v.Newer(prev), nil
+}
+
+func requestVersion(version uint64, origin string) pointstore.Version {
+	return pointstore.Version{Timestamp: hlc.Timestamp(version), Origin: origin}
+}
+
+func parsePointId(id string) (uuid.UUID, error) {
+	pointId, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w %q: %v", ErrInvalidPointId, id, err)
+	}
+	return pointId, nil
+}
+
+// newPointWrite validates a dataset against the collection schema before
+// anything is written.
+func (c *collection) newPointWrite(req *pb.ModifyDataset) (pointWrite, error) {
+	id, err := parsePointId(req.GetId())
+	if err != nil {
+		return pointWrite{}, err
+	}
+	if len(req.GetVector()) != int(c.config.GetDimension()) {
+		return pointWrite{}, fmt.Errorf("%w: expected %d got %d", ErrDimensionMismatch, c.config.GetDimension(), len(req.GetVector()))
+	}
+	doc, err := metadataToDocument(req.GetMetadata())
+	if err != nil {
+		return pointWrite{}, err
+	}
+	if err := c.validateDocument(doc); err != nil {
+		return pointWrite{}, err
+	}
+	data, err := encodeDocument(doc)
+	if err != nil {
+		return pointWrite{}, err
+	}
+	return pointWrite{
+		id:       id,
+		vector:   req.GetVector(),
+		doc:      doc,
+		data:     data,
+		version:  requestVersion(req.GetVersion(), req.GetOrigin()),
+		cond:     requestPrecondition(req.GetPrecondition()),
+		internal: req.GetInternal(),
+	}, nil
+}
+
+// metadataPatch changes single fields of a point document.
+type metadataPatch struct {
+	set       map[string]any
+	unset     []string
+	increment map[string]any
+}
+
+func newMetadataPatch(req *pb.PatchMetadataReq) (metadataPatch, error) {
+	set, err := metadataToDocument(req.GetSet())
+	if err != nil {
+		return metadataPatch{}, err
+	}
+	increment, err := metadataToDocument(req.GetIncrement())
+	if err != nil {
+		return metadataPatch{}, err
+	}
+	fields := make(map[string]bool, len(set)+len(req.GetUnset())+len(increment))
+	for _, group := range [][]string{slices.Collect(maps.Keys(set)), req.GetUnset(), slices.Collect(maps.Keys(increment))} {
+		for _, field := range group {
+			if fields[field] {
+				return metadataPatch{}, fmt.Errorf("%w: field %s is patched more than once", ErrInvalidMetadata, field)
+			}
+			fields[field] = true
+		}
+	}
+	for field, delta := range increment {
+		switch delta.(type) {
+		case int64, float64:
+		default:
+			return metadataPatch{}, fmt.Errorf("%w: increment of field %s is not a number", ErrInvalidMetadata, field)
+		}
+	}
+	return metadataPatch{set: set, unset: req.GetUnset(), increment: increment}, nil
+}
+
+// apply returns the patched copy of doc. Integers stay integers when an
+// integer is added, any float turns the field into a float.
+func (p metadataPatch) apply(doc map[string]any) (map[string]any, error) {
+	patched := maps.Clone(doc)
+	maps.Copy(patched, p.set)
+	for _, field := range p.unset {
+		delete(patched, field)
+	}
+	for field, delta := range p.increment {
+		value, ok := patched[field]
+		if !ok || value == nil {
+			patched[field] = delta
+			continue
+		}
+		if a, err := asInteger(value); err == nil {
+			if b, ok := delta.(int64); ok {
+				if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+					return nil, fmt.Errorf("%w: increment of field %s overflows int64", ErrInvalidMetadata, field)
+				}
+				patched[field] = a + b
+				continue
+			}
+		}
+		a, err := asFloat(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cannot increment field %s: %v", ErrInvalidMetadata, field, err)
+		}
+		b, _ := asFloat(delta)
+		patched[field] = a + b
+	}
+	return patched, nil
+}
+
+func (c *collection) validateDocument(doc map[string]any) error {
+	for field, opts := range c.schema {
+		v, ok := doc[field]
+		if !ok || v == nil || isVectorIndexType(opts.Type) {
+			continue
+		}
+		var err error
+		switch opts.Type {
+		case models.IndexTypeString:
+			_, err = asString(v)
+		case models.IndexTypeInteger:
+			_, err = asInteger(v)
+		case models.IndexTypeFloat:
+			_, err = asFloat(v)
+		case models.IndexTypeStringArray:
+			_, err = asStringArray(v)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: indexed field %s: %v", ErrInvalidMetadata, field, err)
+		}
+	}
+	return nil
+}
+
+// set writes a point. Without a version in the write the point is stamped by
+// this node, otherwise the write only happens if its version is newer than
+// the stored one. The result reports the version and whether it was written.
+func (c *collection) set(ctx context.Context, w pointWrite, mode writeMode) (pointstore.Version, bool, error) {
+	v, applied := w.version, false
+	err := c.write(func(sc storage.StorageCoordinator, txn *cache.Transaction) error {
+		points, err := sc.Get(pointsStorage)
+		if err != nil {
+			return err
+		}
+		prev, deleted, err := pointstore.GetVersion(points, w.id)
+		if err != nil {
+			return err
+		}
+		if err := w.cond.check(w.id, prev, deleted); err != nil {
+			return err
+		}
+		if v, applied, err = c.clock.resolve(w.version, prev); err != nil || !applied {
+			return err
+		}
+		if err := c.put(ctx, sc, txn, w, v, mode); err != nil || w.replayed {
+			return err
+		}
+		return c.enqueueUpsert(sc, w, v)
+	})
+	if err != nil {
+		applied = false
+	}
+	return v, applied, err
+}
+
+// patch changes the metadata of an existing point the same way set writes it.
+// The inverted indexes only see the fields that changed.
+func (c *collection) patch(ctx context.Context, id uuid.UUID, p metadataPatch, v pointstore.Version) (pointstore.Version, bool, error) {
+	applied := false
+	err := c.write(func(sc storage.StorageCoordinator, txn *cache.Transaction) error {
+		points, err := sc.Get(pointsStorage)
+		if err != nil {
+			return err
+		}
+		vectors, err := sc.Get(vectorsStorage)
+		if err != nil {
+			return err
+		}
+		meta, err := sc.Get(collectionStorage)
+		if err != nil {
+			return err
+		}
+		prevVersion, _, err := pointstore.GetVersion(points, id)
+		if err != nil {
+			return err
+		}
+		if v, applied, err = c.clock.resolve(v, prevVersion); err != nil || !applied {
+			return err
+		}
+		prev, err := pointstore.GetPointByUUID(points, id)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, id)
+		}
+		prevDoc, err := decodeDocument(prev.Data)
+		if err != nil {
+			return err
+		}
+		doc, err := p.apply(prevDoc)
+		if err != nil {
+			return err
+		}
+		if err := c.validateDocument(doc); err != nil {
+			return err
+		}
+		data, err := encodeDocument(doc)
+		if err != nil {
+			return err
+		}
+		if err := c.account(meta, 0, c.pointBytes(data)-c.pointBytes(prev.Data), false); err != nil {
+			return err
+		}
+		w := pointWrite{id: id, doc: doc, data: data, version: v}
+		if vectorBytes := vectors.Get(conversion.NodeKey(prev.NodeId, 'v')); vectorBytes != nil {
+			w.vector = conversion.BytesToFloat32(vectorBytes)
+		}
+		point := pointstore.ShardPoint{
+			Point:   models.Point{Id: id, Data: data},
+			NodeId:  prev.NodeId,
+			Version: v,
+		}
+		if err := pointstore.SetPoint(points, point); err != nil {
+			return err
+		}
+		if err := c.updateInvertedIndexes(ctx, sc, prev.NodeId, prevDoc, doc); err != nil {
+			return err
+		}
+		// peers get the patched point, replaying an increment would not be
+		// idempotent
+		return c.enqueueUpsert(sc, w, v)
+	})
+	if err != nil {
+		applied = false
+	}
+	return v, applied, err
+}
+
+// remove deletes a point the same way set writes it and leaves a tombstone
+// behind. Ordered deletes of unknown points are kept as tombstones so a late
+// write that lost the race is not revived, unless the tombstone is past the
+// horizon already. A handoff leaves no tombstone, the point lives on in
+// another shard.
+func (c *collection) remove(ctx context.Context, id uuid.UUID, v pointstore.Version, r removal) (pointstore.Version, bool, error) {
+	applied := false
+	err := c.write(func(sc storage.StorageCoordinator, txn *cache.Transaction) error {
+		points, err := sc.Get(pointsStorage)
+		if err != nil {
+			return err
+		}
+		prev, deleted, err := pointstore.GetVersion(points, id)
+		if err != nil {
+			return err
+		}
+		if err := r.cond.check(id, prev, deleted); err != nil {
+			return err
+		}
+		if v, applied, err = c.clock.resolve(v, prev); err != nil || !applied {
+			return err
+		}
+		err = c.delete(ctx, sc, txn, id, v)
+		unknown := errors.Is(err, pointstore.ErrPointDoesNotExist)
+		switch {
+		case unknown && r.handoff:
+			err = nil
+		case unknown && r.ordered && c.expired(v):
+			applied = false
+			return nil
+		case unknown && r.ordered:
+			err = pointstore.SetTombstone(points, id, v)
+		case err == nil && r.handoff:
+			err = pointstore.DropTombstone(points, id)
+		}
+		if err != nil || r.replayed {
+			return err
+		}
+		return c.enqueue(sc, &pb.Mutation{
+			Type:           pb.MutationType_MUTATION_DELETE,
+			Tenant:         c.tenant,
+			CollectionName: c.name,
+			Id:             id.String(),
+			Version:        uint64(v.Timestamp),
+			Origin:         v.Origin,
+			Handoff:        r.handoff,
+		})
+	})
+	if err != nil {
+		applied = false
+	}
+	return v, applied, err
+}
+
+// apply replays a mutation of a peer. Older mutations than the stored version
+// are skipped, which makes replays and out of order delivery harmless.
+func (c *collection) apply(ctx context.Context, m *pb.Mutation) (bool, error) {
+	v := pointstore.Version{Timestamp: hlc.Timestamp(m.GetVersion()), Origin: m.GetOrigin()}
+	if v.IsZero() {
+		return false, errors.New("mutation without version")
+	}
+	if m.GetType() == pb.MutationType_MUTATION_DELETE {
+		id, err := parsePointId(m.GetId())
+		if err != nil {
+			return false, err
+		}
+		_, applied, err := c.remove(ctx, id, v, removal{ordered: true, handoff: m.GetHandoff(), replayed: true})
+		return applied, err
+	}
+	w, err := c.newPointWrite(&pb.ModifyDataset{
+		Id:             m.GetId(),
+		CollectionName: m.GetCollectionName(),
+		Vector:         m.GetVector(),
+		Metadata:       m.GetMetadata(),
+		Version:        m.GetVersion(),
+		Origin:         m.GetOrigin(),
+		// the origin held the write to the quota already
+		Internal: true,
+	})
+	if err != nil {
+		return false, err
+	}
+	w.replayed = true
+	_, applied, err := c.set(ctx, w, writeUpsert)
+	return applied, err
+}
+
+// enqueueUpsert hands a written point to the peers.
+func (c *collection) enqueueUpsert(sc storage.StorageCoordinator, w pointWrite, v pointstore.Version) error {
+	if !c.replicated {
+		return nil
+	}
+	metadata, err := documentToMetadata(w.doc)
+	if err != nil {
+		return err
+	}
+	return c.enqueue(sc, &pb.Mutation{
+		Type:           pb.MutationType_MUTATION_UPSERT,
+		Tenant:         c.tenant,
+		CollectionName: c.name,
+		Id:             w.id.String(),
+		Vector:         w.vector,
+		Metadata:       metadata,
+		Version:        uint64(v.Timestamp),
+		Origin:         v.Origin,
+	})
+}
+
+func (c *collection) put(ctx context.Context, sc storage.StorageCoordinator, txn *cache.Transaction, w pointWrite, v pointstore.Version, mode writeMode) error {
+	points, err := sc.Get(pointsStorage)
+	if err != nil {
+		return err
+	}
+	meta, err := sc.Get(collectionStorage)
+	if err != nil {
+		return err
+	}
+	// ---------------------------
+	prev, err := pointstore.GetPointByUUID(points, w.id)
+	exists := err == nil
+	if err != nil && !errors.Is(err, pointstore.ErrPointDoesNotExist) {
+		return err
+	}
+	switch {
+	case exists && mode == writeInsert:
+		return fmt.Errorf("%w: %s", ErrPointExists, w.id)
+	case !exists && mode == writeUpdate:
+		return fmt.Errorf("%w: %s", pointstore.ErrPointDoesNotExist, w.id)
+	}
+	prevDoc, err := decodeDocument(prev.Data)
+	if err != nil {
+		return err
+	}
+	nodeId := prev.NodeId
+	added, grown := int64(0), c.pointBytes(w.data)-c.pointBytes(prev.Data)
+	if !exists {
+		nodeId = getCounter(meta, nextNodeIdKey) + 1
+		if err := setCounter(meta, nextNodeIdKey, nodeId); err != nil {
+			return err
+		}
+		added, grown = 1, c.pointBytes(w.data)
+	}
+	if err := c.account(meta, added, grown, w.internal); err != nil {
+		return err
+	}
+	// ---------------------------
+	point := pointstore.ShardPoint{
+		Point:   models.Point{Id: w.id, Data: w.data},
+		NodeId:  nodeId,
+		Version: v,
+	}
+	if err := pointstore.SetPoint(points, point); err != nil {
+		return err
+	}
+	if err := c.updateVectorIndex(ctx, txn, sc, models.IndexVectorChange{Id: nodeId, Vector: w.vector}); err != nil {
+		return err
+	}
+	return c.updateInvertedIndexes(ctx, sc, nodeId, prevDoc, w.doc)
+}
+
+func (c *collection) delete(ctx context.Context, sc storage.StorageCoordinator, txn *cache.Transaction, id uuid.UUID, v pointstore.Version) error {
+	points, err := sc.Get(pointsStorage)
+	if err != nil {
+		return err
+	}
+	meta, err := sc.Get(collectionStorage)
+	if err != nil {
+		return err
+	}
+	prev, err := pointstore.GetPointByUUID(points, id)
+	if err != nil {
+		return err
+	}
+	prevDoc, err := decodeDocument(prev.Data)
+	if err != nil {
+		return err
+	}
+	if err := pointstore.DeletePoint(points, id, prev.NodeId, v); err != nil {
+		return err
+	}
+	if err := c.account(meta, -1, -c.pointBytes(prev.Data), true); err != nil {
+		return err
+	}
+	if err := c.updateVectorIndex(ctx, txn, sc, models.IndexVectorChange{Id: prev.NodeId}); err != nil {
+		return err
+	}
+	return c.updateInvertedIndexes(ctx, sc, prev.NodeId, prevDoc, nil)
+}
+
+func (c *collection) updateVectorIndex(ctx context.Context, txn *cache.Transaction, sc storage.StorageCoordinator, change models.IndexVectorChange) error {
+	return c.withVectorIndex(txn, sc, func(vi vectorIndex) error {
+		in := withcontext.ProduceWithContext(ctx, []models.IndexVectorChange{change})
+		if err := <-vi.InsertUpdateDelete(ctx, in); err != nil {
+			return fmt.Errorf("failed to update vector index: %w", err)
+		}
+		return nil
+	})
+}
+
+// updateInvertedIndexes diffs the indexed fields of the previous and current
+// document of a point. A nil current document removes the point.
+func (c *collection) updateInvertedIndexes(ctx context.Context, sc storage.StorageCoordinator, nodeId uint64, prev, curr map[string]any) error {
+	fields := make([]string, 0, len(c.schema))
+	for field, opts := range c.schema {
+		if !isVectorIndexType(opts.Type) {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		prevValue, currValue := prev[field], curr[field]
+		if reflect.DeepEqual(prevValue, currValue) {
+			continue
+		}
+		bucket, err := sc.Get(invertedStorageName(field))
+		if err != nil {
+			return err
+		}
+		switch c.schema[field].Type {
+		case models.IndexTypeString:
+			err = updateInverted(ctx, bucket, nodeId, prevValue, currValue, asString)
+		case models.IndexTypeInteger:
+			err = updateInverted(ctx, bucket, nodeId, prevValue, currValue, asInteger)
+		case models.IndexTypeFloat:
+			err = updateInverted(ctx, bucket, nodeId, prevValue, currValue, asFloat)
+		case models.IndexTypeStringArray:
+			err = updateInvertedArray(ctx, bucket, nodeId, prevValue, currValue)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update inverted index %s: %w", field, err)
+		}
+	}
+	return nil
+}
+
+func updateInverted[T index.Invertable](ctx context.Context, bucket storage.Storage, nodeId uint64, prev, curr any, cast func(any) (T, error)) error {
+	change := index.IndexChange[T]{Id: nodeId}
+	if prev != nil {
+		v, err := cast(prev)
+		if err != nil {
+			return err
+		}
+		change.PreviousData = &v
+	}
+	if curr != nil {
+		v, err := cast(curr)
+		if err != nil {
+			return err
+		}
+		change.CurrentData = &v
+	}
+	inv := index.NewIndexInverted[T](bucket)
+	return <-inv.InsertUpdateDelete(ctx, withcontext.ProduceWithContext(ctx, []index.IndexChange[T]{change}))
+}
+
+func updateInvertedArray(ctx context.Context, bucket storage.Storage, nodeId uint64, prev, curr any) error {
+	change := index.IndexArrayChange[string]{Id: nodeId}
+	if prev != nil {
+		v, err := asStringArray(prev)
+		if err != nil {
+			return err
+		}
+		change.PreviousData = v
+	}
+	if curr != nil {
+		v, err := asStringArray(curr)
+		if err != nil {
+			return err
+		}
+		change.CurrentData = v
+	}
+	inv := index.NewIndexInvertedArray[string](bucket)
+	return <-inv.InsertUpdateDelete(ctx, withcontext.ProduceWithContext(ctx, []index.IndexArrayChange[string]{change}))
+}
@@ -0,0 +1,311 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/RoaringBitmap/roaring/roaring64"
+	"github.com/sjy-dv/nnv/pkg/cache"
+	"github.com/sjy-dv/nnv/pkg/conversion"
+	"github.com/sjy-dv/nnv/pkg/models"
+	"github.com/sjy-dv/nnv/pkg/pointstore"
+	"github.com/sjy-dv/nnv/storage"
+)
+
+var ErrInvalidQuery = errors.New("invalid query")
+
+// matches are the points a query selects keyed by node id.
+type matches map[uint64]*models.SearchResult
+
+// query runs a search request of the JSON API. A vector query scores its
+// nearest points by similarity times its weight, every other leaf matches
+// through the inverted indexes with a score of 1. _and keeps the points
+// matched by all sub queries and _or the ones matched by any, the scores of
+// a point add up to its hybrid score which orders the results unless the
+// request sorts by properties.
+//
+// Only the first offset+limit results in that order are kept while the
+// matches are read, and the documents are decoded for the ones returned
+// only, unless the request sorts by their properties.
+func (c *collection) query(ctx context.Context, req models.SearchRequest) ([]models.SearchResult, error) {
+	if err := req.Validate(c.schema); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	var results []models.SearchResult
+	err := c.read(func(sc storage.StorageCoordinator, txn *cache.Transaction) error {
+		found, err := c.match(ctx, sc, txn, req.Query)
+		if err != nil {
+			return err
+		}
+		points, err := sc.Get(pointsStorage)
+		if err != nil {
+			return err
+		}
+		vectors, err := sc.Get(vectorsStorage)
+		if err != nil {
+			return err
+		}
+		decode := func(res *models.SearchResult, data []byte) error {
+			res.DecodedData, err = decodeDocument(data)
+			return err
+		}
+		sorted := len(req.Sort) > 0
+		window := newResultWindow(req.Offset+req.Limit, req.Sort)
+		for nodeId, res := range found {
+			// the properties a request sorts by are in the document, the
+			// other orders only need the id
+			point, err := pointstore.GetPointByNodeId(points, nodeId, sorted)
+			if errors.Is(err, pointstore.ErrPointDoesNotExist) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get point %d: %w", nodeId, err)
+			}
+			res.Point = point.Point
+			res.NodeId = nodeId
+			if sorted {
+				if err := decode(res, point.Data); err != nil {
+					return err
+				}
+			}
+			window.add(*res)
+		}
+		results = window.results[min(req.Offset, len(window.results)):]
+		for i := range results {
+			res := &results[i]
+			if !sorted {
+				point, err := pointstore.GetPointByNodeId(points, res.NodeId, true)
+				if err != nil {
+					return fmt.Errorf("failed to get point %d: %w", res.NodeId, err)
+				}
+				if err := decode(res, point.Data); err != nil {
+					return err
+				}
+			}
+			if slices.Contains(req.Select, vectorProperty) {
+				if vectorBytes := vectors.Get(conversion.NodeKey(res.NodeId, 'v')); vectorBytes != nil {
+					res.DecodedData[vectorProperty] = conversion.BytesToFloat32(vectorBytes)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Select) > 0 {
+		for _, res := range results {
+			for field := range res.DecodedData {
+				if !slices.Contains(req.Select, field) {
+					delete(res.DecodedData, field)
+				}
+			}
+		}
+	}
+	return results, nil
+}
+
+func (c *collection) match(ctx context.Context, sc storage.StorageCoordinator, txn *cache.Transaction, q models.Query) (matches, error) {
+	switch q.Property {
+	case "_and", "_or":
+		subQueries := q.And
+		if q.Property == "_or" {
+			subQueries = q.Or
+		}
+		if len(subQueries) == 0 {
+			return nil, fmt.Errorf("%w: empty %s", ErrInvalidQuery, q.Property)
+		}
+		var merged matches
+		for i, subQuery := range subQueries {
+			found, err := c.match(ctx, sc, txn, subQuery)
+			if err != nil {
+				return nil, err
+			}
+			if i == 0 {
+				merged = found
+				continue
+			}
+			merged = merge(merged, found, q.Property == "_and")
+		}
+		return merged, nil
+	}
+	if opts, ok := c.schema[q.Property]; ok && isVectorIndexType(opts.Type) {
+		return c.matchVector(ctx, sc, txn, *q.VectorFlat)
+	}
+	set, err := c.evaluateQuery(sc, q)
+	if err != nil {
+		return nil, err
+	}
+	found := make(matches, set.GetCardinality())
+	it := set.Iterator()
+	for it.HasNext() {
+		score := float32(1)
+		found[it.Next()] = &models.SearchResult{Score: &score, HybridScore: score}
+	}
+	return found, nil
+}
+
+func (c *collection) matchVector(ctx context.Context, sc storage.StorageCoordinator, txn *cache.Transaction, options models.SearchVectorFlatOptions) (matches, error) {
+	meta, err := sc.Get(collectionStorage)
+	if err != nil {
+		return nil, err
+	}
+	if getCounter(meta, pointCountKey) == 0 {
+		return matches{}, nil
+	}
+	var filter *roaring64.Bitmap
+	if options.Filter != nil {
+		if filter, err = c.evaluateQuery(sc, *options.Filter); err != nil {
+			return nil, err
+		}
+		if filter.IsEmpty() {
+			return matches{}, nil
+		}
+	}
+	var results []models.SearchResult
+	err = c.withVectorIndex(txn, sc, func(vi vectorIndex) error {
+		_, results, err = vi.Search(ctx, options, filter)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("vector search failed: %w", err)
+	}
+	weight := float32(1)
+	if options.Weight != nil {
+		weight = *options.Weight
+	}
+	found := make(matches, len(results))
+	for _, res := range results {
+		found[res.NodeId] = &models.SearchResult{
+			Distance:    res.Distance,
+			HybridScore: weight * similarity(*res.Distance),
+		}
+	}
+	return found, nil
+}
+
+// merge combines the matches of two sub queries, only the points both matched
+// are kept when intersect is set.
+func merge(a, b matches, intersect bool) matches {
+	merged := make(matches, len(a))
+	for nodeId, res := range a {
+		other, ok := b[nodeId]
+		switch {
+		case ok:
+			merged[nodeId] = combine(res, other)
+		case !intersect:
+			merged[nodeId] = res
+		}
+	}
+	if !intersect {
+		for nodeId, res := range b {
+			if _, ok := a[nodeId]; !ok {
+				merged[nodeId] = res
+			}
+		}
+	}
+	return merged
+}
+
+func combine(a, b *models.SearchResult) *models.SearchResult {
+	res := &models.SearchResult{
+		Distance:    a.Distance,
+		HybridScore: a.HybridScore + b.HybridScore,
+	}
+	if res.Distance == nil || (b.Distance != nil && *b.Distance < *res.Distance) {
+		res.Distance = b.Distance
+	}
+	if a.Score != nil || b.Score != nil {
+		var score float32
+		for _, s := range []*float32{a.Score, b.Score} {
+			if s != nil {
+				score += *s
+			}
+		}
+		res.Score = &score
+	}
+	return res
+}
+
+// resultWindow keeps the first size results added in the order of
+// compareResults, the others are dropped as they come.
+type resultWindow struct {
+	size    int
+	compare func(a, b models.SearchResult) int
+	results []models.SearchResult
+}
+
+func newResultWindow(size int, options []models.SortOption) *resultWindow {
+	return &resultWindow{size: size, compare: compareResults(options)}
+}
+
+func (w *resultWindow) add(res models.SearchResult) {
+	if len(w.results) == w.size && w.compare(res, w.results[w.size-1]) >= 0 {
+		return
+	}
+	i, _ := slices.BinarySearchFunc(w.results, res, w.compare)
+	w.results = slices.Insert(w.results, i, res)
+	if len(w.results) > w.size {
+		w.results = w.results[:w.size]
+	}
+}
+
+// compareResults orders by the sort options, then by hybrid score and then by
+// id so equal results keep a stable order between requests. Points without a
+// sort property come last, numbers compare as numbers and anything else by its
+// string form.
+func compareResults(options []models.SortOption) func(a, b models.SearchResult) int {
+	return func(a, b models.SearchResult) int {
+		for _, opt := range options {
+			va, oka := a.DecodedData[opt.Property]
+			vb, okb := b.DecodedData[opt.Property]
+			if oka != okb {
+				if oka {
+					return -1
+				}
+				return 1
+			}
+			c := compareValues(va, vb)
+			if opt.Descending {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		if c := cmp.Compare(b.HybridScore, a.HybridScore); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.Id[:], b.Id[:])
+	}
+}
+
+func compareValues(a, b any) int {
+	fa, errA := asFloat(a)
+	fb, errB := asFloat(b)
+	if errA == nil && errB == nil {
+		return cmp.Compare(fa, fb)
+	}
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
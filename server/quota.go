@@ -0,0 +1,100 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sjy-dv/nnv/pkg/tenant"
+)
+
+// Quota limits what a tenant keeps on a node, a zero limit is off. With
+// shards every node enforces the quota on its own share.
+type Quota struct {
+	Collections int
+	Points      uint64
+	// PointBytes counts 4 bytes per vector dimension and the encoded
+	// metadata of every point. It is not the disk_size a collection reports,
+	// which also holds the indexes and the free pages of the storage.
+	PointBytes uint64
+}
+
+func (s *Server) quota(name string) Quota {
+	if q, ok := s.config.Quotas[name]; ok {
+		return q
+	}
+	return s.config.Quotas[tenant.All]
+}
+
+// admitCollection runs with s.mu held.
+func (s *Server) admitCollection(name string) error {
+	q := s.quota(name)
+	if q.Collections == 0 {
+		return nil
+	}
+	var count int
+	for key := range s.collections {
+		if key.tenant == name {
+			count++
+		}
+	}
+	if count >= q.Collections {
+		return fmt.Errorf("%w: tenant %q has %d of %d collections", ErrQuotaExceeded, name, count, q.Collections)
+	}
+	return nil
+}
+
+// usage counts what the collections of a tenant store on this node. Writes
+// change it from inside their transaction, see collection.account, so
+// concurrent writes cannot overshoot the quota.
+type usage struct {
+	tenant string
+	quota  Quota
+	mu     sync.Mutex
+	points int64
+	bytes  int64
+}
+
+// tenantUsage runs with s.mu held.
+func (s *Server) tenantUsage(name string) *usage {
+	u, ok := s.usage[name]
+	if !ok {
+		u = &usage{tenant: name, quota: s.quota(name)}
+		s.usage[name] = u
+	}
+	return u
+}
+
+// add changes the usage by points and bytes. Growth past a limit is refused
+// unless the write is exempt, shrinking is always admitted.
+func (u *usage) add(points, bytes int64, exempt bool) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !exempt {
+		if limit := int64(u.quota.Points); limit > 0 && points > 0 && u.points+points > limit {
+			return fmt.Errorf("%w: tenant %q has %d of %d points", ErrQuotaExceeded, u.tenant, u.points, limit)
+		}
+		if limit := int64(u.quota.PointBytes); limit > 0 && bytes > 0 && u.bytes+bytes > limit {
+			return fmt.Errorf("%w: tenant %q has %d of %d point bytes (vectors and metadata, not disk size)", ErrQuotaExceeded, u.tenant, u.bytes, limit)
+		}
+	}
+	u.points += points
+	u.bytes += bytes
+	return nil
+}
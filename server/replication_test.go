@@ -0,0 +1,294 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/errcode"
+	"github.com/sjy-dv/nnv/pkg/tenant"
+	"github.com/sjy-dv/nnv/replication"
+	"github.com/sjy-dv/nnv/server"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+type replicatedNode struct {
+	client pb.LBCoordinatorClient
+	stop   func()
+	config server.Config
+}
+
+func startReplicatedNodes(t *testing.T, count int) []*replicatedNode {
+	t.Helper()
+	ns, err := replication.StartEmbedded("127.0.0.1", -1, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(ns.Shutdown)
+	return startReplicas(t, ns.ClientURL(), count)
+}
+
+// startReplicas starts nodes that replicate through the nats server at url.
+func startReplicas(t *testing.T, url string, count int) []*replicatedNode {
+	t.Helper()
+	nodes := make([]*replicatedNode, count)
+	for i := range nodes {
+		config := server.Config{
+			DataDir:     t.TempDir(),
+			Stable:      true,
+			NodeId:      fmt.Sprintf("node-%d", i),
+			Replication: &replication.Config{URL: url},
+		}
+		client, stop := startNodeConfig(t, config)
+		nodes[i] = &replicatedNode{client: client, stop: stop, config: config}
+		resp, err := client.CreateCollection(context.Background(), &pb.Collection{
+			CollectionName: "docs",
+			Dimension:      2,
+			InvertedIndex:  []string{"category"},
+		})
+		require.NoError(t, err)
+		require.True(t, resp.GetResponse().GetResult(), resp.GetResponse().GetErrorMessage())
+	}
+	t.Cleanup(func() {
+		for _, n := range nodes {
+			n.stop()
+		}
+	})
+	return nodes
+}
+
+func (n *replicatedNode) restart(t *testing.T) {
+	t.Helper()
+	n.stop()
+	n.client, n.stop = startNodeConfig(t, n.config)
+}
+
+func fetch(t *testing.T, client pb.LBCoordinatorClient, id string) *pb.Row {
+	t.Helper()
+	resp, err := client.GetPoints(context.Background(), &pb.PointIds{CollectionName: "docs", Ids: []string{id}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	if len(resp.GetPoints()) == 0 {
+		return nil
+	}
+	return resp.GetPoints()[0]
+}
+
+// converged waits until every node returns the same vector for the point, nil
+// if the point has to be gone.
+func converged(t *testing.T, nodes []*replicatedNode, id string, vector []float32) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		for _, n := range nodes {
+			row := fetch(t, n.client, id)
+			if (row == nil) != (vector == nil) || (row != nil && !slices.Equal(row.GetVector(), vector)) {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 20*time.Millisecond)
+}
+
+func TestReplication(t *testing.T) {
+	nodes := startReplicatedNodes(t, 3)
+	ctx := context.Background()
+	id := uuid.NewString()
+
+	resp, err := nodes[0].client.Insert(ctx, &pb.ModifyDataset{
+		Id:             id,
+		CollectionName: "docs",
+		Vector:         []float32{1, 1},
+		Metadata:       map[string]*anypb.Any{"category": mustAny(t, "a")},
+	})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	converged(t, nodes, id, []float32{1, 1})
+
+	// replicated points are searchable through the inverted index
+	search, err := nodes[2].client.Search(ctx, &pb.SearchReq{
+		CollectionName: "docs",
+		Vector:         []float32{1, 1},
+		Metadata:       map[string]*anypb.Any{"category": mustAny(t, "a")},
+	})
+	require.NoError(t, err)
+	require.True(t, search.GetResult(), search.GetErrorMessage())
+	require.Len(t, search.GetResponse(), 1)
+
+	resp, err = nodes[1].client.Update(ctx, &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{2, 2}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	converged(t, nodes, id, []float32{2, 2})
+
+	// a node that was down catches up from the stream
+	nodes[2].stop()
+	resp, err = nodes[0].client.Update(ctx, &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{3, 3}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	nodes[2].restart(t)
+	converged(t, nodes, id, []float32{3, 3})
+
+	// patches replicate as the patched point, the peers do not add again
+	patch, err := nodes[1].client.PatchMetadata(ctx, &pb.PatchMetadataReq{
+		Id:             id,
+		CollectionName: "docs",
+		Set:            map[string]*anypb.Any{"category": mustAny(t, "b")},
+		Increment:      map[string]*anypb.Any{"views": mustAny(t, int64(2))},
+	})
+	require.NoError(t, err)
+	require.True(t, patch.GetResult(), patch.GetErrorMessage())
+	require.Eventually(t, func() bool {
+		for _, n := range nodes {
+			row := fetch(t, n.client, id)
+			if !proto.Equal(row.GetMetadata()["category"], mustAny(t, "b")) || !proto.Equal(row.GetMetadata()["views"], mustAny(t, int64(2))) {
+				return false
+			}
+			if !slices.Equal(row.GetVector(), []float32{3, 3}) {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 20*time.Millisecond)
+
+	resp, err = nodes[2].client.Delete(ctx, &pb.DeleteDataset{Id: id, CollectionName: "docs"})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	converged(t, nodes, id, nil)
+	for _, n := range nodes {
+		col, err := n.client.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+		require.NoError(t, err)
+		require.Zero(t, col.GetCollectionSize())
+	}
+
+	// writes replicate into the collection of their tenant
+	teamA := metadata.AppendToOutgoingContext(ctx, tenant.MetadataKey, "team-a")
+	for _, n := range nodes {
+		created, err := n.client.CreateCollection(teamA, &pb.Collection{CollectionName: "docs", Dimension: 2})
+		require.NoError(t, err)
+		require.True(t, created.GetResponse().GetResult(), created.GetResponse().GetErrorMessage())
+	}
+	resp, err = nodes[0].client.Insert(teamA, &pb.ModifyDataset{Id: uuid.NewString(), CollectionName: "docs", Vector: []float32{1, 1}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	require.Eventually(t, func() bool {
+		col, err := nodes[2].client.GetCollection(teamA, &pb.CollectionName{CollectionName: "docs"})
+		return err == nil && col.GetCollectionSize() == 1
+	}, 10*time.Second, 20*time.Millisecond)
+	col, err := nodes[2].client.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+	require.NoError(t, err)
+	require.Zero(t, col.GetCollectionSize())
+}
+
+func TestReplicationOutbox(t *testing.T) {
+	storeDir := t.TempDir()
+	ns, err := replication.StartEmbedded("127.0.0.1", -1, storeDir)
+	require.NoError(t, err)
+	port := ns.Addr().(*net.TCPAddr).Port
+	nodes := startReplicas(t, ns.ClientURL(), 2)
+	ctx := context.Background()
+
+	// a write that is durable locally succeeds while the stream is out of
+	// reach, the peers get it once the stream is back
+	ns.Shutdown()
+	id := uuid.NewString()
+	resp, err := nodes[0].client.Insert(ctx, &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{1, 1}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	require.Nil(t, fetch(t, nodes[1].client, id))
+	ns, err = replication.StartEmbedded("127.0.0.1", port, storeDir)
+	require.NoError(t, err)
+	t.Cleanup(ns.Shutdown)
+	require.Eventually(t, func() bool {
+		return fetch(t, nodes[1].client, id) != nil
+	}, 30*time.Second, 50*time.Millisecond)
+
+	// mutations of a collection a peer does not have yet wait for it
+	created, err := nodes[0].client.CreateCollection(ctx, &pb.Collection{CollectionName: "late", Dimension: 2})
+	require.NoError(t, err)
+	require.True(t, created.GetResponse().GetResult(), created.GetResponse().GetErrorMessage())
+	resp, err = nodes[0].client.Insert(ctx, &pb.ModifyDataset{Id: uuid.NewString(), CollectionName: "late", Vector: []float32{1, 1}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	created, err = nodes[1].client.CreateCollection(ctx, &pb.Collection{CollectionName: "late", Dimension: 2})
+	require.NoError(t, err)
+	require.True(t, created.GetResponse().GetResult(), created.GetResponse().GetErrorMessage())
+	require.Eventually(t, func() bool {
+		col, err := nodes[1].client.GetCollection(ctx, &pb.CollectionName{CollectionName: "late"})
+		return err == nil && col.GetCollectionSize() == 1
+	}, 10*time.Second, 20*time.Millisecond)
+}
+
+func TestReplicationConflicts(t *testing.T) {
+	nodes := startReplicatedNodes(t, 3)
+	ctx := context.Background()
+	ids := make([]string, 20)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+
+	// every node writes every point at once, each with its own vector
+	var wg sync.WaitGroup
+	for n, node := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, id := range ids {
+				node.client.Insert(ctx, &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{float32(n), 0}})
+				node.client.Update(ctx, &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{float32(n), 1}})
+				if n == 0 {
+					node.client.Delete(ctx, &pb.DeleteDataset{Id: id, CollectionName: "docs"})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// all nodes settle on the same last write
+	require.Eventually(t, func() bool {
+		for _, id := range ids {
+			first := fetch(t, nodes[0].client, id)
+			for _, n := range nodes[1:] {
+				row := fetch(t, n.client, id)
+				if (row == nil) != (first == nil) || (row != nil && !slices.Equal(row.GetVector(), first.GetVector())) {
+					return false
+				}
+			}
+		}
+		return true
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// a write after the dust settled wins everywhere
+	for _, id := range ids {
+		_, err := nodes[1].client.Insert(ctx, &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{9, 9}})
+		if code, _ := errcode.FromError(err); code == pb.ErrorCode_ALREADY_EXISTS {
+			_, err = nodes[1].client.Update(ctx, &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{9, 9}})
+		}
+		require.NoError(t, err)
+	}
+	for _, id := range ids {
+		converged(t, nodes, id, []float32{9, 9})
+	}
+}
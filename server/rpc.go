@@ -0,0 +1,517 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	pointIdsChunk      = 1000
+	defaultScrollLimit = 100
+	// larger pages are cut, the cursor picks up the rest
+	maxScrollLimit = 1000
+)
+
+type rpcServer struct {
+	pb.UnimplementedLBCoordinatorServer
+	server *Server
+}
+
+func okResponse() *pb.Response {
+	return &pb.Response{Result: true}
+}
+
+// respond answers an RPC, a failed one with its status.
+func respond(err error) (*pb.Response, error) {
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return okResponse(), nil
+}
+
+// errResponse is the answer to a failed item of a stream or a batch, the
+// call itself goes on.
+func errResponse(err error) *pb.Response {
+	return &pb.Response{
+		Result:       false,
+		ErrorMessage: err.Error(),
+		ErrorCode:    errorCode(err),
+	}
+}
+
+func errOrOk(err error) *pb.Response {
+	if err != nil {
+		return errResponse(err)
+	}
+	return okResponse()
+}
+
+func (r *rpcServer) Ping(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (r *rpcServer) CreateCollection(ctx context.Context, req *pb.Collection) (*pb.CollectionResponse, error) {
+	col, err := r.server.createCollection(ctx, req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	info, err := col.info()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	log.Info().Str("collection", col.name).Msg("collection created")
+	return &pb.CollectionResponse{Response: okResponse(), Collection: info}, nil
+}
+
+func (r *rpcServer) DropCollection(ctx context.Context, req *pb.CollectionName) (*pb.Response, error) {
+	if err := r.server.dropCollection(ctx, req.GetCollectionName()); err != nil {
+		return nil, statusError(err)
+	}
+	log.Info().Str("collection", req.GetCollectionName()).Msg("collection dropped")
+	return okResponse(), nil
+}
+
+func (r *rpcServer) GetCollection(ctx context.Context, req *pb.CollectionName) (*pb.Collection, error) {
+	col, err := r.server.getCollection(ctx, req.GetCollectionName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	info, err := col.info()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return info, nil
+}
+
+func (r *rpcServer) ListCollection(ctx context.Context, _ *emptypb.Empty) (*pb.CollectionList, error) {
+	list := &pb.CollectionList{}
+	for _, col := range r.server.listCollections(ctx) {
+		if !auth.Allowed(ctx, col.tenant, col.name, auth.Read) {
+			continue
+		}
+		info, err := col.info()
+		if err != nil {
+			return nil, statusError(err)
+		}
+		list.Collections = append(list.Collections, info)
+		list.TotalSize += info.GetDiskSize()
+	}
+	list.Count = uint64(len(list.Collections))
+	return list, nil
+}
+
+func (r *rpcServer) CreateAlias(ctx context.Context, req *pb.Alias) (*pb.Response, error) {
+	if err := r.server.createAlias(ctx, req.GetAlias(), req.GetCollectionName()); err != nil {
+		return nil, statusError(err)
+	}
+	log.Info().Str("alias", req.GetAlias()).Str("collection", req.GetCollectionName()).Msg("alias created")
+	return okResponse(), nil
+}
+
+func (r *rpcServer) PointAlias(ctx context.Context, req *pb.Alias) (*pb.Response, error) {
+	if err := r.server.pointAlias(ctx, req.GetAlias(), req.GetCollectionName()); err != nil {
+		return nil, statusError(err)
+	}
+	log.Info().Str("alias", req.GetAlias()).Str("collection", req.GetCollectionName()).Msg("alias pointed")
+	return okResponse(), nil
+}
+
+func (r *rpcServer) SwapAliases(ctx context.Context, req *pb.SwapAliasesReq) (*pb.Response, error) {
+	if err := r.server.swapAliases(ctx, req.GetAlias(), req.GetOtherAlias()); err != nil {
+		return nil, statusError(err)
+	}
+	log.Info().Str("alias", req.GetAlias()).Str("other", req.GetOtherAlias()).Msg("aliases swapped")
+	return okResponse(), nil
+}
+
+func (r *rpcServer) DeleteAlias(ctx context.Context, req *pb.AliasName) (*pb.Response, error) {
+	if err := r.server.deleteAlias(ctx, req.GetAlias()); err != nil {
+		return nil, statusError(err)
+	}
+	log.Info().Str("alias", req.GetAlias()).Msg("alias deleted")
+	return okResponse(), nil
+}
+
+func (r *rpcServer) ListAliases(ctx context.Context, _ *emptypb.Empty) (*pb.AliasList, error) {
+	list := &pb.AliasList{}
+	for _, alias := range r.server.listAliases(ctx) {
+		if auth.Allowed(ctx, alias.GetTenant(), alias.GetAlias(), auth.Read) {
+			list.Aliases = append(list.Aliases, alias)
+		}
+	}
+	return list, nil
+}
+
+// write and remove acknowledge a versioned write that lost to a newer one
+// without touching the point, the newer write is what the point ends up with
+// anyway. Applied writes wake the relay that publishes them to the peers.
+func (r *rpcServer) write(ctx context.Context, req *pb.ModifyDataset, mode writeMode) error {
+	col, err := r.server.getCollection(ctx, req.GetCollectionName())
+	if err != nil {
+		return err
+	}
+	w, err := col.newPointWrite(req)
+	if err != nil {
+		return err
+	}
+	_, applied, err := col.set(ctx, w, mode)
+	if applied {
+		r.server.wakeRelay()
+	}
+	return err
+}
+
+func (r *rpcServer) remove(ctx context.Context, req *pb.DeleteDataset) error {
+	col, err := r.server.getCollection(ctx, req.GetCollectionName())
+	if err != nil {
+		return err
+	}
+	id, err := parsePointId(req.GetId())
+	if err != nil {
+		return err
+	}
+	_, applied, err := col.remove(ctx, id, requestVersion(req.GetVersion(), req.GetOrigin()), removal{
+		cond:    requestPrecondition(req.GetPrecondition()),
+		ordered: req.GetInternal(),
+		handoff: req.GetHandoff(),
+	})
+	if applied {
+		r.server.wakeRelay()
+	}
+	return err
+}
+
+func (r *rpcServer) patch(ctx context.Context, req *pb.PatchMetadataReq) error {
+	col, err := r.server.getCollection(ctx, req.GetCollectionName())
+	if err != nil {
+		return err
+	}
+	id, err := parsePointId(req.GetId())
+	if err != nil {
+		return err
+	}
+	p, err := newMetadataPatch(req)
+	if err != nil {
+		return err
+	}
+	_, applied, err := col.patch(ctx, id, p, requestVersion(req.GetVersion(), req.GetOrigin()))
+	if applied {
+		r.server.wakeRelay()
+	}
+	return err
+}
+
+func (r *rpcServer) modify(ctx context.Context, req *pb.ModifyDataset, mode writeMode) *pb.Response {
+	return errOrOk(r.write(ctx, req, mode))
+}
+
+func (r *rpcServer) delete(ctx context.Context, req *pb.DeleteDataset) *pb.Response {
+	return errOrOk(r.remove(ctx, req))
+}
+
+func (r *rpcServer) Insert(ctx context.Context, req *pb.ModifyDataset) (*pb.Response, error) {
+	return respond(r.write(ctx, req, writeInsert))
+}
+
+func (r *rpcServer) Update(ctx context.Context, req *pb.ModifyDataset) (*pb.Response, error) {
+	return respond(r.write(ctx, req, writeUpdate))
+}
+
+func (r *rpcServer) PatchMetadata(ctx context.Context, req *pb.PatchMetadataReq) (*pb.Response, error) {
+	return respond(r.patch(ctx, req))
+}
+
+func (r *rpcServer) Delete(ctx context.Context, req *pb.DeleteDataset) (*pb.Response, error) {
+	return respond(r.remove(ctx, req))
+}
+
+// serveStream answers every received message with its own response, a failed
+// item does not abort the rest of the stream.
+func serveStream[T any](stream grpc.BidiStreamingServer[T, pb.Response], handle func(context.Context, *T) *pb.Response) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(handle(stream.Context(), req)); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *rpcServer) BatchInsert(stream grpc.BidiStreamingServer[pb.ModifyDataset, pb.Response]) error {
+	return serveStream(stream, func(ctx context.Context, req *pb.ModifyDataset) *pb.Response {
+		return r.modify(ctx, req, writeInsert)
+	})
+}
+
+func (r *rpcServer) BatchUpdate(stream grpc.BidiStreamingServer[pb.ModifyDataset, pb.Response]) error {
+	return serveStream(stream, func(ctx context.Context, req *pb.ModifyDataset) *pb.Response {
+		return r.modify(ctx, req, writeUpdate)
+	})
+}
+
+func (r *rpcServer) BatchDelete(stream grpc.BidiStreamingServer[pb.DeleteDataset, pb.Response]) error {
+	return serveStream(stream, r.delete)
+}
+
+// DataLoader upserts every point it receives, it is the sync path between
+// nodes so the point may or may not exist already.
+func (r *rpcServer) DataLoader(stream grpc.BidiStreamingServer[pb.ModifyDataset, pb.Response]) error {
+	return serveStream(stream, func(ctx context.Context, req *pb.ModifyDataset) *pb.Response {
+		return r.modify(ctx, req, writeUpsert)
+	})
+}
+
+// withSearchOptions fills the fields of a search request that are not set
+// from its deprecated search_options, the JSON of those fields.
+func withSearchOptions(req *pb.SearchReq) (*pb.SearchReq, error) {
+	if len(req.GetSearchOptions()) == 0 {
+		return req, nil
+	}
+	options := &pb.SearchReq{}
+	if err := protojson.Unmarshal(req.GetSearchOptions(), options); err != nil {
+		return nil, fmt.Errorf("%w: search_options: %v", ErrInvalidRequest, err)
+	}
+	req = proto.Clone(req).(*pb.SearchReq)
+	if req.Filter == nil {
+		req.Filter = options.GetFilter()
+	}
+	if req.TimeBudgetMs == 0 {
+		req.TimeBudgetMs = options.GetTimeBudgetMs()
+	}
+	if req.EfSearch == 0 {
+		req.EfSearch = options.GetEfSearch()
+	}
+	return req, nil
+}
+
+// requestQuery turns a search request into a query on col.
+func requestQuery(col *collection, req *pb.SearchReq) (searchQuery, error) {
+	req, err := withSearchOptions(req)
+	if err != nil {
+		return searchQuery{}, err
+	}
+	metadata, err := metadataToDocument(req.GetMetadata())
+	if err != nil {
+		return searchQuery{}, err
+	}
+	filter, err := col.searchFilter(metadata, req.GetFilter())
+	if err != nil {
+		return searchQuery{}, err
+	}
+	query := searchQuery{
+		vector:   req.GetVector(),
+		topK:     int(req.GetTopK()),
+		minScore: req.GetMinScore(),
+		filter:   filter,
+		efSearch: int(req.GetEfSearch()),
+	}
+	if budget := req.GetTimeBudgetMs(); budget > 0 {
+		query.deadline = time.Now().Add(time.Duration(budget) * time.Millisecond)
+	}
+	return query, nil
+}
+
+func (r *rpcServer) Search(ctx context.Context, req *pb.SearchReq) (*pb.SearchResponse, error) {
+	startTime := time.Now()
+	col, err := r.server.getCollection(ctx, req.GetCollectionName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	query, err := requestQuery(col, req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	rows, partial, err := col.search(ctx, query)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.SearchResponse{
+		Result:   true,
+		Response: rows,
+		Latency:  time.Since(startTime).String(),
+		Partial:  partial,
+	}, nil
+}
+
+func (r *rpcServer) BatchSearch(ctx context.Context, req *pb.BatchSearchReq) (*pb.BatchSearchResponse, error) {
+	startTime := time.Now()
+	col, err := r.server.getCollection(ctx, req.GetCollectionName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	responses := make([]*pb.SearchResponse, len(req.GetSearches()))
+	queries := make([]searchQuery, 0, len(req.GetSearches()))
+	// positions of the queries in the request
+	positions := make([]int, 0, len(req.GetSearches()))
+	for i, search := range req.GetSearches() {
+		if name := search.GetCollectionName(); name != "" && name != req.GetCollectionName() && name != col.name {
+			responses[i] = searchErrResponse(fmt.Errorf("%w: search on collection %s in a batch on %s", ErrInvalidRequest, name, col.name))
+			continue
+		}
+		query, err := requestQuery(col, search)
+		if err != nil {
+			responses[i] = searchErrResponse(err)
+			continue
+		}
+		queries = append(queries, query)
+		positions = append(positions, i)
+	}
+	for j, outcome := range col.batchSearch(ctx, queries) {
+		if outcome.err != nil {
+			responses[positions[j]] = searchErrResponse(outcome.err)
+			continue
+		}
+		responses[positions[j]] = &pb.SearchResponse{Result: true, Response: outcome.rows, Partial: outcome.partial}
+	}
+	return &pb.BatchSearchResponse{
+		Result:    true,
+		Responses: responses,
+		Latency:   time.Since(startTime).String(),
+	}, nil
+}
+
+func (r *rpcServer) GetPoints(ctx context.Context, req *pb.PointIds) (*pb.PointsResponse, error) {
+	col, err := r.server.getCollection(ctx, req.GetCollectionName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	ids := make([]uuid.UUID, len(req.GetIds()))
+	for i, id := range req.GetIds() {
+		if ids[i], err = parsePointId(id); err != nil {
+			return nil, statusError(err)
+		}
+	}
+	rows, err := col.getPoints(ids, requestFields(req.WithVector, req.WithMetadata))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.PointsResponse{Result: true, Points: rows}, nil
+}
+
+func (r *rpcServer) Scroll(ctx context.Context, req *pb.ScrollReq) (*pb.ScrollResponse, error) {
+	col, err := r.server.getCollection(ctx, req.GetCollectionName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	filter, err := col.searchFilter(nil, req.GetFilter())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	limit := int(min(req.GetLimit(), maxScrollLimit))
+	if limit <= 0 {
+		limit = defaultScrollLimit
+	}
+	rows, next, err := col.scroll(filter, req.GetCursor(), limit, requestFields(req.WithVector, req.WithMetadata))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.ScrollResponse{Result: true, Points: rows, NextCursor: next}, nil
+}
+
+func (r *rpcServer) Count(ctx context.Context, req *pb.CountReq) (*pb.CountResponse, error) {
+	col, err := r.server.getCollection(ctx, req.GetCollectionName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	filter, err := col.searchFilter(nil, req.GetFilter())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	count, err := col.count(filter)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.CountResponse{Result: true, Count: count}, nil
+}
+
+// ListPointIds streams the point ids of a collection in chunks, it is how the
+// gateway finds the points to move while resharding.
+func (r *rpcServer) ListPointIds(req *pb.CollectionName, stream grpc.ServerStreamingServer[pb.PointIds]) error {
+	col, err := r.server.getCollection(stream.Context(), req.GetCollectionName())
+	if err != nil {
+		return statusError(err)
+	}
+	ids, err := col.pointIds()
+	if err != nil {
+		return statusError(err)
+	}
+	for start := 0; start < len(ids); start += pointIdsChunk {
+		chunk := &pb.PointIds{CollectionName: req.GetCollectionName()}
+		for _, id := range ids[start:min(start+pointIdsChunk, len(ids))] {
+			chunk.Ids = append(chunk.Ids, id.String())
+		}
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// searchErrResponse is the answer to a failed search of a batch.
+func searchErrResponse(err error) *pb.SearchResponse {
+	resp := errResponse(err)
+	return &pb.SearchResponse{
+		Result:       resp.Result,
+		ErrorMessage: resp.ErrorMessage,
+		ErrorCode:    resp.ErrorCode,
+	}
+}
+
+func (r *rpcServer) GetMerkleTree(ctx context.Context, req *pb.MerkleReq) (*pb.MerkleTree, error) {
+	col, err := r.server.getCollection(ctx, req.GetCollectionName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	tree, err := col.merkleTree()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	hashes, err := tree.Level(int(req.GetLevel()))
+	if err != nil {
+		return nil, statusError(fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+	}
+	return &pb.MerkleTree{CollectionName: col.name, Level: req.GetLevel(), Hashes: hashes}, nil
+}
+
+// CheckDivergence reports how far each collection is from its copies on the
+// peers. A failing peer is reported in its own entry.
+func (r *rpcServer) CheckDivergence(ctx context.Context, req *pb.DivergenceReq) (*pb.DivergenceReport, error) {
+	divergences, err := r.server.checkDivergence(ctx, req.GetCollectionName(), req.GetRepair())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.DivergenceReport{Result: true, Divergences: divergences}, nil
+}
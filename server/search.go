@@ -0,0 +1,634 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/RoaringBitmap/roaring/roaring64"
+	"github.com/google/uuid"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/cache"
+	"github.com/sjy-dv/nnv/pkg/conversion"
+	"github.com/sjy-dv/nnv/pkg/index"
+	"github.com/sjy-dv/nnv/pkg/models"
+	"github.com/sjy-dv/nnv/pkg/pointstore"
+	"github.com/sjy-dv/nnv/storage"
+)
+
+const (
+	defaultTopK = 10
+	// bounds the ef_search of a request like the HTTP API does
+	maxEfSearch = 1000
+)
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// stops a scan once a page of points is complete
+var errPageFull = errors.New("page full")
+
+type searchQuery struct {
+	vector   []float32
+	topK     int
+	minScore float32
+	filter   *models.Query
+	// the vector search answers with what it found by then, zero waits
+	// for the full answer
+	deadline time.Time
+	// candidates kept by an HNSW search, zero uses the index default
+	efSearch int
+}
+
+// metadataQuery turns the metadata map of a search request into an equality
+// query over the inverted indexes, all entries have to match.
+func (c *collection) metadataQuery(metadata map[string]any) (*models.Query, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	fields := make([]string, 0, len(metadata))
+	for field := range metadata {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	queries := make([]models.Query, 0, len(fields))
+	for _, field := range fields {
+		opts, ok := c.schema[field]
+		if !ok || isVectorIndexType(opts.Type) {
+			return nil, fmt.Errorf("%w: field %s is not indexed", ErrInvalidFilter, field)
+		}
+		value := metadata[field]
+		q := models.Query{Property: field}
+		var err error
+		switch opts.Type {
+		case models.IndexTypeString:
+			var v string
+			v, err = asString(value)
+			q.String = &models.SearchStringOptions{Value: v, Operator: models.OperatorEquals}
+		case models.IndexTypeInteger:
+			var v int64
+			v, err = asInteger(value)
+			q.Integer = &models.SearchIntegerOptions{Value: v, Operator: models.OperatorEquals}
+		case models.IndexTypeFloat:
+			var v float64
+			v, err = asFloat(value)
+			q.Float = &models.SearchFloatOptions{Value: v, Operator: models.OperatorEquals}
+		case models.IndexTypeStringArray:
+			var v []string
+			v, err = asStringArray(value)
+			q.StringArray = &models.SearchStringArrayOptions{Value: v, Operator: models.OperatorContainsAll}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s: %v", ErrInvalidFilter, field, err)
+		}
+		queries = append(queries, q)
+	}
+	if len(queries) == 1 {
+		return &queries[0], nil
+	}
+	return &models.Query{Property: "_and", And: queries}, nil
+}
+
+var filterOperators = map[pb.FilterOperator]string{
+	pb.FilterOperator_FILTER_EQUALS:                 models.OperatorEquals,
+	pb.FilterOperator_FILTER_NOT_EQUALS:             models.OperatorNotEquals,
+	pb.FilterOperator_FILTER_STARTS_WITH:            models.OperatorStartsWith,
+	pb.FilterOperator_FILTER_GREATER_THAN:           models.OperatorGreaterThan,
+	pb.FilterOperator_FILTER_GREATER_THAN_OR_EQUALS: models.OperatorGreaterOrEq,
+	pb.FilterOperator_FILTER_LESS_THAN:              models.OperatorLessThan,
+	pb.FilterOperator_FILTER_LESS_THAN_OR_EQUALS:    models.OperatorLessOrEq,
+	pb.FilterOperator_FILTER_IN_RANGE:               models.OperatorInRange,
+	pb.FilterOperator_FILTER_CONTAINS_ALL:           models.OperatorContainsAll,
+	pb.FilterOperator_FILTER_CONTAINS_ANY:           models.OperatorContainsAny,
+}
+
+func filterOperator(op pb.FilterOperator) (string, error) {
+	operator, ok := filterOperators[op]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown operator %v", ErrInvalidFilter, op)
+	}
+	return operator, nil
+}
+
+// filterToQuery converts the filter of a search request into the query tree
+// of the inverted indexes.
+func filterToQuery(f *pb.Filter) (models.Query, error) {
+	q := models.Query{Property: f.GetProperty()}
+	for _, sub := range f.GetAnd() {
+		subQuery, err := filterToQuery(sub)
+		if err != nil {
+			return q, err
+		}
+		q.And = append(q.And, subQuery)
+	}
+	for _, sub := range f.GetOr() {
+		subQuery, err := filterToQuery(sub)
+		if err != nil {
+			return q, err
+		}
+		q.Or = append(q.Or, subQuery)
+	}
+	var err error
+	switch cond := f.GetCondition().(type) {
+	case *pb.Filter_StringCondition:
+		q.String = &models.SearchStringOptions{
+			Value:    cond.StringCondition.GetValue(),
+			EndValue: cond.StringCondition.GetEndValue(),
+		}
+		q.String.Operator, err = filterOperator(cond.StringCondition.GetOperator())
+	case *pb.Filter_IntegerCondition:
+		q.Integer = &models.SearchIntegerOptions{
+			Value:    cond.IntegerCondition.GetValue(),
+			EndValue: cond.IntegerCondition.GetEndValue(),
+		}
+		q.Integer.Operator, err = filterOperator(cond.IntegerCondition.GetOperator())
+	case *pb.Filter_FloatCondition:
+		q.Float = &models.SearchFloatOptions{
+			Value:    cond.FloatCondition.GetValue(),
+			EndValue: cond.FloatCondition.GetEndValue(),
+		}
+		q.Float.Operator, err = filterOperator(cond.FloatCondition.GetOperator())
+	case *pb.Filter_StringArrayCondition:
+		q.StringArray = &models.SearchStringArrayOptions{Value: cond.StringArrayCondition.GetValues()}
+		q.StringArray.Operator, err = filterOperator(cond.StringArrayCondition.GetOperator())
+	}
+	return q, err
+}
+
+// searchFilter combines the metadata equality query and the typed filter of
+// a search request, nil when the request has neither.
+func (c *collection) searchFilter(metadata map[string]any, f *pb.Filter) (*models.Query, error) {
+	metadataQuery, err := c.metadataQuery(metadata)
+	if err != nil || f == nil {
+		return metadataQuery, err
+	}
+	q, err := filterToQuery(f)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.Validate(c.schema); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+	if metadataQuery == nil {
+		return &q, nil
+	}
+	return &models.Query{Property: "_and", And: []models.Query{*metadataQuery, q}}, nil
+}
+
+// evaluateQuery resolves a filter query to the set of matching node ids.
+func (c *collection) evaluateQuery(sc storage.StorageCoordinator, q models.Query) (*roaring64.Bitmap, error) {
+	switch q.Property {
+	case "_and", "_or":
+		subQueries := q.And
+		if q.Property == "_or" {
+			subQueries = q.Or
+		}
+		if len(subQueries) == 0 {
+			return nil, fmt.Errorf("%w: empty %s", ErrInvalidFilter, q.Property)
+		}
+		sets := make([]*roaring64.Bitmap, len(subQueries))
+		for i, subQuery := range subQueries {
+			set, err := c.evaluateQuery(sc, subQuery)
+			if err != nil {
+				return nil, err
+			}
+			sets[i] = set
+		}
+		if q.Property == "_and" {
+			return roaring64.FastAnd(sets...), nil
+		}
+		return roaring64.FastOr(sets...), nil
+	case "_id":
+		points, err := sc.Get(pointsStorage)
+		if err != nil {
+			return nil, err
+		}
+		var ids []string
+		if q.String != nil {
+			ids = []string{q.String.Value}
+		} else if q.StringArray != nil {
+			ids = q.StringArray.Value
+		}
+		set := roaring64.New()
+		for _, id := range ids {
+			pointId, err := uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+			}
+			nodeId, err := pointstore.GetPointNodeIdByUUID(points, pointId)
+			if errors.Is(err, pointstore.ErrPointDoesNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			set.Add(nodeId)
+		}
+		return set, nil
+	}
+	opts, ok := c.schema[q.Property]
+	if !ok || isVectorIndexType(opts.Type) {
+		return nil, fmt.Errorf("%w: property %s is not indexed", ErrInvalidFilter, q.Property)
+	}
+	bucket, err := sc.Get(invertedStorageName(q.Property))
+	if err != nil {
+		return nil, err
+	}
+	var set *roaring64.Bitmap
+	switch {
+	case opts.Type == models.IndexTypeString && q.String != nil:
+		set, err = index.NewIndexInverted[string](bucket).Search(q.String.Value, q.String.EndValue, q.String.Operator)
+	case opts.Type == models.IndexTypeInteger && q.Integer != nil:
+		set, err = index.NewIndexInverted[int64](bucket).Search(q.Integer.Value, q.Integer.EndValue, q.Integer.Operator)
+	case opts.Type == models.IndexTypeFloat && q.Float != nil:
+		set, err = index.NewIndexInverted[float64](bucket).Search(q.Float.Value, q.Float.EndValue, q.Float.Operator)
+	case opts.Type == models.IndexTypeStringArray && q.StringArray != nil:
+		set, err = index.NewIndexInvertedArray[string](bucket).Search(q.StringArray.Value, q.StringArray.Operator)
+	default:
+		return nil, fmt.Errorf("%w: %s query options not provided for property %s", ErrInvalidFilter, opts.Type, q.Property)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+	if set == nil {
+		set = roaring64.New()
+	}
+	return set, nil
+}
+
+// similarity maps a euclidean distance onto a score where higher is better,
+// the same scale the HNSW graph uses internally.
+func similarity(distance float32) float32 {
+	return 1 / (1 + distance)
+}
+
+// search answers a query, partial is set when its deadline cut the vector
+// search short.
+func (c *collection) search(ctx context.Context, query searchQuery) (rows []*pb.Row, partial bool, err error) {
+	if err := c.checkSearch(&query); err != nil {
+		return nil, false, err
+	}
+	err = c.read(func(sc storage.StorageCoordinator, txn *cache.Transaction) error {
+		var filter *roaring64.Bitmap
+		if query.filter != nil {
+			var err error
+			if filter, err = c.evaluateQuery(sc, *query.filter); err != nil {
+				return err
+			}
+		}
+		var err error
+		rows, partial, err = c.searchIndex(ctx, sc, txn, query, filter)
+		return err
+	})
+	return rows, partial, err
+}
+
+// checkSearch validates a query and fills in its defaults.
+func (c *collection) checkSearch(query *searchQuery) error {
+	if len(query.vector) != int(c.config.GetDimension()) {
+		return fmt.Errorf("%w: expected %d got %d", ErrDimensionMismatch, c.config.GetDimension(), len(query.vector))
+	}
+	if query.topK <= 0 {
+		query.topK = defaultTopK
+	}
+	if query.efSearch > maxEfSearch {
+		return fmt.Errorf("%w: ef_search %d exceeds %d", ErrInvalidRequest, query.efSearch, maxEfSearch)
+	}
+	return nil
+}
+
+// searchIndex runs a query on the vector index, a non nil filter holds the
+// node ids the query is limited to.
+func (c *collection) searchIndex(ctx context.Context, sc storage.StorageCoordinator, txn *cache.Transaction, query searchQuery, filter *roaring64.Bitmap) (rows []*pb.Row, partial bool, err error) {
+	err = c.withVectorIndex(txn, sc, func(vi vectorIndex) error {
+		var err error
+		rows, partial, err = c.searchWith(ctx, sc, vi, query, filter)
+		return err
+	})
+	return rows, partial, err
+}
+
+// searchWith runs a query on a vector index the caller holds and reads the
+// rows of its results from sc. Searches only read the index, so several may
+// share it.
+func (c *collection) searchWith(ctx context.Context, sc storage.StorageCoordinator, vi vectorIndex, query searchQuery, filter *roaring64.Bitmap) ([]*pb.Row, bool, error) {
+	rows := make([]*pb.Row, 0, query.topK)
+	meta, err := sc.Get(collectionStorage)
+	if err != nil {
+		return nil, false, err
+	}
+	if getCounter(meta, pointCountKey) == 0 || (filter != nil && filter.IsEmpty()) {
+		return rows, false, nil
+	}
+	options := models.SearchVectorFlatOptions{
+		Vector:   query.vector,
+		Operator: "near",
+		Limit:    query.topK,
+		EfSearch: query.efSearch,
+		Deadline: query.deadline,
+	}
+	_, results, err := vi.Search(ctx, options, filter)
+	partial := errors.Is(err, models.ErrPartialResults)
+	if err != nil && !partial {
+		return nil, false, fmt.Errorf("vector search failed: %w", err)
+	}
+	// ---------------------------
+	points, err := sc.Get(pointsStorage)
+	if err != nil {
+		return nil, false, err
+	}
+	vectors, err := sc.Get(vectorsStorage)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, res := range results {
+		score := similarity(*res.Distance)
+		if score < query.minScore {
+			continue
+		}
+		row, err := c.row(points, vectors, res.NodeId, allFields)
+		if err != nil {
+			return nil, false, err
+		}
+		row.Score = score
+		rows = append(rows, row)
+	}
+	return rows, partial, nil
+}
+
+// searchOutcome is the answer to one query of a batch.
+type searchOutcome struct {
+	rows    []*pb.Row
+	partial bool
+	err     error
+}
+
+// batchSearch runs queries in parallel and answers them in their order.
+// Writes wait until the batch is done, so every query sees the same points
+// and a filter shared by several queries is evaluated once. The vector index
+// is taken once for the whole batch and its workers search it side by side.
+func (c *collection) batchSearch(ctx context.Context, queries []searchQuery) []searchOutcome {
+	outcomes := make([]searchOutcome, len(queries))
+	c.txMu.RLock()
+	defer c.txMu.RUnlock()
+
+	filters := make(map[string]*roaring64.Bitmap)
+	filterErrs := make(map[string]error)
+	keys := make([]string, len(queries))
+	err := c.readLocked(func(sc storage.StorageCoordinator, txn *cache.Transaction) error {
+		for i := range queries {
+			if outcomes[i].err = c.checkSearch(&queries[i]); outcomes[i].err != nil || queries[i].filter == nil {
+				continue
+			}
+			key, err := json.Marshal(queries[i].filter)
+			if err != nil {
+				return err
+			}
+			keys[i] = string(key)
+			if _, ok := filters[keys[i]]; ok {
+				continue
+			}
+			filters[keys[i]], filterErrs[keys[i]] = c.evaluateQuery(sc, *queries[i].filter)
+		}
+		return c.withVectorIndex(txn, sc, func(vi vectorIndex) error {
+			c.searchConcurrently(ctx, sc, vi, queries, outcomes, func(i int) (*roaring64.Bitmap, error) {
+				return filters[keys[i]], filterErrs[keys[i]]
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		for i := range outcomes {
+			outcomes[i].err = err
+		}
+	}
+	return outcomes
+}
+
+// searchConcurrently answers the queries that have no outcome yet on up to
+// GOMAXPROCS workers sharing vi and the read transaction sc. A read
+// transaction only reads, and a second one would wait on the first with the
+// in-memory storage.
+func (c *collection) searchConcurrently(ctx context.Context, sc storage.StorageCoordinator, vi vectorIndex, queries []searchQuery, outcomes []searchOutcome, filter func(i int) (*roaring64.Bitmap, error)) {
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(queries)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := int(next.Add(1) - 1); i < len(queries); i = int(next.Add(1) - 1) {
+				if outcomes[i].err != nil {
+					continue
+				}
+				set, err := filter(i)
+				if err == nil {
+					err = ctx.Err()
+				}
+				if err == nil {
+					outcomes[i].rows, outcomes[i].partial, err = c.searchWith(ctx, sc, vi, queries[i], set)
+				}
+				outcomes[i].err = err
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// rowFields picks the parts of a point a row carries besides its id and
+// version.
+type rowFields struct {
+	vector   bool
+	metadata bool
+}
+
+var allFields = rowFields{vector: true, metadata: true}
+
+// requestFields includes what a request leaves unset.
+func requestFields(withVector, withMetadata *bool) rowFields {
+	return rowFields{
+		vector:   withVector == nil || *withVector,
+		metadata: withMetadata == nil || *withMetadata,
+	}
+}
+
+func (c *collection) row(points, vectors storage.Storage, nodeId uint64, fields rowFields) (*pb.Row, error) {
+	point, err := pointstore.GetPointByNodeId(points, nodeId, fields.metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get point %d: %w", nodeId, err)
+	}
+	row := &pb.Row{
+		Id:      point.Id.String(),
+		Version: uint64(point.Version.Timestamp),
+		Origin:  point.Version.Origin,
+	}
+	if fields.metadata {
+		doc, err := decodeDocument(point.Data)
+		if err != nil {
+			return nil, err
+		}
+		if row.Metadata, err = documentToMetadata(doc); err != nil {
+			return nil, err
+		}
+	}
+	if fields.vector {
+		if vectorBytes := vectors.Get(conversion.NodeKey(nodeId, 'v')); vectorBytes != nil {
+			row.Vector = conversion.BytesToFloat32(vectorBytes)
+		}
+	}
+	return row, nil
+}
+
+// getPoints returns the rows of the given points, ids that do not exist are
+// skipped.
+func (c *collection) getPoints(ids []uuid.UUID, fields rowFields) ([]*pb.Row, error) {
+	rows := make([]*pb.Row, 0, len(ids))
+	err := c.db.Read(func(sc storage.StorageCoordinator) error {
+		points, err := sc.Get(pointsStorage)
+		if err != nil {
+			return err
+		}
+		vectors, err := sc.Get(vectorsStorage)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			nodeId, err := pointstore.GetPointNodeIdByUUID(points, id)
+			if errors.Is(err, pointstore.ErrPointDoesNotExist) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			row, err := c.row(points, vectors, nodeId, fields)
+			if err != nil {
+				return err
+			}
+			rows = append(rows, row)
+		}
+		return nil
+	})
+	return rows, err
+}
+
+// pointIds lists the ids of every point in the collection.
+func (c *collection) pointIds() ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := c.db.Read(func(sc storage.StorageCoordinator) error {
+		points, err := sc.Get(pointsStorage)
+		if err != nil {
+			return err
+		}
+		return points.PrefixScan([]byte{'p'}, func(k, v []byte) error {
+			if len(k) != 18 || k[17] != 'i' {
+				return nil
+			}
+			id, err := uuid.FromBytes(k[1:17])
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+			return nil
+		})
+	})
+	return ids, err
+}
+
+// scroll returns up to limit points after the cursor in id order, only the
+// ones matching the filter if there is one. The cursor of the next page is
+// the id of the last point, empty once no point is left.
+func (c *collection) scroll(filter *models.Query, cursor string, limit int, fields rowFields) ([]*pb.Row, string, error) {
+	start := []byte{'p'}
+	if cursor != "" {
+		id, err := parsePointId(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		start = pointstore.PointKey(id, 'i')
+	}
+	rows := make([]*pb.Row, 0, limit)
+	more := false
+	err := c.db.Read(func(sc storage.StorageCoordinator) error {
+		var matching *roaring64.Bitmap
+		if filter != nil {
+			var err error
+			if matching, err = c.evaluateQuery(sc, *filter); err != nil {
+				return err
+			}
+		}
+		points, err := sc.Get(pointsStorage)
+		if err != nil {
+			return err
+		}
+		vectors, err := sc.Get(vectorsStorage)
+		if err != nil {
+			return err
+		}
+		err = points.RangeScan(start, []byte{'q'}, false, func(k, v []byte) error {
+			if len(k) != 18 || k[17] != 'i' {
+				return nil
+			}
+			nodeId := conversion.BytesToUint64(v)
+			if matching != nil && !matching.Contains(nodeId) {
+				return nil
+			}
+			if len(rows) == limit {
+				more = true
+				return errPageFull
+			}
+			row, err := c.row(points, vectors, nodeId, fields)
+			if err != nil {
+				return err
+			}
+			rows = append(rows, row)
+			return nil
+		})
+		if errors.Is(err, errPageFull) {
+			return nil
+		}
+		return err
+	})
+	if err != nil || !more {
+		return rows, "", err
+	}
+	return rows, rows[len(rows)-1].GetId(), nil
+}
+
+// count returns the number of points, only the ones matching the filter if
+// there is one.
+func (c *collection) count(filter *models.Query) (uint64, error) {
+	if filter == nil {
+		return c.pointCount()
+	}
+	var count uint64
+	err := c.db.Read(func(sc storage.StorageCoordinator) error {
+		matching, err := c.evaluateQuery(sc, *filter)
+		if err != nil {
+			return err
+		}
+		count = matching.GetCardinality()
+		return nil
+	})
+	return count, err
+}
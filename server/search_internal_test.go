@@ -0,0 +1,168 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/RoaringBitmap/roaring/roaring64"
+	"github.com/google/uuid"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/cache"
+	"github.com/sjy-dv/nnv/pkg/hlc"
+	"github.com/sjy-dv/nnv/pkg/models"
+	"github.com/sjy-dv/nnv/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// slowIndex holds every search until two of them run at the same time.
+type slowIndex struct {
+	vectorIndex
+	inside  atomic.Int32
+	overlap chan struct{}
+	once    sync.Once
+}
+
+func (s *slowIndex) Search(ctx context.Context, options models.SearchVectorFlatOptions, filter *roaring64.Bitmap) (*roaring64.Bitmap, []models.SearchResult, error) {
+	if s.inside.Add(1) >= 2 {
+		s.once.Do(func() { close(s.overlap) })
+	}
+	defer s.inside.Add(-1)
+	select {
+	case <-s.overlap:
+	case <-time.After(5 * time.Second):
+	}
+	return s.vectorIndex.Search(ctx, options, filter)
+}
+
+// lateIndex searches only once the deadline of a search has passed.
+type lateIndex struct {
+	vectorIndex
+}
+
+func (l lateIndex) Search(ctx context.Context, options models.SearchVectorFlatOptions, filter *roaring64.Bitmap) (*roaring64.Bitmap, []models.SearchResult, error) {
+	if !options.Deadline.IsZero() {
+		time.Sleep(time.Until(options.Deadline))
+	}
+	return l.vectorIndex.Search(ctx, options, filter)
+}
+
+// swapIndex replaces the cached vector index of c with what wrap makes of it.
+func swapIndex(t *testing.T, c *collection, wrap func(vectorIndex) vectorIndex) {
+	t.Helper()
+	var wrapped vectorIndex
+	err := c.read(func(sc storage.StorageCoordinator, txn *cache.Transaction) error {
+		return c.withVectorIndex(txn, sc, func(vi vectorIndex) error {
+			wrapped = wrap(vi)
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	c.cacheManager.Release(c.vectorCacheName())
+	txn := c.cacheManager.NewTransaction()
+	require.NoError(t, txn.With(c.vectorCacheName(), false, func() (cache.Cachable, error) {
+		return wrapped, nil
+	}, func(cache.Cachable) error { return nil }))
+	txn.Commit(false)
+}
+
+func TestBatchSearchOverlaps(t *testing.T) {
+	if runtime.GOMAXPROCS(0) < 2 {
+		defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(2))
+	}
+	ctx := context.Background()
+	c, err := createCollection(filepath.Join(t.TempDir(), "docs.cdat"), false, cache.NewManager(-1), &pb.Collection{
+		CollectionName: "docs",
+		Dimension:      2,
+	})
+	require.NoError(t, err)
+	defer c.close()
+	c.clock = &clock{Clock: hlc.NewClock(), origin: "test"}
+	for i := 0; i < 10; i++ {
+		w, err := c.newPointWrite(&pb.ModifyDataset{Id: uuid.NewString(), Vector: []float32{float32(i), float32(i)}})
+		require.NoError(t, err)
+		_, _, err = c.set(ctx, w, writeUpsert)
+		require.NoError(t, err)
+	}
+
+	slow := &slowIndex{overlap: make(chan struct{})}
+	swapIndex(t, c, func(vi vectorIndex) vectorIndex {
+		slow.vectorIndex = vi
+		return slow
+	})
+
+	queries := []searchQuery{
+		{vector: []float32{1, 1}, topK: 2},
+		{vector: []float32{7, 7}, topK: 2},
+	}
+	start := time.Now()
+	outcomes := c.batchSearch(ctx, queries)
+	for _, outcome := range outcomes {
+		require.NoError(t, outcome.err)
+		require.Len(t, outcome.rows, 2)
+	}
+	select {
+	case <-slow.overlap:
+	default:
+		t.Fatalf("the queries of the batch ran one after the other in %s", time.Since(start))
+	}
+}
+
+func TestSearchTimeBudget(t *testing.T) {
+	s, err := New(Config{DataDir: t.TempDir(), Stable: true})
+	require.NoError(t, err)
+	defer s.Close()
+	r := &rpcServer{server: s}
+	ctx := context.Background()
+	for _, index := range []pb.VectorIndex{pb.VectorIndex_FLAT_INDEX, pb.VectorIndex_HNSW_INDEX} {
+		name := index.String()
+		_, err := r.CreateCollection(ctx, &pb.Collection{CollectionName: name, Dimension: 2, VectorIndex: index})
+		require.NoError(t, err)
+		// more points than the flat index scans between looks at the clock
+		for i := 0; i < 600; i++ {
+			_, err := r.Insert(ctx, &pb.ModifyDataset{Id: uuid.NewString(), CollectionName: name, Vector: []float32{float32(i), float32(i)}})
+			require.NoError(t, err)
+		}
+		c, err := s.getCollection(ctx, name)
+		require.NoError(t, err)
+		swapIndex(t, c, func(vi vectorIndex) vectorIndex { return lateIndex{vi} })
+
+		search := &pb.SearchReq{CollectionName: name, Vector: []float32{300, 300}, TopK: 10}
+		resp, err := r.Search(ctx, search)
+		require.NoError(t, err, name)
+		require.False(t, resp.GetPartial(), name)
+		require.Len(t, resp.GetResponse(), 10, name)
+		// the search outlasts its budget and answers with what it found
+		search.TimeBudgetMs = 20
+		resp, err = r.Search(ctx, search)
+		require.NoError(t, err, name)
+		require.True(t, resp.GetResult(), name)
+		require.True(t, resp.GetPartial(), name)
+
+		batch, err := r.BatchSearch(ctx, &pb.BatchSearchReq{CollectionName: name, Searches: []*pb.SearchReq{search}})
+		require.NoError(t, err, name)
+		require.True(t, batch.GetResponses()[0].GetResult(), name)
+		require.True(t, batch.GetResponses()[0].GetPartial(), name)
+	}
+}
@@ -0,0 +1,448 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/auth"
+	"github.com/sjy-dv/nnv/pkg/cache"
+	"github.com/sjy-dv/nnv/pkg/hlc"
+	"github.com/sjy-dv/nnv/pkg/tenant"
+	"github.com/sjy-dv/nnv/replication"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var (
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrCollectionExists   = errors.New("collection already exists")
+	ErrInvalidCollection  = errors.New("invalid collection")
+)
+
+const nodeIdFile = "node.id"
+
+// collections of the tenants other than the default one live in a directory
+// per tenant below this one
+const tenantsDir = "tenants"
+
+var collectionNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+type Config struct {
+	Host string
+	Port string
+	// port of the HTTP/JSON API on Host, the API is off when empty
+	HTTPPort string
+	DataDir  string
+	// bbolt backed collections when true, compressed in-memory cdat files
+	// flushed on close otherwise
+	Stable bool
+	// identifies the node to its peers, generated and kept in the data
+	// directory when empty
+	NodeId string
+	// publish writes to and apply writes of peers from a JetStream stream,
+	// nil runs the node on its own
+	Replication *replication.Config
+	// replicas of the same data, compared and repaired by anti-entropy
+	Peers []string
+	// pause between anti-entropy rounds, zero only checks on request
+	AntiEntropyInterval time.Duration
+	// age after which tombstones are purged, it has to exceed the time the
+	// replicas take to converge or a purged delete is repaired back to life.
+	// Zero keeps tombstones forever.
+	TombstoneHorizon time.Duration
+	// authenticates and authorizes the callers of both APIs, nil serves
+	// everyone
+	Auth *auth.Config
+	// serves both APIs over TLS when set
+	TLS *tls.Config
+	// limits per tenant on this node, the entry of tenant.All applies to the
+	// tenants without their own
+	Quotas map[string]Quota
+}
+
+func (c Config) Addr() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
+
+// extension of the collection files, one storage layer is opened per
+// collection
+func (c Config) extension() string {
+	if c.Stable {
+		return ".db"
+	}
+	return ".cdat"
+}
+
+// collectionKey names a collection within its tenant.
+type collectionKey struct {
+	tenant string
+	name   string
+}
+
+type Server struct {
+	config      Config
+	collections map[collectionKey]*collection
+	// alias to collection name per tenant
+	aliases map[string]map[string]string
+	// index instances survive across transactions, loading an HNSW graph
+	// scans its whole storage so nothing is ever pruned
+	cacheManager *cache.Manager
+	grpcServer   *grpc.Server
+	httpServer   *http.Server
+	clock        *clock
+	// per tenant, created along with its first collection
+	usage      map[string]*usage
+	replicator *replication.Replicator
+	// wakes the relay of the outboxes
+	relayDue   chan struct{}
+	peers      []*peer
+	closing    chan struct{}
+	closeOnce  sync.Once
+	background sync.WaitGroup
+	mu         sync.RWMutex
+}
+
+// New opens the collections of the data directory. The dial options are used
+// for the peers, whose connections are plaintext unless they say otherwise.
+func New(config Config, dialOpts ...grpc.DialOption) (*Server, error) {
+	if config.DataDir == "" {
+		return nil, errors.New("data directory is required")
+	}
+	if err := os.MkdirAll(config.DataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory %s: %w", config.DataDir, err)
+	}
+	if config.NodeId == "" {
+		nodeId, err := loadNodeId(config.DataDir)
+		if err != nil {
+			return nil, err
+		}
+		config.NodeId = nodeId
+	}
+	s := &Server{
+		config:       config,
+		collections:  make(map[collectionKey]*collection),
+		aliases:      make(map[string]map[string]string),
+		cacheManager: cache.NewManager(-1),
+		clock:        &clock{Clock: hlc.NewClock(), origin: config.NodeId},
+		usage:        make(map[string]*usage),
+		relayDue:     make(chan struct{}, 1),
+		closing:      make(chan struct{}),
+	}
+	if err := s.loadCollections(); err != nil {
+		s.Close()
+		return nil, err
+	}
+	if config.Replication != nil {
+		if err := s.startReplication(*config.Replication); err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.background.Add(1)
+		go s.relay()
+	}
+	dialOpts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, append(tenant.Forward(), dialOpts...)...)
+	peers, err := dialPeers(config.Peers, dialOpts...)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.peers = peers
+	if config.AntiEntropyInterval > 0 && len(peers) > 0 {
+		s.background.Add(1)
+		go s.antiEntropy(config.AntiEntropyInterval)
+	}
+	if config.TombstoneHorizon > 0 {
+		s.background.Add(1)
+		go s.purgeTombstones(config.TombstoneHorizon)
+	}
+	return s, nil
+}
+
+// loadNodeId keeps a generated node id in the data directory so the node
+// resumes its replication consumer after a restart.
+func loadNodeId(dataDir string) (string, error) {
+	path := filepath.Join(dataDir, nodeIdFile)
+	b, err := os.ReadFile(path)
+	if err == nil {
+		return strings.TrimSpace(string(b)), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read node id: %w", err)
+	}
+	nodeId := uuid.NewString()
+	if err := os.WriteFile(path, []byte(nodeId), 0644); err != nil {
+		return "", fmt.Errorf("failed to store node id: %w", err)
+	}
+	return nodeId, nil
+}
+
+func (s *Server) startReplication(config replication.Config) error {
+	ctx := context.Background()
+	r, err := replication.Connect(ctx, config, s.config.NodeId)
+	if err != nil {
+		return err
+	}
+	if err := r.Subscribe(ctx, s.applyMutation); err != nil {
+		r.Close()
+		return err
+	}
+	s.replicator = r
+	log.Info().Str("node", s.config.NodeId).Str("nats", config.URL).Msg("replication started")
+	return nil
+}
+
+// applyMutation is the replication callback. Collections are not replicated,
+// a mutation of a collection this node does not have yet fails and is
+// delivered again until the collection is created.
+func (s *Server) applyMutation(ctx context.Context, m *pb.Mutation) error {
+	col, err := s.getCollection(tenant.NewContext(ctx, m.GetTenant()), m.GetCollectionName())
+	if err != nil {
+		return err
+	}
+	_, err = col.apply(ctx, m)
+	return err
+}
+
+func (s *Server) loadCollections() error {
+	if err := s.loadTenant(tenant.Default); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(filepath.Join(s.config.DataDir, tenantsDir))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to list tenants: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if err := s.loadTenant(entry.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) loadTenant(name string) error {
+	paths, err := filepath.Glob(filepath.Join(s.tenantDir(name), "*"+s.config.extension()))
+	if err != nil {
+		return fmt.Errorf("failed to list collections: %w", err)
+	}
+	for _, path := range paths {
+		col, err := openCollection(path, s.config.Stable, s.cacheManager)
+		if err != nil {
+			return fmt.Errorf("failed to load collection %s: %w", path, err)
+		}
+		col.clock = s.clock
+		col.tenant = name
+		col.replicated = s.config.Replication != nil
+		col.horizon = s.config.TombstoneHorizon
+		col.usage = s.tenantUsage(name)
+		points, bytes, err := col.size()
+		if err != nil {
+			col.close()
+			return fmt.Errorf("failed to load collection %s: %w", path, err)
+		}
+		col.usage.add(points, bytes, true)
+		s.collections[collectionKey{tenant: name, name: col.name}] = col
+		log.Info().Str("tenant", name).Str("collection", col.name).Msg("collection loaded")
+	}
+	return s.loadAliases(name)
+}
+
+func (s *Server) tenantDir(name string) string {
+	if name == tenant.Default {
+		return s.config.DataDir
+	}
+	return filepath.Join(s.config.DataDir, tenantsDir, name)
+}
+
+// singleTenant is the tenant of ctx, which must name one to address a
+// collection.
+func singleTenant(ctx context.Context) (string, error) {
+	name := tenant.FromContext(ctx)
+	if name == tenant.All {
+		return "", fmt.Errorf("%w: collections are addressed within a single tenant", ErrInvalidRequest)
+	}
+	return name, nil
+}
+
+func (s *Server) getCollection(ctx context.Context, name string) (*collection, error) {
+	t, err := singleTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	col, ok := s.lookup(t, name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCollectionNotFound, name)
+	}
+	return col, nil
+}
+
+func (s *Server) createCollection(ctx context.Context, config *pb.Collection) (*collection, error) {
+	t, err := singleTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !collectionNamePattern.MatchString(config.GetCollectionName()) {
+		return nil, fmt.Errorf("%w name %q, expected %s", ErrInvalidCollection, config.GetCollectionName(), collectionNamePattern)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := collectionKey{tenant: t, name: config.GetCollectionName()}
+	if _, ok := s.collections[key]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrCollectionExists, config.GetCollectionName())
+	}
+	if _, ok := s.aliases[t][key.name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrAliasExists, key.name)
+	}
+	if err := s.admitCollection(t); err != nil {
+		return nil, err
+	}
+	dir := s.tenantDir(t)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create tenant directory %s: %w", dir, err)
+	}
+	col, err := createCollection(filepath.Join(dir, key.name+s.config.extension()), s.config.Stable, s.cacheManager, config)
+	if err != nil {
+		return nil, err
+	}
+	col.clock = s.clock
+	col.tenant = t
+	col.replicated = s.config.Replication != nil
+	col.horizon = s.config.TombstoneHorizon
+	col.usage = s.tenantUsage(t)
+	s.collections[key] = col
+	return col, nil
+}
+
+func (s *Server) dropCollection(ctx context.Context, name string) error {
+	t, err := singleTenant(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := collectionKey{tenant: t, name: name}
+	col, ok := s.collections[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrCollectionNotFound, name)
+	}
+	if aliases := s.aliasesOf(key); aliases != "" {
+		return fmt.Errorf("%w: %s is named by %s", ErrCollectionAliased, name, aliases)
+	}
+	points, bytes, err := col.size()
+	if err != nil {
+		return err
+	}
+	delete(s.collections, key)
+	if err := col.drop(); err != nil {
+		return err
+	}
+	col.usage.add(-points, -bytes, true)
+	return nil
+}
+
+// listCollections returns the collections of the tenant of ctx, those of
+// every tenant for tenant.All.
+func (s *Server) listCollections(ctx context.Context) []*collection {
+	return s.tenantCollections(tenant.FromContext(ctx))
+}
+
+func (s *Server) tenantCollections(name string) []*collection {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cols := make([]*collection, 0, len(s.collections))
+	for key, col := range s.collections {
+		if name == tenant.All || key.tenant == name {
+			cols = append(cols, col)
+		}
+	}
+	sort.Slice(cols, func(i, j int) bool {
+		if cols[i].tenant != cols[j].tenant {
+			return cols[i].tenant < cols[j].tenant
+		}
+		return strings.Compare(cols[i].name, cols[j].name) < 0
+	})
+	return cols
+}
+
+func (s *Server) Serve(lis net.Listener, opts ...grpc.ServerOption) error {
+	if s.config.TLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.config.TLS)))
+	}
+	if s.config.Auth != nil {
+		opts = append(opts, s.config.Auth.ServerOptions()...)
+	}
+	opts = append(opts, tenant.ServerOptions()...)
+	s.grpcServer = grpc.NewServer(opts...)
+	pb.RegisterLBCoordinatorServer(s.grpcServer, &rpcServer{server: s})
+	log.Info().Str("addr", lis.Addr().String()).Msg("nnv node listening")
+	return s.grpcServer.Serve(lis)
+}
+
+func (s *Server) ListenAndServe() error {
+	lis, err := net.Listen("tcp", s.config.Addr())
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.Addr(), err)
+	}
+	return s.Serve(lis)
+}
+
+func (s *Server) Close() error {
+	if s.grpcServer != nil {
+		s.grpcServer.GracefulStop()
+	}
+	if s.httpServer != nil {
+		s.httpServer.Shutdown(context.Background())
+	}
+	s.closeOnce.Do(func() { close(s.closing) })
+	s.background.Wait()
+	closePeers(s.peers)
+	if s.replicator != nil {
+		s.replicator.Close()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	for key, col := range s.collections {
+		if err := col.close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close collection %s: %w", key.name, err))
+		}
+	}
+	clear(s.collections)
+	return errors.Join(errs...)
+}
@@ -0,0 +1,1030 @@
+// Licensed to sjy-dv under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. sjy-dv licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	pb "github.com/sjy-dv/nnv/gen/protoc/v1/balancerCommunicationV1"
+	"github.com/sjy-dv/nnv/pkg/auth"
+	"github.com/sjy-dv/nnv/pkg/errcode"
+	"github.com/sjy-dv/nnv/pkg/hlc"
+	"github.com/sjy-dv/nnv/pkg/tenant"
+	"github.com/sjy-dv/nnv/server"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func startNode(t *testing.T, dataDir string) (pb.LBCoordinatorClient, func()) {
+	t.Helper()
+	return startNodeConfig(t, server.Config{DataDir: dataDir, Stable: true})
+}
+
+func startNodeConfig(t *testing.T, config server.Config) (pb.LBCoordinatorClient, func()) {
+	t.Helper()
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	node, err := server.New(config)
+	require.NoError(t, err)
+	lis := bufconn.Listen(1 << 20)
+	go node.Serve(lis)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	return pb.NewLBCoordinatorClient(conn), func() {
+		conn.Close()
+		require.NoError(t, node.Close())
+	}
+}
+
+func mustAny(t *testing.T, v any) *anypb.Any {
+	t.Helper()
+	var a *anypb.Any
+	var err error
+	switch v := v.(type) {
+	case string:
+		a, err = anypb.New(wrapperspb.String(v))
+	case int64:
+		a, err = anypb.New(wrapperspb.Int64(v))
+	}
+	require.NoError(t, err)
+	return a
+}
+
+// requireCode checks that an RPC failed with the status of code.
+func requireCode(t *testing.T, err error, code pb.ErrorCode) {
+	t.Helper()
+	got, ok := errcode.FromError(err)
+	require.True(t, ok, err)
+	require.Equal(t, code, got, err)
+}
+
+func insertPoints(t *testing.T, client pb.LBCoordinatorClient, collectionName string, count int) []string {
+	t.Helper()
+	ids := make([]string, count)
+	for i := 0; i < count; i++ {
+		ids[i] = uuid.NewString()
+		category := "even"
+		if i%2 == 1 {
+			category = "odd"
+		}
+		resp, err := client.Insert(context.Background(), &pb.ModifyDataset{
+			Id:             ids[i],
+			CollectionName: collectionName,
+			Vector:         []float32{float32(i), float32(i)},
+			Metadata: map[string]*anypb.Any{
+				"category": mustAny(t, category),
+				"rank":     mustAny(t, int64(i)),
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	}
+	return ids
+}
+
+func TestCollectionLifecycle(t *testing.T) {
+	dataDir := t.TempDir()
+	client, stop := startNode(t, dataDir)
+	ctx := context.Background()
+	_, err := client.Ping(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+
+	resp, err := client.CreateCollection(ctx, &pb.Collection{
+		CollectionName: "docs",
+		Dimension:      2,
+		InvertedIndex:  []string{"category", "rank:integer"},
+	})
+	require.NoError(t, err)
+	require.True(t, resp.GetResponse().GetResult(), resp.GetResponse().GetErrorMessage())
+	require.NotEmpty(t, resp.GetCollection().GetCreateTimestamp())
+
+	_, err = client.CreateCollection(ctx, &pb.Collection{CollectionName: "docs", Dimension: 2})
+	requireCode(t, err, pb.ErrorCode_ALREADY_EXISTS)
+
+	insertPoints(t, client, "docs", 10)
+	col, err := client.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+	require.NoError(t, err)
+	require.EqualValues(t, 10, col.GetCollectionSize())
+	stop()
+
+	// Collections survive restarts
+	client, stop = startNode(t, dataDir)
+	defer stop()
+	list, err := client.ListCollection(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, list.GetCount())
+	require.EqualValues(t, 10, list.GetCollections()[0].GetCollectionSize())
+
+	dropResp, err := client.DropCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+	require.NoError(t, err)
+	require.True(t, dropResp.GetResult(), dropResp.GetErrorMessage())
+	_, err = client.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+	require.Error(t, err)
+}
+
+func TestInsertUpdateDeleteSearch(t *testing.T) {
+	for _, vectorIndex := range []pb.VectorIndex{pb.VectorIndex_FLAT_INDEX, pb.VectorIndex_HNSW_INDEX} {
+		t.Run(vectorIndex.String(), func(t *testing.T) {
+			client, stop := startNode(t, t.TempDir())
+			defer stop()
+			ctx := context.Background()
+			_, err := client.CreateCollection(ctx, &pb.Collection{
+				CollectionName: "docs",
+				Dimension:      2,
+				InvertedIndex:  []string{"category", "rank:integer"},
+				VectorIndex:    vectorIndex,
+			})
+			require.NoError(t, err)
+			ids := insertPoints(t, client, "docs", 20)
+
+			// Duplicate inserts and wrong dimensions are rejected
+			_, err = client.Insert(ctx, &pb.ModifyDataset{Id: ids[0], CollectionName: "docs", Vector: []float32{1, 1}})
+			requireCode(t, err, pb.ErrorCode_ALREADY_EXISTS)
+			_, err = client.Insert(ctx, &pb.ModifyDataset{Id: uuid.NewString(), CollectionName: "docs", Vector: []float32{1}})
+			requireCode(t, err, pb.ErrorCode_DIMENSION_MISMATCH)
+
+			search, err := client.Search(ctx, &pb.SearchReq{CollectionName: "docs", Vector: []float32{0, 0}, TopK: 3})
+			require.NoError(t, err)
+			require.True(t, search.GetResult(), search.GetErrorMessage())
+			require.Len(t, search.GetResponse(), 3)
+			require.Equal(t, ids[0], search.GetResponse()[0].GetId())
+			require.Equal(t, []float32{0, 0}, search.GetResponse()[0].GetVector())
+			require.EqualValues(t, 1, search.GetResponse()[0].GetScore())
+
+			search, err = client.Search(ctx, &pb.SearchReq{
+				CollectionName: "docs",
+				Vector:         []float32{0, 0},
+				TopK:           3,
+				Metadata:       map[string]*anypb.Any{"category": mustAny(t, "odd")},
+			})
+			require.NoError(t, err)
+			require.True(t, search.GetResult(), search.GetErrorMessage())
+			require.Len(t, search.GetResponse(), 3)
+			require.Equal(t, ids[1], search.GetResponse()[0].GetId())
+			category := &wrapperspb.StringValue{}
+			require.NoError(t, search.GetResponse()[0].GetMetadata()["category"].UnmarshalTo(category))
+			require.Equal(t, "odd", category.GetValue())
+
+			// Move the first point away and delete the second
+			resp, err := client.Update(ctx, &pb.ModifyDataset{Id: ids[0], CollectionName: "docs", Vector: []float32{100, 100}})
+			require.NoError(t, err)
+			require.True(t, resp.GetResult(), resp.GetErrorMessage())
+			resp, err = client.Delete(ctx, &pb.DeleteDataset{Id: ids[1], CollectionName: "docs"})
+			require.NoError(t, err)
+			require.True(t, resp.GetResult(), resp.GetErrorMessage())
+			_, err = client.Update(ctx, &pb.ModifyDataset{Id: uuid.NewString(), CollectionName: "docs", Vector: []float32{1, 1}})
+			requireCode(t, err, pb.ErrorCode_NOT_FOUND)
+
+			search, err = client.Search(ctx, &pb.SearchReq{CollectionName: "docs", Vector: []float32{0, 0}, TopK: 2})
+			require.NoError(t, err)
+			require.True(t, search.GetResult(), search.GetErrorMessage())
+			require.Len(t, search.GetResponse(), 2)
+			require.Equal(t, ids[2], search.GetResponse()[0].GetId())
+			require.Equal(t, ids[3], search.GetResponse()[1].GetId())
+			col, err := client.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+			require.NoError(t, err)
+			require.EqualValues(t, 19, col.GetCollectionSize())
+		})
+	}
+}
+
+func TestHNSWPersistence(t *testing.T) {
+	dataDir := t.TempDir()
+	client, stop := startNode(t, dataDir)
+	ctx := context.Background()
+	_, err := client.CreateCollection(ctx, &pb.Collection{
+		CollectionName: "docs",
+		Dimension:      2,
+		VectorIndex:    pb.VectorIndex_HNSW_INDEX,
+	})
+	require.NoError(t, err)
+	ids := insertPoints(t, client, "docs", 20)
+	_, err = client.Update(ctx, &pb.ModifyDataset{Id: ids[0], CollectionName: "docs", Vector: []float32{100, 100}})
+	require.NoError(t, err)
+	_, err = client.Delete(ctx, &pb.DeleteDataset{Id: ids[1], CollectionName: "docs"})
+	require.NoError(t, err)
+	nearest := func(count int) []string {
+		t.Helper()
+		resp, err := client.Search(ctx, &pb.SearchReq{CollectionName: "docs", Vector: []float32{0, 0}, TopK: uint64(count)})
+		require.NoError(t, err)
+		var got []string
+		for _, row := range resp.GetResponse() {
+			got = append(got, row.GetId())
+		}
+		return got
+	}
+	require.Equal(t, ids[2:5], nearest(3))
+
+	// the graph is loaded as it was written, moved and deleted points
+	// included, and takes writes of the same dimension only
+	for restart := 0; restart < 2; restart++ {
+		stop()
+		client, stop = startNode(t, dataDir)
+		require.Equal(t, ids[2:5], nearest(3))
+		_, err = client.Insert(ctx, &pb.ModifyDataset{Id: uuid.NewString(), CollectionName: "docs", Vector: []float32{1}})
+		requireCode(t, err, pb.ErrorCode_DIMENSION_MISMATCH)
+	}
+	closest := uuid.NewString()
+	_, err = client.Insert(ctx, &pb.ModifyDataset{Id: closest, CollectionName: "docs", Vector: []float32{0.5, 0.5}})
+	require.NoError(t, err)
+	stop()
+
+	client, stop = startNode(t, dataDir)
+	defer stop()
+	require.Equal(t, []string{closest, ids[2]}, nearest(2))
+	require.Equal(t, ids[0], nearest(20)[19])
+}
+
+func TestSearchFilter(t *testing.T) {
+	for _, vectorIndex := range []pb.VectorIndex{pb.VectorIndex_FLAT_INDEX, pb.VectorIndex_HNSW_INDEX} {
+		t.Run(vectorIndex.String(), func(t *testing.T) {
+			client, stop := startNode(t, t.TempDir())
+			defer stop()
+			ctx := context.Background()
+			_, err := client.CreateCollection(ctx, &pb.Collection{
+				CollectionName: "docs",
+				Dimension:      2,
+				InvertedIndex:  []string{"category", "rank:integer"},
+				VectorIndex:    vectorIndex,
+			})
+			require.NoError(t, err)
+			ids := insertPoints(t, client, "docs", 20)
+			search := func(filter *pb.Filter, metadata map[string]*anypb.Any) *pb.SearchResponse {
+				t.Helper()
+				resp, err := client.Search(ctx, &pb.SearchReq{
+					CollectionName: "docs",
+					Vector:         []float32{0, 0},
+					TopK:           20,
+					Metadata:       metadata,
+					Filter:         filter,
+				})
+				require.NoError(t, err)
+				return resp
+			}
+			rank := func(op pb.FilterOperator, value, end int64) *pb.Filter {
+				return &pb.Filter{Property: "rank", Condition: &pb.Filter_IntegerCondition{
+					IntegerCondition: &pb.IntegerCondition{Operator: op, Value: value, EndValue: end},
+				}}
+			}
+			category := func(value string) *pb.Filter {
+				return &pb.Filter{Property: "category", Condition: &pb.Filter_StringCondition{
+					StringCondition: &pb.StringCondition{Operator: pb.FilterOperator_FILTER_EQUALS, Value: value},
+				}}
+			}
+			resultIds := func(resp *pb.SearchResponse) []string {
+				t.Helper()
+				require.True(t, resp.GetResult(), resp.GetErrorMessage())
+				var got []string
+				for _, row := range resp.GetResponse() {
+					got = append(got, row.GetId())
+				}
+				return got
+			}
+
+			// odd ranks from 4 to 9, nearest first
+			resp := search(&pb.Filter{Property: "_and", And: []*pb.Filter{
+				rank(pb.FilterOperator_FILTER_IN_RANGE, 4, 9),
+				category("odd"),
+			}}, nil)
+			require.Equal(t, []string{ids[5], ids[7], ids[9]}, resultIds(resp))
+
+			resp = search(&pb.Filter{Property: "_or", Or: []*pb.Filter{
+				rank(pb.FilterOperator_FILTER_LESS_THAN, 2, 0),
+				rank(pb.FilterOperator_FILTER_GREATER_THAN_OR_EQUALS, 18, 0),
+			}}, nil)
+			require.Equal(t, []string{ids[0], ids[1], ids[18], ids[19]}, resultIds(resp))
+
+			// the metadata equality and the filter both apply
+			resp = search(rank(pb.FilterOperator_FILTER_LESS_THAN_OR_EQUALS, 4, 0), map[string]*anypb.Any{"category": mustAny(t, "even")})
+			require.Equal(t, []string{ids[0], ids[2], ids[4]}, resultIds(resp))
+
+			resp = search(&pb.Filter{Property: "_id", Condition: &pb.Filter_StringArrayCondition{
+				StringArrayCondition: &pb.StringArrayCondition{Operator: pb.FilterOperator_FILTER_CONTAINS_ANY, Values: []string{ids[3], ids[12]}},
+			}}, nil)
+			require.Equal(t, []string{ids[3], ids[12]}, resultIds(resp))
+
+			// filters on fields that are not indexed or with the wrong condition fail
+			for _, filter := range []*pb.Filter{
+				{Property: "missing", Condition: &pb.Filter_StringCondition{StringCondition: &pb.StringCondition{Value: "x"}}},
+				{Property: "rank", Condition: &pb.Filter_StringCondition{StringCondition: &pb.StringCondition{Value: "x"}}},
+			} {
+				_, err := client.Search(ctx, &pb.SearchReq{CollectionName: "docs", Vector: []float32{0, 0}, Filter: filter})
+				requireCode(t, err, pb.ErrorCode_INVALID_FILTER)
+			}
+
+			// the deprecated search options still filter
+			resp, err = client.Search(ctx, &pb.SearchReq{
+				CollectionName: "docs",
+				Vector:         []float32{0, 0},
+				TopK:           20,
+				SearchOptions:  []byte(`{"filter": {"property": "rank", "integerCondition": {"operator": "FILTER_LESS_THAN", "value": 3}}}`),
+			})
+			require.NoError(t, err)
+			require.Equal(t, []string{ids[0], ids[1], ids[2]}, resultIds(resp))
+			_, err = client.Search(ctx, &pb.SearchReq{CollectionName: "docs", Vector: []float32{0, 0}, TopK: 20, SearchOptions: []byte("{")})
+			requireCode(t, err, pb.ErrorCode_INVALID_ARGUMENT)
+		})
+	}
+}
+
+func TestBatchSearch(t *testing.T) {
+	client, stop := startNode(t, t.TempDir())
+	defer stop()
+	ctx := context.Background()
+	_, err := client.CreateCollection(ctx, &pb.Collection{
+		CollectionName: "docs",
+		Dimension:      2,
+		InvertedIndex:  []string{"category", "rank:integer"},
+	})
+	require.NoError(t, err)
+	insertPoints(t, client, "docs", 50)
+	odd := &pb.Filter{Property: "category", Condition: &pb.Filter_StringCondition{
+		StringCondition: &pb.StringCondition{Operator: pb.FilterOperator_FILTER_EQUALS, Value: "odd"},
+	}}
+	var searches []*pb.SearchReq
+	for i := 0; i < 40; i++ {
+		search := &pb.SearchReq{Vector: []float32{float32(i) + 0.3, float32(i) + 0.3}, TopK: 3}
+		if i%2 == 0 {
+			search.Filter = odd
+		}
+		searches = append(searches, search)
+	}
+	// bad searches fail on their own
+	searches[5] = &pb.SearchReq{Vector: []float32{1, 2, 3}}
+	searches[7] = &pb.SearchReq{CollectionName: "other", Vector: []float32{1, 1}}
+
+	resp, err := client.BatchSearch(ctx, &pb.BatchSearchReq{CollectionName: "docs", Searches: searches})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	require.Len(t, resp.GetResponses(), len(searches))
+	for i, search := range searches {
+		got := resp.GetResponses()[i]
+		if i == 5 || i == 7 {
+			require.False(t, got.GetResult(), i)
+			continue
+		}
+		search.CollectionName = "docs"
+		want, err := client.Search(ctx, search)
+		require.NoError(t, err)
+		require.True(t, got.GetResult(), got.GetErrorMessage())
+		require.Len(t, got.GetResponse(), 3)
+		for j, row := range got.GetResponse() {
+			require.Equal(t, want.GetResponse()[j].GetId(), row.GetId(), i)
+		}
+	}
+
+	_, err = client.BatchSearch(ctx, &pb.BatchSearchReq{CollectionName: "nope", Searches: searches})
+	requireCode(t, err, pb.ErrorCode_NOT_FOUND)
+}
+
+func TestScrollAndCount(t *testing.T) {
+	client, stop := startNode(t, t.TempDir())
+	defer stop()
+	ctx := context.Background()
+	_, err := client.CreateCollection(ctx, &pb.Collection{
+		CollectionName: "docs",
+		Dimension:      2,
+		InvertedIndex:  []string{"category", "rank:integer"},
+	})
+	require.NoError(t, err)
+	ids := insertPoints(t, client, "docs", 25)
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	no := false
+
+	points, err := client.GetPoints(ctx, &pb.PointIds{CollectionName: "docs", Ids: ids[:2], WithVector: &no})
+	require.NoError(t, err)
+	require.True(t, points.GetResult(), points.GetErrorMessage())
+	require.Len(t, points.GetPoints(), 2)
+	require.Nil(t, points.GetPoints()[0].GetVector())
+	require.Contains(t, points.GetPoints()[0].GetMetadata(), "category")
+
+	// pages follow the id order until the cursor runs out
+	var got []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		resp, err := client.Scroll(ctx, &pb.ScrollReq{CollectionName: "docs", Cursor: cursor, Limit: 10, WithMetadata: &no})
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+		for _, row := range resp.GetPoints() {
+			require.Empty(t, row.GetMetadata())
+			require.Len(t, row.GetVector(), 2)
+			got = append(got, row.GetId())
+		}
+		if cursor = resp.GetNextCursor(); cursor == "" {
+			break
+		}
+	}
+	require.Equal(t, sorted, got)
+
+	odd := &pb.Filter{Property: "category", Condition: &pb.Filter_StringCondition{
+		StringCondition: &pb.StringCondition{Operator: pb.FilterOperator_FILTER_EQUALS, Value: "odd"},
+	}}
+	resp, err := client.Scroll(ctx, &pb.ScrollReq{CollectionName: "docs", Filter: odd, Limit: 100})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	require.Len(t, resp.GetPoints(), 12)
+	require.Empty(t, resp.GetNextCursor())
+	for _, row := range resp.GetPoints() {
+		require.Equal(t, mustAny(t, "odd").GetValue(), row.GetMetadata()["category"].GetValue())
+	}
+	_, err = client.Scroll(ctx, &pb.ScrollReq{CollectionName: "docs", Cursor: "not-a-uuid"})
+	requireCode(t, err, pb.ErrorCode_INVALID_ARGUMENT)
+
+	count, err := client.Count(ctx, &pb.CountReq{CollectionName: "docs"})
+	require.NoError(t, err)
+	require.True(t, count.GetResult(), count.GetErrorMessage())
+	require.EqualValues(t, 25, count.GetCount())
+	count, err = client.Count(ctx, &pb.CountReq{CollectionName: "docs", Filter: odd})
+	require.NoError(t, err)
+	require.EqualValues(t, 12, count.GetCount())
+	_, err = client.Count(ctx, &pb.CountReq{CollectionName: "nope"})
+	requireCode(t, err, pb.ErrorCode_NOT_FOUND)
+}
+
+func TestPatchMetadata(t *testing.T) {
+	client, stop := startNode(t, t.TempDir())
+	defer stop()
+	ctx := context.Background()
+	_, err := client.CreateCollection(ctx, &pb.Collection{
+		CollectionName: "docs",
+		Dimension:      2,
+		InvertedIndex:  []string{"category", "rank:integer"},
+	})
+	require.NoError(t, err)
+	ids := insertPoints(t, client, "docs", 4)
+	patch := func(req *pb.PatchMetadataReq) error {
+		req.CollectionName = "docs"
+		_, err := client.PatchMetadata(ctx, req)
+		return err
+	}
+	matching := func(filter *pb.Filter) []string {
+		t.Helper()
+		resp, err := client.Scroll(ctx, &pb.ScrollReq{CollectionName: "docs", Filter: filter})
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+		var got []string
+		for _, row := range resp.GetPoints() {
+			got = append(got, row.GetId())
+		}
+		return got
+	}
+	category := func(value string) *pb.Filter {
+		return &pb.Filter{Property: "category", Condition: &pb.Filter_StringCondition{
+			StringCondition: &pb.StringCondition{Operator: pb.FilterOperator_FILTER_EQUALS, Value: value},
+		}}
+	}
+	rank := func(value int64) *pb.Filter {
+		return &pb.Filter{Property: "rank", Condition: &pb.Filter_IntegerCondition{
+			IntegerCondition: &pb.IntegerCondition{Operator: pb.FilterOperator_FILTER_EQUALS, Value: value},
+		}}
+	}
+
+	require.NoError(t, patch(&pb.PatchMetadataReq{
+		Id:        ids[1],
+		Set:       map[string]*anypb.Any{"category": mustAny(t, "special"), "note": mustAny(t, "x")},
+		Increment: map[string]*anypb.Any{"rank": mustAny(t, int64(10)), "views": mustAny(t, int64(1))},
+	}))
+	row := fetch(t, client, ids[1])
+	require.Equal(t, []float32{1, 1}, row.GetVector())
+	require.True(t, proto.Equal(mustAny(t, int64(11)), row.GetMetadata()["rank"]))
+	require.True(t, proto.Equal(mustAny(t, int64(1)), row.GetMetadata()["views"]))
+	require.True(t, proto.Equal(mustAny(t, "x"), row.GetMetadata()["note"]))
+	// the indexes moved the point from its old values to the new ones
+	require.Equal(t, []string{ids[1]}, matching(category("special")))
+	require.Equal(t, []string{ids[3]}, matching(category("odd")))
+	require.Equal(t, []string{ids[1]}, matching(rank(11)))
+	require.Empty(t, matching(rank(1)))
+
+	require.NoError(t, patch(&pb.PatchMetadataReq{Id: ids[1], Unset: []string{"category", "note"}}))
+	row = fetch(t, client, ids[1])
+	require.NotContains(t, row.GetMetadata(), "category")
+	require.NotContains(t, row.GetMetadata(), "note")
+	require.Empty(t, matching(category("special")))
+	require.Equal(t, []string{ids[1]}, matching(rank(11)))
+
+	// invalid patches leave the point alone
+	for _, req := range []*pb.PatchMetadataReq{
+		{Id: ids[0], Set: map[string]*anypb.Any{"rank": mustAny(t, int64(1))}, Unset: []string{"rank"}},
+		{Id: ids[0], Increment: map[string]*anypb.Any{"category": mustAny(t, int64(1))}},
+		{Id: ids[0], Increment: map[string]*anypb.Any{"rank": mustAny(t, "one")}},
+		{Id: ids[0], Set: map[string]*anypb.Any{"rank": mustAny(t, "zero")}},
+		{Id: uuid.NewString(), Set: map[string]*anypb.Any{"note": mustAny(t, "x")}},
+	} {
+		require.Error(t, patch(req))
+	}
+	require.Equal(t, []string{ids[0]}, matching(rank(0)))
+	require.True(t, proto.Equal(mustAny(t, "even"), fetch(t, client, ids[0]).GetMetadata()["category"]))
+}
+
+func TestBatchStreams(t *testing.T) {
+	client, stop := startNode(t, t.TempDir())
+	defer stop()
+	ctx := context.Background()
+	_, err := client.CreateCollection(ctx, &pb.Collection{CollectionName: "docs", Dimension: 2})
+	require.NoError(t, err)
+
+	stream, err := client.BatchInsert(ctx)
+	require.NoError(t, err)
+	ids := make([]string, 10)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+		require.NoError(t, stream.Send(&pb.ModifyDataset{Id: ids[i], CollectionName: "docs", Vector: []float32{float32(i), 0}}))
+	}
+	// A bad item is reported without aborting the stream
+	require.NoError(t, stream.Send(&pb.ModifyDataset{Id: "not-a-uuid", CollectionName: "docs", Vector: []float32{0, 0}}))
+	require.NoError(t, stream.CloseSend())
+	for i := 0; i < len(ids); i++ {
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), fmt.Sprint(i, resp.GetErrorMessage()))
+	}
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.False(t, resp.GetResult())
+
+	delStream, err := client.BatchDelete(ctx)
+	require.NoError(t, err)
+	for _, id := range ids[:5] {
+		require.NoError(t, delStream.Send(&pb.DeleteDataset{Id: id, CollectionName: "docs"}))
+	}
+	require.NoError(t, delStream.CloseSend())
+	for range ids[:5] {
+		resp, err := delStream.Recv()
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	}
+	col, err := client.GetCollection(ctx, &pb.CollectionName{CollectionName: "docs"})
+	require.NoError(t, err)
+	require.EqualValues(t, 5, col.GetCollectionSize())
+}
+
+func TestConditionalWrites(t *testing.T) {
+	client, stop := startNode(t, t.TempDir())
+	defer stop()
+	ctx := context.Background()
+	_, err := client.CreateCollection(ctx, &pb.Collection{CollectionName: "docs", Dimension: 2})
+	require.NoError(t, err)
+	id := uuid.NewString()
+	write := func(update bool, vector []float32, cond *pb.Precondition) error {
+		req := &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: vector, Precondition: cond}
+		var err error
+		if update {
+			_, err = client.Update(ctx, req)
+		} else {
+			_, err = client.Insert(ctx, req)
+		}
+		return err
+	}
+	versionEquals := func(v uint64) *pb.Precondition { return &pb.Precondition{IfVersionEquals: &v} }
+
+	require.NoError(t, write(false, []float32{1, 1}, &pb.Precondition{IfAbsent: true}))
+	requireCode(t, write(false, []float32{2, 2}, &pb.Precondition{IfAbsent: true}), pb.ErrorCode_PRECONDITION_FAILED)
+
+	// compare-and-set on the version, the loser sees the precondition fail
+	v := fetch(t, client, id).GetVersion()
+	require.NoError(t, write(true, []float32{3, 3}, versionEquals(v)))
+	requireCode(t, write(true, []float32{4, 4}, versionEquals(v)), pb.ErrorCode_PRECONDITION_FAILED)
+	require.Equal(t, []float32{3, 3}, fetch(t, client, id).GetVector())
+
+	_, err = client.Delete(ctx, &pb.DeleteDataset{Id: id, CollectionName: "docs", Precondition: versionEquals(v)})
+	requireCode(t, err, pb.ErrorCode_PRECONDITION_FAILED)
+	_, err = client.Delete(ctx, &pb.DeleteDataset{Id: id, CollectionName: "docs", Precondition: &pb.Precondition{IfExists: true}})
+	require.NoError(t, err)
+	// a tombstone counts as absent
+	_, err = client.Delete(ctx, &pb.DeleteDataset{Id: id, CollectionName: "docs", Precondition: &pb.Precondition{IfExists: true}})
+	requireCode(t, err, pb.ErrorCode_PRECONDITION_FAILED)
+	require.NoError(t, write(false, []float32{5, 5}, &pb.Precondition{IfAbsent: true}))
+
+	// batch items fail on their own
+	stream, err := client.BatchUpdate(ctx)
+	require.NoError(t, err)
+	v = fetch(t, client, id).GetVersion()
+	for _, cond := range []*pb.Precondition{versionEquals(v + 1), versionEquals(v), {IfAbsent: true}, {IfExists: true}} {
+		require.NoError(t, stream.Send(&pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{6, 6}, Precondition: cond}))
+	}
+	require.NoError(t, stream.CloseSend())
+	for _, ok := range []bool{false, true, false, true} {
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, ok, resp.GetResult(), resp.GetErrorMessage())
+		if !ok {
+			require.Equal(t, pb.ErrorCode_PRECONDITION_FAILED, resp.GetErrorCode())
+		}
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	client, stop := startNode(t, t.TempDir())
+	defer stop()
+	ctx := context.Background()
+	_, err := client.CreateCollection(ctx, &pb.Collection{CollectionName: "docs", Dimension: 2})
+	require.NoError(t, err)
+
+	_, err = client.CreateCollection(ctx, &pb.Collection{CollectionName: "docs", Dimension: 2})
+	requireCode(t, err, pb.ErrorCode_ALREADY_EXISTS)
+
+	id := uuid.NewString()
+	for _, tc := range []struct {
+		req  *pb.ModifyDataset
+		code pb.ErrorCode
+	}{
+		{&pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{1, 2, 3}}, pb.ErrorCode_DIMENSION_MISMATCH},
+		{&pb.ModifyDataset{Id: "not-a-uuid", CollectionName: "docs", Vector: []float32{1, 2}}, pb.ErrorCode_INVALID_ARGUMENT},
+		{&pb.ModifyDataset{Id: id, CollectionName: "missing", Vector: []float32{1, 2}}, pb.ErrorCode_NOT_FOUND},
+	} {
+		_, err := client.Insert(ctx, tc.req)
+		requireCode(t, err, tc.code)
+	}
+	_, err = client.Update(ctx, &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{1, 2}})
+	requireCode(t, err, pb.ErrorCode_NOT_FOUND)
+	// the gRPC code follows the error code
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetCollection(ctx, &pb.CollectionName{CollectionName: "missing"})
+	requireCode(t, err, pb.ErrorCode_NOT_FOUND)
+}
+
+func TestAuthorization(t *testing.T) {
+	client, stop := startNodeConfig(t, server.Config{DataDir: t.TempDir(), Stable: true, Auth: &auth.Config{
+		Authenticators: []auth.Authenticator{auth.APIKeys{"admin-key": "admin", "reader-key": "reader", "writer-key": "writer", "ops-key": "ops"}},
+		Policy: auth.Policy{
+			"admin":  {auth.Any: auth.Admin},
+			"reader": {"docs": auth.Read},
+			"writer": {"docs": auth.Write},
+			"ops":    {"*/*": auth.Admin},
+		},
+		Forwarders: []string{"admin"},
+		Operators:  []string{"ops"},
+	}})
+	defer stop()
+	as := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), auth.AuthorizationKey, "Bearer "+key)
+	}
+	denied := func(err error, code pb.ErrorCode) {
+		t.Helper()
+		got, ok := errcode.FromError(err)
+		require.True(t, ok, err)
+		require.Equal(t, code, got)
+	}
+
+	_, err := client.Ping(context.Background(), &emptypb.Empty{})
+	require.NoError(t, err)
+	_, err = client.CreateCollection(context.Background(), &pb.Collection{CollectionName: "docs", Dimension: 2})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.CreateCollection(as("wrong-key"), &pb.Collection{CollectionName: "docs", Dimension: 2})
+	denied(err, pb.ErrorCode_UNAUTHENTICATED)
+	_, err = client.CreateCollection(as("writer-key"), &pb.Collection{CollectionName: "docs", Dimension: 2})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	for _, name := range []string{"docs", "other"} {
+		resp, err := client.CreateCollection(as("admin-key"), &pb.Collection{CollectionName: name, Dimension: 2})
+		require.NoError(t, err)
+		require.True(t, resp.GetResponse().GetResult(), resp.GetResponse().GetErrorMessage())
+	}
+
+	id := uuid.NewString()
+	resp, err := client.Insert(as("writer-key"), &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{1, 1}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	_, err = client.Insert(as("reader-key"), &pb.ModifyDataset{Id: uuid.NewString(), CollectionName: "docs", Vector: []float32{1, 1}})
+	denied(err, pb.ErrorCode_PERMISSION_DENIED)
+	// only forwarders pick the versions of their writes
+	_, err = client.Update(as("writer-key"), &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{2, 2}, Version: 1, Origin: "writer"})
+	denied(err, pb.ErrorCode_PERMISSION_DENIED)
+	resp, err = client.Update(as("admin-key"), &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{2, 2}, Version: 1, Origin: "admin"})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	search, err := client.Search(as("reader-key"), &pb.SearchReq{CollectionName: "docs", Vector: []float32{1, 1}, TopK: 1})
+	require.NoError(t, err)
+	require.Equal(t, id, search.GetResponse()[0].GetId())
+	_, err = client.Search(as("reader-key"), &pb.SearchReq{CollectionName: "other", Vector: []float32{1, 1}, TopK: 1})
+	denied(err, pb.ErrorCode_PERMISSION_DENIED)
+
+	// every message of a stream is authorized on its own
+	stream, err := client.BatchInsert(as("writer-key"))
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.ModifyDataset{Id: uuid.NewString(), CollectionName: "docs", Vector: []float32{2, 2}}))
+	require.NoError(t, stream.Send(&pb.ModifyDataset{Id: uuid.NewString(), CollectionName: "other", Vector: []float32{2, 2}}))
+	require.NoError(t, stream.CloseSend())
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	require.True(t, first.GetResult(), first.GetErrorMessage())
+	_, err = stream.Recv()
+	denied(err, pb.ErrorCode_PERMISSION_DENIED)
+
+	// callers only see the collections they may read
+	list, err := client.ListCollection(as("reader-key"), &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, list.GetCollections(), 1)
+	require.Equal(t, "docs", list.GetCollections()[0].GetCollectionName())
+	list, err = client.ListCollection(as("admin-key"), &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, list.GetCollections(), 2)
+
+	// forwarding an identity needs a trusted forwarder
+	_, err = client.GetCollection(metadata.AppendToOutgoingContext(as("writer-key"), auth.ForwardedKey, "admin"), &pb.CollectionName{CollectionName: "other"})
+	denied(err, pb.ErrorCode_PERMISSION_DENIED)
+
+	// grants are per tenant and only operators leave the default one
+	in := func(ctx context.Context, name string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, tenant.MetadataKey, name)
+	}
+	_, err = client.CreateCollection(in(as("admin-key"), "team-a"), &pb.Collection{CollectionName: "docs", Dimension: 2})
+	denied(err, pb.ErrorCode_PERMISSION_DENIED)
+	created, err := client.CreateCollection(in(as("ops-key"), "team-a"), &pb.Collection{CollectionName: "docs", Dimension: 2})
+	require.NoError(t, err)
+	require.True(t, created.GetResponse().GetResult(), created.GetResponse().GetErrorMessage())
+	_, err = client.Insert(in(as("writer-key"), "team-a"), &pb.ModifyDataset{Id: uuid.NewString(), CollectionName: "docs", Vector: []float32{1, 1}})
+	denied(err, pb.ErrorCode_PERMISSION_DENIED)
+	_, err = client.ListCollection(in(as("admin-key"), tenant.All), &emptypb.Empty{})
+	denied(err, pb.ErrorCode_PERMISSION_DENIED)
+	list, err = client.ListCollection(in(as("ops-key"), tenant.All), &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, list.GetCollections(), 3)
+	list, err = client.ListCollection(in(as("admin-key"), tenant.Default), &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, list.GetCollections(), 2)
+}
+
+func TestTenants(t *testing.T) {
+	dataDir := t.TempDir()
+	config := server.Config{DataDir: dataDir, Stable: true, Quotas: map[string]server.Quota{
+		tenant.All: {Collections: 2, Points: 3},
+		"big":      {},
+	}}
+	client, stop := startNodeConfig(t, config)
+	in := func(name string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), tenant.MetadataKey, name)
+	}
+
+	// the same name resolves to a different collection per tenant
+	for i, name := range []string{"team-a", "team-b"} {
+		resp, err := client.CreateCollection(in(name), &pb.Collection{CollectionName: "docs", Dimension: uint64(i + 2)})
+		require.NoError(t, err)
+		require.True(t, resp.GetResponse().GetResult(), resp.GetResponse().GetErrorMessage())
+	}
+	col, err := client.GetCollection(in("team-b"), &pb.CollectionName{CollectionName: "docs"})
+	require.NoError(t, err)
+	require.EqualValues(t, 3, col.GetDimension())
+	require.Equal(t, "team-b", col.GetTenant())
+	_, err = client.GetCollection(context.Background(), &pb.CollectionName{CollectionName: "docs"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetCollection(in("bad/name"), &pb.CollectionName{CollectionName: "docs"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	list, err := client.ListCollection(in("team-a"), &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, list.GetCollections(), 1)
+	require.EqualValues(t, 2, list.GetCollections()[0].GetDimension())
+	list, err = client.ListCollection(in(tenant.All), &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, list.GetCollections(), 2)
+
+	// quotas
+	resp, err := client.CreateCollection(in("team-a"), &pb.Collection{CollectionName: "more", Dimension: 2})
+	require.NoError(t, err)
+	require.True(t, resp.GetResponse().GetResult(), resp.GetResponse().GetErrorMessage())
+	_, err = client.CreateCollection(in("team-a"), &pb.Collection{CollectionName: "most", Dimension: 2})
+	requireCode(t, err, pb.ErrorCode_QUOTA_EXCEEDED)
+	ids := make([]string, 4)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+		collection := "docs"
+		if i == 2 {
+			collection = "more"
+		}
+		_, err := client.Insert(in("team-a"), &pb.ModifyDataset{Id: ids[i], CollectionName: collection, Vector: []float32{1, 1}})
+		if i < 3 {
+			require.NoError(t, err)
+		} else {
+			requireCode(t, err, pb.ErrorCode_QUOTA_EXCEEDED)
+		}
+	}
+	// repairs and migrations copy points that are stored elsewhere already
+	insert, err := client.Insert(in("team-a"), &pb.ModifyDataset{Id: uuid.NewString(), CollectionName: "docs", Vector: []float32{1, 1}, Internal: true})
+	require.NoError(t, err)
+	require.True(t, insert.GetResult(), insert.GetErrorMessage())
+	// writes that add no point pass
+	update, err := client.Update(in("team-a"), &pb.ModifyDataset{Id: ids[0], CollectionName: "docs", Vector: []float32{2, 2}})
+	require.NoError(t, err)
+	require.True(t, update.GetResult(), update.GetErrorMessage())
+	del, err := client.Delete(in("team-a"), &pb.DeleteDataset{Id: ids[0], CollectionName: "docs"})
+	require.NoError(t, err)
+	require.True(t, del.GetResult(), del.GetErrorMessage())
+	insert, err = client.Insert(in("team-b"), &pb.ModifyDataset{Id: ids[3], CollectionName: "docs", Vector: []float32{1, 1, 1}})
+	require.NoError(t, err)
+	require.True(t, insert.GetResult(), insert.GetErrorMessage())
+	for _, name := range []string{"a", "b", "c"} {
+		resp, err := client.CreateCollection(in("big"), &pb.Collection{CollectionName: name, Dimension: 2})
+		require.NoError(t, err)
+		require.True(t, resp.GetResponse().GetResult(), resp.GetResponse().GetErrorMessage())
+	}
+	stop()
+
+	// tenants survive restarts along with their usage, a point byte quota stops
+	// writes that grow
+	config.Quotas = map[string]server.Quota{"team-a": {PointBytes: 1}}
+	client, stop = startNodeConfig(t, config)
+	defer stop()
+	list, err = client.ListCollection(in(tenant.All), &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, list.GetCollections(), 6)
+	require.Equal(t, "big", list.GetCollections()[0].GetTenant())
+	_, err = client.Insert(in("team-a"), &pb.ModifyDataset{Id: uuid.NewString(), CollectionName: "docs", Vector: []float32{1, 1}})
+	requireCode(t, err, pb.ErrorCode_QUOTA_EXCEEDED)
+	require.ErrorContains(t, err, "point bytes (vectors and metadata, not disk size)")
+	del, err = client.Delete(in("team-a"), &pb.DeleteDataset{Id: ids[1], CollectionName: "docs"})
+	require.NoError(t, err)
+	require.True(t, del.GetResult(), del.GetErrorMessage())
+	insert, err = client.Insert(in("team-b"), &pb.ModifyDataset{Id: uuid.NewString(), CollectionName: "docs", Vector: []float32{1, 1, 1}})
+	require.NoError(t, err)
+	require.True(t, insert.GetResult(), insert.GetErrorMessage())
+}
+
+func TestAliases(t *testing.T) {
+	dataDir := t.TempDir()
+	client, stop := startNode(t, dataDir)
+	ctx := context.Background()
+	for _, name := range []string{"docs-v1", "docs-v2"} {
+		resp, err := client.CreateCollection(ctx, &pb.Collection{CollectionName: name, Dimension: 2})
+		require.NoError(t, err)
+		require.True(t, resp.GetResponse().GetResult(), resp.GetResponse().GetErrorMessage())
+	}
+	insertPoints(t, client, "docs-v1", 3)
+	insertPoints(t, client, "docs-v2", 5)
+	count := func(name string) uint64 {
+		t.Helper()
+		resp, err := client.Count(ctx, &pb.CountReq{CollectionName: name})
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+		return resp.GetCount()
+	}
+	change := func(resp *pb.Response, err error) *pb.Response {
+		t.Helper()
+		require.NoError(t, err)
+		return resp
+	}
+	failed := func(_ *pb.Response, err error) error { return err }
+
+	require.True(t, change(client.CreateAlias(ctx, &pb.Alias{Alias: "docs", CollectionName: "docs-v1"})).GetResult())
+	require.EqualValues(t, 3, count("docs"))
+	// writes through an alias land in its collection
+	insertPoints(t, client, "docs", 1)
+	require.EqualValues(t, 4, count("docs-v1"))
+	search, err := client.Search(ctx, &pb.SearchReq{CollectionName: "docs", Vector: []float32{0, 0}, TopK: 10})
+	require.NoError(t, err)
+	require.Len(t, search.GetResponse(), 4)
+
+	for _, tc := range []struct {
+		err  error
+		code pb.ErrorCode
+	}{
+		{failed(client.CreateAlias(ctx, &pb.Alias{Alias: "docs", CollectionName: "docs-v2"})), pb.ErrorCode_ALREADY_EXISTS},
+		{failed(client.CreateAlias(ctx, &pb.Alias{Alias: "docs-v2", CollectionName: "docs-v1"})), pb.ErrorCode_ALREADY_EXISTS},
+		{failed(client.CreateAlias(ctx, &pb.Alias{Alias: "other", CollectionName: "docs"})), pb.ErrorCode_NOT_FOUND},
+		{failed(client.PointAlias(ctx, &pb.Alias{Alias: "missing", CollectionName: "docs-v2"})), pb.ErrorCode_NOT_FOUND},
+		{failed(client.DropCollection(ctx, &pb.CollectionName{CollectionName: "docs-v1"})), pb.ErrorCode_PRECONDITION_FAILED},
+		{failed(client.DropCollection(ctx, &pb.CollectionName{CollectionName: "docs"})), pb.ErrorCode_NOT_FOUND},
+	} {
+		requireCode(t, tc.err, tc.code)
+	}
+	_, err = client.CreateCollection(ctx, &pb.Collection{CollectionName: "docs", Dimension: 2})
+	requireCode(t, err, pb.ErrorCode_ALREADY_EXISTS)
+
+	require.True(t, change(client.PointAlias(ctx, &pb.Alias{Alias: "docs", CollectionName: "docs-v2"})).GetResult())
+	require.EqualValues(t, 5, count("docs"))
+	require.True(t, change(client.CreateAlias(ctx, &pb.Alias{Alias: "staging", CollectionName: "docs-v1"})).GetResult())
+	require.True(t, change(client.SwapAliases(ctx, &pb.SwapAliasesReq{Alias: "docs", OtherAlias: "staging"})).GetResult())
+	require.EqualValues(t, 4, count("docs"))
+	require.EqualValues(t, 5, count("staging"))
+	stop()
+
+	// aliases survive restarts
+	client, stop = startNode(t, dataDir)
+	defer stop()
+	aliases, err := client.ListAliases(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, aliases.GetAliases(), 2)
+	require.Equal(t, "docs", aliases.GetAliases()[0].GetAlias())
+	require.Equal(t, "docs-v1", aliases.GetAliases()[0].GetCollectionName())
+	require.EqualValues(t, 5, count("staging"))
+	require.True(t, change(client.DeleteAlias(ctx, &pb.AliasName{Alias: "staging"})).GetResult())
+	require.True(t, change(client.DropCollection(ctx, &pb.CollectionName{CollectionName: "docs-v2"})).GetResult())
+	_, err = client.Count(ctx, &pb.CountReq{CollectionName: "staging"})
+	requireCode(t, err, pb.ErrorCode_NOT_FOUND)
+}
+
+func TestVersionedWrites(t *testing.T) {
+	client, stop := startNode(t, t.TempDir())
+	defer stop()
+	ctx := context.Background()
+	_, err := client.CreateCollection(ctx, &pb.Collection{CollectionName: "docs", Dimension: 2})
+	require.NoError(t, err)
+	id := uuid.NewString()
+	get := func() *pb.Row {
+		resp, err := client.GetPoints(ctx, &pb.PointIds{CollectionName: "docs", Ids: []string{id}})
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+		if len(resp.GetPoints()) == 0 {
+			return nil
+		}
+		return resp.GetPoints()[0]
+	}
+	write := func(vector []float32, version uint64, origin string) {
+		loader, err := client.DataLoader(ctx)
+		require.NoError(t, err)
+		require.NoError(t, loader.Send(&pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: vector, Version: version, Origin: origin}))
+		resp, err := loader.Recv()
+		require.NoError(t, err)
+		require.True(t, resp.GetResult(), resp.GetErrorMessage())
+		require.NoError(t, loader.CloseSend())
+	}
+
+	// writes the node stamps itself always move the version forward
+	resp, err := client.Insert(ctx, &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{1, 1}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	first := get()
+	require.NotZero(t, first.GetVersion())
+	require.NotEmpty(t, first.GetOrigin())
+	resp, err = client.Update(ctx, &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{2, 2}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	require.Greater(t, get().GetVersion(), first.GetVersion())
+
+	// a versioned write ahead wins, older ones are acknowledged but ignored
+	// and equal timestamps are ordered by origin
+	future := uint64(hlc.New(time.Now().Add(hlc.MaxSkew/2), 0))
+	write([]float32{3, 3}, future, "b")
+	write([]float32{4, 4}, future-1, "z")
+	write([]float32{5, 5}, future, "a")
+	require.Equal(t, []float32{3, 3}, get().GetVector())
+	write([]float32{6, 6}, future, "c")
+	require.Equal(t, []float32{6, 6}, get().GetVector())
+	require.Equal(t, future, get().GetVersion())
+	require.Equal(t, "c", get().GetOrigin())
+
+	// versions too far ahead of the clock are refused
+	_, err = client.Update(ctx, &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{9, 9}, Version: math.MaxUint64, Origin: "z"})
+	requireCode(t, err, pb.ErrorCode_INVALID_ARGUMENT)
+	require.Equal(t, future, get().GetVersion())
+
+	// the tombstone of a newer delete keeps an older write from reviving it
+	delResp, err := client.Delete(ctx, &pb.DeleteDataset{Id: id, CollectionName: "docs", Version: future + 1, Origin: "a"})
+	require.NoError(t, err)
+	require.True(t, delResp.GetResult(), delResp.GetErrorMessage())
+	write([]float32{7, 7}, future, "d")
+	require.Nil(t, get())
+
+	// a local insert lands past the tombstone
+	resp, err = client.Insert(ctx, &pb.ModifyDataset{Id: id, CollectionName: "docs", Vector: []float32{8, 8}})
+	require.NoError(t, err)
+	require.True(t, resp.GetResult(), resp.GetErrorMessage())
+	require.Greater(t, get().GetVersion(), future+1)
+
+	// a handoff leaves no tombstone, the point may come back with the
+	// version it left with
+	moved := get()
+	delResp, err = client.Delete(ctx, &pb.DeleteDataset{Id: id, CollectionName: "docs", Handoff: true})
+	require.NoError(t, err)
+	require.True(t, delResp.GetResult(), delResp.GetErrorMessage())
+	require.Nil(t, get())
+	write(moved.GetVector(), moved.GetVersion(), moved.GetOrigin())
+	require.Equal(t, moved.GetVersion(), get().GetVersion())
+}
@@ -30,7 +30,7 @@ import (
 	"github.com/sjy-dv/nnv/pkg/flate"
 )
 
-var readOnlyConstraints error = errors.New("shard is read-only")
+var readOnlyConstraints error = fmt.Errorf("shard: %w", ErrReadOnly)
 
 type compressionMemStore struct {
 	data       map[string][]byte
@@ -243,6 +243,7 @@ func (self *compressionCdat) loadFromCache(path string) error {
 			if err != nil {
 				return fmt.Errorf("failed to create new cache file: %v", err)
 			}
+			return nil
 		}
 		return fmt.Errorf("failed to open cache file: %v", err)
 	}
@@ -19,6 +19,7 @@ package storage
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -38,11 +39,19 @@ func (self diskStore) Get(k []byte) []byte {
 }
 
 func (self diskStore) Put(k, v []byte) error {
-	return self.disk.Put(k, v)
+	return readOnly(self.disk.Put(k, v))
 }
 
 func (self diskStore) Delete(k []byte) error {
-	return self.disk.Delete(k)
+	return readOnly(self.disk.Delete(k))
+}
+
+// readOnly marks the errors bbolt returns for writes in a read transaction.
+func readOnly(err error) error {
+	if errors.Is(err, bbolt.ErrTxNotWritable) || errors.Is(err, bbolt.ErrDatabaseReadOnly) {
+		return fmt.Errorf("%w: %v", ErrReadOnly, err)
+	}
+	return err
 }
 
 func (self diskStore) ForEach(f func(k, v []byte) error) error {
@@ -121,7 +130,7 @@ func (self *diskStoreCoordinator) Delete(storageName string) error {
 	self.mu.Lock()
 	defer self.mu.Unlock()
 	if self.isReadOnly {
-		return fmt.Errorf("failed to delete storage (%s). read-only constraitns", storageName)
+		return fmt.Errorf("%w: failed to delete storage (%s)", ErrReadOnly, storageName)
 	}
 	return self.tx.DeleteBucket([]byte(storageName))
 }
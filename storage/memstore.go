@@ -30,7 +30,7 @@ func (b *memeStorage) Get(k []byte) []byte {
 
 func (b *memeStorage) Put(k, v []byte) error {
 	if b.isReadOnly {
-		return fmt.Errorf("cannot put into read-only memory bucket")
+		return fmt.Errorf("%w: cannot put into memory bucket", ErrReadOnly)
 	}
 	b.data[string(k)] = v
 	return nil
@@ -104,7 +104,7 @@ func (b *memeStorage) RangeScan(start, end []byte, inclusive bool, f func(k, v [
 
 func (b *memeStorage) Delete(k []byte) error {
 	if b.isReadOnly {
-		return fmt.Errorf("cannot delete in a read-only memory bucket")
+		return fmt.Errorf("%w: cannot delete in memory bucket", ErrReadOnly)
 	}
 	delete(b.data, string(k))
 	return nil
@@ -135,7 +135,7 @@ func (self *memeStorageManager) Delete(storageName string) error {
 	self.mu.Lock()
 	defer self.mu.Unlock()
 	if self.isReadOnly {
-		return fmt.Errorf("cannot delete %s in a read-only memory bucket manager", storageName)
+		return fmt.Errorf("%w: cannot delete %s in memory bucket manager", ErrReadOnly, storageName)
 	}
 	delete(self.storages, storageName)
 	return nil
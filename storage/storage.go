@@ -25,6 +25,9 @@ import (
 	"go.etcd.io/bbolt"
 )
 
+// ErrReadOnly is wrapped by every write a read-only storage refuses.
+var ErrReadOnly = errors.New("storage is read-only")
+
 type nullReadOnlyStorage struct{}
 
 func (nullReadOnlyStorage) IsReadOnly() bool {
@@ -48,11 +51,11 @@ func (nullReadOnlyStorage) RangeScan(start, end []byte, inclusive bool, f func(k
 }
 
 func (nullReadOnlyStorage) Put(k, v []byte) error {
-	return errors.New("cannot put into empty read-only storage")
+	return fmt.Errorf("%w: cannot put into empty storage", ErrReadOnly)
 }
 
 func (nullReadOnlyStorage) Delete(k []byte) error {
-	return errors.New("cannot delete from empty read-only storage")
+	return fmt.Errorf("%w: cannot delete from empty storage", ErrReadOnly)
 }
 
 type ReadOnlyStorage interface {